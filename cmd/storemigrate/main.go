@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/Alice00021/test_common/pkg/logger"
+	"github.com/Alice00021/test_common/pkg/mongodb"
+	"github.com/Alice00021/test_common/pkg/postgres"
+	"github.com/Alice00021/test_common/pkg/transactional"
+
+	"test_go/config"
+	"test_go/internal/entity"
+	"test_go/internal/repo"
+	mongorepo "test_go/internal/repo/mongodb"
+	"test_go/internal/repo/persistent"
+	"test_go/internal/usecase/storemigrate"
+)
+
+// storemigrate copies the commands and operations from one storage backend to the other:
+//
+//	storemigrate -from postgres -to mongo [-dry-run]
+//
+// Progress goes to the log, the report is printed to stdout as JSON. The exit code is 1
+// when the migration failed or the migrated records do not match the source.
+func main() {
+	from := flag.String("from", config.StorageBackendPostgres, "source backend: postgres or mongo")
+	to := flag.String("to", config.StorageBackendMongo, "target backend: postgres or mongo")
+	dryRun := flag.Bool("dry-run", false, "report what would be migrated without writing to the target")
+	flag.Parse()
+
+	for _, backend := range []string{*from, *to} {
+		if backend != config.StorageBackendPostgres && backend != config.StorageBackendMongo {
+			log.Fatalf("storemigrate: unknown backend %q, want %q or %q",
+				backend, config.StorageBackendPostgres, config.StorageBackendMongo)
+		}
+	}
+	if *from == *to {
+		log.Fatalf("storemigrate: -from and -to must differ, both are %q", *from)
+	}
+
+	os.Exit(run(*from, *to, *dryRun))
+}
+
+func run(from, to string, dryRun bool) int {
+	cfg, err := config.NewStoreMigrateConfig()
+	if err != nil {
+		log.Printf("Config error: %s", err)
+		return 1
+	}
+
+	l := logger.NewMultipleWriter(
+		logger.Level(cfg.Log.Level),
+		logger.FileName(cfg.Log.FileName),
+	)
+
+	pg, err := postgres.New(cfg.PG.URL, postgres.MaxPoolSize(cfg.PG.PoolMax))
+	if err != nil {
+		l.Error(fmt.Errorf("storemigrate - postgres.New: %w", err))
+		return 1
+	}
+	defer pg.Close()
+
+	mongoClient, err := mongodb.New(mongodb.Config{
+		URI:      cfg.MongoDB.URI,
+		Database: cfg.MongoDB.Database,
+		Timeout:  cfg.MongoDB.Timeout,
+	})
+	if err != nil {
+		l.Error(fmt.Errorf("storemigrate - mongodb.New: %w", err))
+		return 1
+	}
+	defer func() {
+		if err := mongoClient.Close(); err != nil {
+			l.Error(fmt.Errorf("mongodb close error: %w", err))
+		}
+	}()
+
+	ctx := context.Background()
+	if err := mongorepo.Bootstrap(ctx, mongoClient); err != nil {
+		l.Error(fmt.Errorf("storemigrate - mongorepo.Bootstrap: %w", err))
+		return 1
+	}
+
+	stores := map[string]repo.StoreMigrationRepo{
+		config.StorageBackendPostgres: persistent.NewStoreMigrationRepo(pg),
+		config.StorageBackendMongo:    mongorepo.NewStoreMigrationRepo(mongoClient),
+	}
+	// the postgres writes of a record share a transaction, the mongo writes do not use it
+	uc := storemigrate.New(transactional.NewPgTransaction(pg), stores[from], stores[to], l)
+	report, err := uc.Migrate(ctx, entity.StoreMigrationInput{From: from, To: to, DryRun: dryRun})
+	if report != nil {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(report)
+	}
+	if err != nil {
+		l.Error(fmt.Errorf("storemigrate - uc.Migrate: %w", err))
+		return 1
+	}
+
+	return 0
+}
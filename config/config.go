@@ -24,6 +24,10 @@ type (
 		LocalFileStorage LocalFileStorage
 		EmailConfig      EmailConfig
 		JWT              JWTConfig
+		Library          Library
+		CommandCatalog   CommandCatalog
+		Storage          Storage
+		Runs             Runs
 	}
 
 	// App -.
@@ -104,12 +108,45 @@ type (
 		VerifyBaseURL  string `env:"VERIFY_BASE_URL,required"`
 	}
 
+	// Library -.
+	Library struct {
+		AuthorDeletePolicy   string        `env:"LIBRARY_AUTHOR_DELETE_POLICY" envDefault:"restrict"`
+		TrashRetention       time.Duration `env:"LIBRARY_TRASH_RETENTION" envDefault:"720h"`
+		TrashPurgeInterval   time.Duration `env:"LIBRARY_TRASH_PURGE_INTERVAL" envDefault:"1h"`
+		ProfessionalMinBooks int64         `env:"LIBRARY_PROFESSIONAL_MIN_BOOKS" envDefault:"6"`
+		CoverMaxSize         int64         `env:"LIBRARY_COVER_MAX_SIZE" envDefault:"5242880"`
+		CoverThumbnailSizes  []int         `env:"LIBRARY_COVER_THUMBNAIL_SIZES" envDefault:"160,480"`
+		CoverMaxPixels       int           `env:"LIBRARY_COVER_MAX_PIXELS" envDefault:"25000000"`
+	}
+
+	// CommandCatalog -.
+	CommandCatalog struct {
+		WatchEnabled  bool          `env:"COMMAND_CATALOG_WATCH_ENABLED" envDefault:"false"`
+		WatchInterval time.Duration `env:"COMMAND_CATALOG_WATCH_INTERVAL" envDefault:"30s"`
+	}
+
+	// Storage - where commands and operations are kept, postgres or mongo.
+	Storage struct {
+		Backend string `env:"STORAGE_BACKEND" envDefault:"postgres"`
+	}
+
+	// Runs -.
+	Runs struct {
+		// SimulatorTimeUnit - what a unit of the average time of a command lasts in the simulator.
+		SimulatorTimeUnit time.Duration `env:"RUNS_SIMULATOR_TIME_UNIT" envDefault:"1s"`
+	}
+
 	// JWTConfig -.
 	JWTConfig struct {
 		SecretKey string `env:"JWT_SECRET_KEY,required"`
 	}
 )
 
+const (
+	StorageBackendPostgres = "postgres"
+	StorageBackendMongo    = "mongo"
+)
+
 // NewConfig returns app config.
 func NewConfig() (*Config, error) {
 	// Load .env file
@@ -123,5 +160,43 @@ func NewConfig() (*Config, error) {
 		return nil, fmt.Errorf("config error: %w", err)
 	}
 
+	switch cfg.Storage.Backend {
+	case StorageBackendPostgres, StorageBackendMongo:
+	default:
+		return nil, fmt.Errorf("config error: STORAGE_BACKEND must be %q or %q, got %q",
+			StorageBackendPostgres, StorageBackendMongo, cfg.Storage.Backend)
+	}
+
+	// the intervals drive tickers, which do not take a duration that is not positive
+	if cfg.Library.TrashPurgeInterval <= 0 {
+		return nil, fmt.Errorf("config error: LIBRARY_TRASH_PURGE_INTERVAL must be positive, got %s",
+			cfg.Library.TrashPurgeInterval)
+	}
+	if cfg.CommandCatalog.WatchEnabled && cfg.CommandCatalog.WatchInterval <= 0 {
+		return nil, fmt.Errorf("config error: COMMAND_CATALOG_WATCH_INTERVAL must be positive, got %s",
+			cfg.CommandCatalog.WatchInterval)
+	}
+
+	return cfg, nil
+}
+
+// StoreMigrateConfig - the part of the config the store migration needs.
+type StoreMigrateConfig struct {
+	Log     Log
+	PG      PG
+	MongoDB MongoDB
+}
+
+// NewStoreMigrateConfig returns the store migration config.
+func NewStoreMigrateConfig() (*StoreMigrateConfig, error) {
+	if err := godotenv.Load(); err != nil {
+		log.Println("Could not loading .env file")
+	}
+
+	cfg := &StoreMigrateConfig{}
+	if err := env.Parse(cfg); err != nil {
+		return nil, fmt.Errorf("config error: %w", err)
+	}
+
 	return cfg, nil
 }
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"github.com/Alice00021/test_common/pkg/rabbitmq/rmq_rpc/client"
 	"github.com/Alice00021/test_common/pkg/rabbitmq/rmq_rpc/server"
@@ -9,6 +10,7 @@ import (
 	"os/signal"
 	"syscall"
 	"test_go/internal/di"
+	mongorepo "test_go/internal/repo/mongodb"
 
 	"github.com/Alice00021/test_common/pkg/httpserver"
 	"github.com/Alice00021/test_common/pkg/logger"
@@ -52,6 +54,10 @@ func Run(cfg *config.Config) {
 		}
 	}()
 
+	if err := mongorepo.Bootstrap(context.Background(), mongoClient); err != nil {
+		l.Fatal(fmt.Errorf("app - Run - mongorepo.Bootstrap: %w", err))
+	}
+
 	// Transaction builder
 	pgTx := transactional.NewPgTransaction(pg)
 
@@ -62,11 +68,21 @@ func Run(cfg *config.Config) {
 	}
 
 	// Repo
-	repo := di.NewRepo(pg, mongoClient)
+	repo := di.NewRepo(pg, mongoClient, cfg.LocalFileStorage.BasePath)
 
 	// Use-Case
 	uc := di.NewUseCase(pgTx, repo, l, cfg)
 
+	// Background jobs
+	bgCtx, bgCancel := context.WithCancel(context.Background())
+	defer bgCancel()
+
+	go runTrashPurge(bgCtx, uc.Trash, cfg.Library.TrashPurgeInterval, l)
+	if cfg.CommandCatalog.WatchEnabled {
+		go runCommandCatalogWatch(bgCtx, uc.CommandWatcher, cfg.CommandCatalog.WatchInterval, l)
+	}
+	go runOperationRuns(bgCtx, uc.OperationRun, l)
+
 	// RabbitMQ RPC Server
 	rmqRouter := amqprpc.NewRouter(uc, l)
 
@@ -94,6 +110,8 @@ func Run(cfg *config.Config) {
 	}
 
 	// Shutdown
+	bgCancel()
+
 	err = httpServer.Shutdown()
 	if err != nil {
 		l.Error(fmt.Errorf("app - Run - httpServer.Shutdown: %w", err))
@@ -0,0 +1,27 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"github.com/Alice00021/test_common/pkg/logger"
+	"test_go/internal/usecase"
+	"time"
+)
+
+// runCommandCatalogWatch - checks the catalog file right away and then on every tick until ctx is cancelled.
+func runCommandCatalogWatch(ctx context.Context, uc usecase.CommandWatcher, interval time.Duration, l logger.Interface) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := uc.Check(ctx); err != nil {
+			l.Error(fmt.Errorf("app - runCommandCatalogWatch - uc.Check: %w", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
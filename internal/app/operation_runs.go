@@ -0,0 +1,15 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"github.com/Alice00021/test_common/pkg/logger"
+	"test_go/internal/usecase"
+)
+
+// runOperationRuns - executes the queued operation runs until ctx is cancelled.
+func runOperationRuns(ctx context.Context, uc usecase.OperationRun, l logger.Interface) {
+	if err := uc.Process(ctx); err != nil {
+		l.Error(fmt.Errorf("app - runOperationRuns - uc.Process: %w", err))
+	}
+}
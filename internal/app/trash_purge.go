@@ -0,0 +1,26 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"github.com/Alice00021/test_common/pkg/logger"
+	"test_go/internal/usecase"
+	"time"
+)
+
+// runTrashPurge - periodically removes expired records from trash until ctx is cancelled.
+func runTrashPurge(ctx context.Context, uc usecase.Trash, interval time.Duration, l logger.Interface) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := uc.PurgeExpired(ctx); err != nil {
+				l.Error(fmt.Errorf("app - runTrashPurge - uc.PurgeExpired: %w", err))
+			}
+		}
+	}
+}
@@ -15,30 +15,37 @@ import (
 )
 
 type authorRoutes struct {
-	uc usecase.Author
-	l  logger.Interface
+	uc     usecase.Author
+	authUc usecase.Auth
+	l      logger.Interface
 }
 
-func newAuthorRoutes(routes map[string]server.CallHandler, uc usecase.Author, l logger.Interface) {
-	r := &authorRoutes{uc, l}
+func newAuthorRoutes(routes map[string]server.CallHandler, uc usecase.Author, authUc usecase.Auth, l logger.Interface) {
+	r := &authorRoutes{uc, authUc, l}
 	{
 		routes["v1.createAuthor"] = r.createAuthor()
 		routes["v1.updateAuthor"] = r.updateAuthor()
 		routes["v1.getAuthor"] = r.getAuthor()
 		routes["v1.getAuthors"] = r.getAuthors()
+		routes["v1.getAuthorStats"] = r.getAuthorStats()
 		routes["v1.deleteAuthor"] = r.deleteAuthor()
 	}
 }
 
 func (r *authorRoutes) createAuthor() server.CallHandler {
 	return func(d *amqp.Delivery) (interface{}, error) {
+		user, err := optionalUser(d, r.authUc)
+		if err != nil {
+			return nil, err
+		}
+
 		var inp entity.CreateAuthorInput
 		if err := json.Unmarshal(d.Body, &inp); err != nil {
 			r.l.Error(err, "amqp_rpc - v1 - createAuthor")
 			return nil, rmqrpc.NewMessageError(rmqrpc.InvalidArgument, err)
 		}
 
-		res, err := r.uc.CreateAuthor(context.Background(), inp)
+		res, err := r.uc.CreateAuthor(context.Background(), user, inp)
 		if err != nil {
 			r.l.Error(err, "amqp_rpc - v1 - createAuthor")
 			return nil, rmqrpc.NewMessageError(rmqrpc.Internal, err)
@@ -50,17 +57,25 @@ func (r *authorRoutes) createAuthor() server.CallHandler {
 
 func (r *authorRoutes) updateAuthor() server.CallHandler {
 	return func(d *amqp.Delivery) (interface{}, error) {
+		user, err := optionalUser(d, r.authUc)
+		if err != nil {
+			return nil, err
+		}
+
 		var inp entity.UpdateAuthorInput
 		if err := json.Unmarshal(d.Body, &inp); err != nil {
 			r.l.Error(err, "amqp_rpc - v1 - updateAuthor")
 			return nil, rmqrpc.NewMessageError(rmqrpc.InvalidArgument, err)
 		}
 
-		err := r.uc.UpdateAuthor(context.Background(), inp)
+		err = r.uc.UpdateAuthor(context.Background(), user, inp)
 		if err != nil {
 			if errors.Is(err, entity.ErrAuthorNotFound) {
 				return nil, rmqrpc.NewMessageError(rmqrpc.NotFound, err)
 			}
+			if errors.Is(err, entity.ErrPatchFieldRequired) {
+				return nil, rmqrpc.NewMessageError(rmqrpc.InvalidArgument, err)
+			}
 
 			r.l.Error(err, "amqp_rpc - V1 - updateAuthor")
 			return nil, rmqrpc.NewMessageError(rmqrpc.Internal, err)
@@ -107,16 +122,25 @@ func (r *authorRoutes) getAuthors() server.CallHandler {
 
 func (r *authorRoutes) deleteAuthor() server.CallHandler {
 	return func(d *amqp.Delivery) (interface{}, error) {
-		var req request.IdRequest
-		if err := json.Unmarshal(d.Body, &req); err != nil {
+		user, err := optionalUser(d, r.authUc)
+		if err != nil {
+			return nil, err
+		}
+
+		var inp entity.DeleteAuthorInput
+		if err := json.Unmarshal(d.Body, &inp); err != nil {
 			r.l.Error(err, "amqp_rpc - V1 - deleteAuthor")
 			return nil, rmqrpc.NewMessageError(rmqrpc.InvalidArgument, err)
 		}
 
-		if err := r.uc.DeleteAuthor(context.Background(), req.ID); err != nil {
+		if err := r.uc.DeleteAuthor(context.Background(), user, inp); err != nil {
 			if errors.Is(err, entity.ErrAuthorNotFound) {
 				return nil, rmqrpc.NewMessageError(rmqrpc.NotFound, err)
 			}
+			if errors.Is(err, entity.ErrAuthorHasBooks) || errors.Is(err, entity.ErrInvalidDeletePolicy) ||
+				errors.Is(err, entity.ErrReassignAuthorRequired) {
+				return nil, rmqrpc.NewMessageError(rmqrpc.InvalidArgument, err)
+			}
 
 			r.l.Error(err, "amqp_rpc - V1 - deleteAuthor")
 			return nil, rmqrpc.NewMessageError(rmqrpc.Internal, err)
@@ -125,3 +149,25 @@ func (r *authorRoutes) deleteAuthor() server.CallHandler {
 		return nil, nil
 	}
 }
+
+func (r *authorRoutes) getAuthorStats() server.CallHandler {
+	return func(d *amqp.Delivery) (interface{}, error) {
+		var req request.IdRequest
+		if err := json.Unmarshal(d.Body, &req); err != nil {
+			r.l.Error(err, "amqp_rpc - V1 - getAuthorStats")
+			return nil, rmqrpc.NewMessageError(rmqrpc.InvalidArgument, err)
+		}
+
+		res, err := r.uc.GetAuthorStats(context.Background(), req.ID)
+		if err != nil {
+			if errors.Is(err, entity.ErrAuthorNotFound) {
+				return nil, rmqrpc.NewMessageError(rmqrpc.NotFound, err)
+			}
+
+			r.l.Error(err, "amqp_rpc - V1 - getAuthorStats")
+			return nil, rmqrpc.NewMessageError(rmqrpc.Internal, err)
+		}
+
+		return res, nil
+	}
+}
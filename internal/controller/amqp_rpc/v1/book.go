@@ -14,12 +14,13 @@ import (
 )
 
 type bookRoutes struct {
-	uc usecase.Book
-	l  logger.Interface
+	uc     usecase.Book
+	authUc usecase.Auth
+	l      logger.Interface
 }
 
-func newBookRoutes(routes map[string]server.CallHandler, uc usecase.Book, l logger.Interface) {
-	r := &bookRoutes{uc, l}
+func newBookRoutes(routes map[string]server.CallHandler, uc usecase.Book, authUc usecase.Auth, l logger.Interface) {
+	r := &bookRoutes{uc, authUc, l}
 	{
 		routes["v1.createBook"] = r.createBook()
 		routes["v1.updateBook"] = r.updateBook()
@@ -31,12 +32,19 @@ func newBookRoutes(routes map[string]server.CallHandler, uc usecase.Book, l logg
 
 func (r *bookRoutes) createBook() server.CallHandler {
 	return func(d *amqp.Delivery) (interface{}, error) {
+		user, err := currentUser(d, r.authUc)
+		if err != nil {
+			return nil, err
+		}
+
 		var inp entity.CreateBookInput
 		if err := json.Unmarshal(d.Body, &inp); err != nil {
 			r.l.Error(err, "amqp_rpc - v1 - createBook")
 			return nil, rmqrpc.NewMessageError(rmqrpc.InvalidArgument, err)
 		}
 
+		inp.CreatedBy = user.ID
+
 		res, err := r.uc.CreateBook(context.Background(), inp)
 		if err != nil {
 			r.l.Error(err, "amqp_rpc - v1 - createBook")
@@ -49,17 +57,28 @@ func (r *bookRoutes) createBook() server.CallHandler {
 
 func (r *bookRoutes) updateBook() server.CallHandler {
 	return func(d *amqp.Delivery) (interface{}, error) {
+		user, err := currentUser(d, r.authUc)
+		if err != nil {
+			return nil, err
+		}
+
 		var inp entity.UpdateBookInput
 		if err := json.Unmarshal(d.Body, &inp); err != nil {
 			r.l.Error(err, "amqp_rpc - v1 - updateBook")
 			return nil, rmqrpc.NewMessageError(rmqrpc.InvalidArgument, err)
 		}
 
-		err := r.uc.UpdateBook(context.Background(), inp)
+		err = r.uc.UpdateBook(context.Background(), user, inp)
 		if err != nil {
 			if errors.Is(err, entity.ErrBookNotFound) {
 				return nil, rmqrpc.NewMessageError(rmqrpc.NotFound, err)
 			}
+			if errors.Is(err, entity.ErrPatchFieldRequired) {
+				return nil, rmqrpc.NewMessageError(rmqrpc.InvalidArgument, err)
+			}
+			if errors.Is(err, entity.ErrAccessDenied) {
+				return nil, permissionDenied(err)
+			}
 
 			r.l.Error(err, "amqp_rpc - V1 - updateBook")
 			return nil, rmqrpc.NewMessageError(rmqrpc.Internal, err)
@@ -71,6 +90,10 @@ func (r *bookRoutes) updateBook() server.CallHandler {
 
 func (r *bookRoutes) getBook() server.CallHandler {
 	return func(d *amqp.Delivery) (interface{}, error) {
+		if _, err := currentUser(d, r.authUc); err != nil {
+			return nil, err
+		}
+
 		var req request.IdRequest
 		if err := json.Unmarshal(d.Body, &req); err != nil {
 			r.l.Error(err, "amqp_rpc - V1 - getBook")
@@ -79,7 +102,7 @@ func (r *bookRoutes) getBook() server.CallHandler {
 
 		res, err := r.uc.GetBook(context.Background(), req.ID)
 		if err != nil {
-			if errors.Is(err, entity.ErrAuthorNotFound) {
+			if errors.Is(err, entity.ErrBookNotFound) {
 				return nil, rmqrpc.NewMessageError(rmqrpc.NotFound, err)
 			}
 
@@ -93,6 +116,9 @@ func (r *bookRoutes) getBook() server.CallHandler {
 
 func (r *bookRoutes) getBooks() server.CallHandler {
 	return func(d *amqp.Delivery) (interface{}, error) {
+		if _, err := currentUser(d, r.authUc); err != nil {
+			return nil, err
+		}
 
 		res, err := r.uc.GetBooks(context.Background())
 		if err != nil {
@@ -106,16 +132,24 @@ func (r *bookRoutes) getBooks() server.CallHandler {
 
 func (r *bookRoutes) deleteBook() server.CallHandler {
 	return func(d *amqp.Delivery) (interface{}, error) {
+		user, err := currentUser(d, r.authUc)
+		if err != nil {
+			return nil, err
+		}
+
 		var req request.IdRequest
 		if err := json.Unmarshal(d.Body, &req); err != nil {
 			r.l.Error(err, "amqp_rpc - V1 - deleteBook")
 			return nil, rmqrpc.NewMessageError(rmqrpc.InvalidArgument, err)
 		}
 
-		if err := r.uc.DeleteBook(context.Background(), req.ID); err != nil {
+		if err := r.uc.DeleteBook(context.Background(), user, req.ID); err != nil {
 			if errors.Is(err, entity.ErrBookNotFound) {
 				return nil, rmqrpc.NewMessageError(rmqrpc.NotFound, err)
 			}
+			if errors.Is(err, entity.ErrAccessDenied) {
+				return nil, permissionDenied(err)
+			}
 
 			r.l.Error(err, "amqp_rpc - V1 - deleteBook")
 			return nil, rmqrpc.NewMessageError(rmqrpc.Internal, err)
@@ -33,6 +33,9 @@ func (r *commandRoutes) updateCommands() server.CallHandler {
 			if errors.Is(err, entity.ErrCommandNotFound) {
 				return nil, rmqrpc.NewMessageError(rmqrpc.NotFound, err)
 			}
+			if errors.Is(err, entity.ErrInvalidCommandCatalog) || errors.Is(err, entity.ErrCommandCatalogTooLarge) {
+				return nil, rmqrpc.NewMessageError(rmqrpc.InvalidArgument, err)
+			}
 
 			r.l.Error(err, "amqp_rpc - V1 - updateCommands")
 			return nil, rmqrpc.NewMessageError(rmqrpc.Internal, err)
@@ -45,7 +48,7 @@ func (r *commandRoutes) updateCommands() server.CallHandler {
 func (r *commandRoutes) getCommands() server.CallHandler {
 	return func(d *amqp.Delivery) (interface{}, error) {
 
-		res, err := r.uc.GetCommands(context.Background())
+		res, err := r.uc.GetCommands(context.Background(), entity.DefaultLocale)
 		if err != nil {
 			r.l.Error(err, "amqp_rpc - v1 - getCommands")
 			return nil, rmqrpc.NewMessageError(rmqrpc.Internal, err)
@@ -14,15 +14,17 @@ import (
 )
 
 type operationRoutes struct {
-	uc usecase.Operation
-	l  logger.Interface
+	uc     usecase.Operation
+	authUc usecase.Auth
+	l      logger.Interface
 }
 
-func newOperationRoutes(routes map[string]server.CallHandler, uc usecase.Operation, l logger.Interface) {
-	r := &operationRoutes{uc, l}
+func newOperationRoutes(routes map[string]server.CallHandler, uc usecase.Operation, authUc usecase.Auth, l logger.Interface) {
+	r := &operationRoutes{uc, authUc, l}
 	{
 		routes["v1.createOperation"] = r.createOperation()
 		routes["v1.updateOperation"] = r.updateOperation()
+		routes["v1.validateOperation"] = r.validateOperation()
 		routes["v1.getOperation"] = r.getOperation()
 		routes["v1.getOperations"] = r.getOperations()
 		routes["v1.deleteOperation"] = r.deleteOperation()
@@ -31,14 +33,26 @@ func newOperationRoutes(routes map[string]server.CallHandler, uc usecase.Operati
 
 func (r *operationRoutes) createOperation() server.CallHandler {
 	return func(d *amqp.Delivery) (interface{}, error) {
+		user, err := optionalUser(d, r.authUc)
+		if err != nil {
+			return nil, err
+		}
+
 		var inp entity.CreateOperationInput
 		if err := json.Unmarshal(d.Body, &inp); err != nil {
 			r.l.Error(err, "amqp_rpc - v1 - createOperation")
 			return nil, rmqrpc.NewMessageError(rmqrpc.InvalidArgument, err)
 		}
 
-		res, err := r.uc.CreateOperation(context.Background(), inp)
+		res, err := r.uc.CreateOperation(context.Background(), user, inp)
 		if err != nil {
+			if errors.Is(err, entity.ErrDeckProfileNotFound) {
+				return nil, rmqrpc.NewMessageError(rmqrpc.NotFound, err)
+			}
+			if errors.Is(err, entity.ErrInvalidOperation) {
+				return nil, rmqrpc.NewMessageError(rmqrpc.InvalidArgument, err)
+			}
+
 			r.l.Error(err, "amqp_rpc - v1 - createOperation")
 			return nil, rmqrpc.NewMessageError(rmqrpc.Internal, err)
 		}
@@ -47,19 +61,50 @@ func (r *operationRoutes) createOperation() server.CallHandler {
 	}
 }
 
-func (r *operationRoutes) updateOperation() server.CallHandler {
+// validateOperation - a dry run of createOperation, violations are part of the reply.
+func (r *operationRoutes) validateOperation() server.CallHandler {
 	return func(d *amqp.Delivery) (interface{}, error) {
-		var inp entity.UpdateOperationInput
+		var inp entity.ValidateOperationInput
 		if err := json.Unmarshal(d.Body, &inp); err != nil {
+			r.l.Error(err, "amqp_rpc - v1 - validateOperation")
+			return nil, rmqrpc.NewMessageError(rmqrpc.InvalidArgument, err)
+		}
+
+		res, err := r.uc.ValidateOperation(context.Background(), inp)
+		if err != nil {
+			if errors.Is(err, entity.ErrDeckProfileNotFound) {
+				return nil, rmqrpc.NewMessageError(rmqrpc.NotFound, err)
+			}
+
+			r.l.Error(err, "amqp_rpc - v1 - validateOperation")
+			return nil, rmqrpc.NewMessageError(rmqrpc.Internal, err)
+		}
+
+		return res, nil
+	}
+}
+
+func (r *operationRoutes) updateOperation() server.CallHandler {
+	return func(d *amqp.Delivery) (interface{}, error) {
+		user, err := optionalUser(d, r.authUc)
+		if err != nil {
+			return nil, err
+		}
+
+		var req request.UpdateOperationRequest
+		if err := json.Unmarshal(d.Body, &req); err != nil {
 			r.l.Error(err, "amqp_rpc - v1 - updateOperation")
 			return nil, rmqrpc.NewMessageError(rmqrpc.InvalidArgument, err)
 		}
 
-		err := r.uc.UpdateOperation(context.Background(), inp)
+		err = r.uc.UpdateOperation(context.Background(), user, req.ToEntity())
 		if err != nil {
-			if errors.Is(err, entity.ErrOperationNotFound) {
+			if errors.Is(err, entity.ErrOperationNotFound) || errors.Is(err, entity.ErrDeckProfileNotFound) {
 				return nil, rmqrpc.NewMessageError(rmqrpc.NotFound, err)
 			}
+			if errors.Is(err, entity.ErrPatchFieldRequired) || errors.Is(err, entity.ErrInvalidOperation) {
+				return nil, rmqrpc.NewMessageError(rmqrpc.InvalidArgument, err)
+			}
 
 			r.l.Error(err, "amqp_rpc - V1 - updateOperation")
 			return nil, rmqrpc.NewMessageError(rmqrpc.Internal, err)
@@ -71,13 +116,13 @@ func (r *operationRoutes) updateOperation() server.CallHandler {
 
 func (r *operationRoutes) getOperation() server.CallHandler {
 	return func(d *amqp.Delivery) (interface{}, error) {
-		var req request.IdRequest
+		var req request.OperationIdRequest
 		if err := json.Unmarshal(d.Body, &req); err != nil {
 			r.l.Error(err, "amqp_rpc - V1 - getOperation")
 			return nil, rmqrpc.NewMessageError(rmqrpc.InvalidArgument, err)
 		}
 
-		res, err := r.uc.GetOperation(context.Background(), req.ID)
+		res, err := r.uc.GetOperation(context.Background(), string(req.ID), entity.DefaultLocale)
 		if err != nil {
 			if errors.Is(err, entity.ErrOperationNotFound) {
 				return nil, rmqrpc.NewMessageError(rmqrpc.NotFound, err)
@@ -94,7 +139,7 @@ func (r *operationRoutes) getOperation() server.CallHandler {
 func (r *operationRoutes) getOperations() server.CallHandler {
 	return func(d *amqp.Delivery) (interface{}, error) {
 
-		res, err := r.uc.GetOperations(context.Background())
+		res, err := r.uc.GetOperations(context.Background(), entity.DefaultLocale)
 		if err != nil {
 			r.l.Error(err, "amqp_rpc - v1 - getOperations")
 			return nil, rmqrpc.NewMessageError(rmqrpc.Internal, err)
@@ -106,13 +151,18 @@ func (r *operationRoutes) getOperations() server.CallHandler {
 
 func (r *operationRoutes) deleteOperation() server.CallHandler {
 	return func(d *amqp.Delivery) (interface{}, error) {
-		var req request.IdRequest
+		user, err := optionalUser(d, r.authUc)
+		if err != nil {
+			return nil, err
+		}
+
+		var req request.OperationIdRequest
 		if err := json.Unmarshal(d.Body, &req); err != nil {
 			r.l.Error(err, "amqp_rpc - V1 - deleteOperation")
 			return nil, rmqrpc.NewMessageError(rmqrpc.InvalidArgument, err)
 		}
 
-		if err := r.uc.DeleteOperation(context.Background(), req.ID); err != nil {
+		if err := r.uc.DeleteOperation(context.Background(), user, string(req.ID)); err != nil {
 			if errors.Is(err, entity.ErrOperationNotFound) {
 				return nil, rmqrpc.NewMessageError(rmqrpc.NotFound, err)
 			}
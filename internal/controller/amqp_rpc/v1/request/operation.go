@@ -0,0 +1,39 @@
+package request
+
+import (
+	"encoding/json"
+	"test_go/internal/entity"
+)
+
+// OperationID - the opaque id of an operation, the numeric ids sent by older clients are accepted too.
+type OperationID string
+
+func (id *OperationID) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*id = OperationID(s)
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*id = OperationID(n.String())
+	return nil
+}
+
+type OperationIdRequest struct {
+	ID OperationID `json:"id"`
+}
+
+type UpdateOperationRequest struct {
+	entity.UpdateOperationInput
+	ID OperationID `json:"id"`
+}
+
+func (req *UpdateOperationRequest) ToEntity() entity.UpdateOperationInput {
+	inp := req.UpdateOperationInput
+	inp.ID = string(req.ID)
+	return inp
+}
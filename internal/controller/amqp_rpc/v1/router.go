@@ -8,8 +8,8 @@ import (
 
 func NewRouter(routes map[string]server.CallHandler, uc *di.UseCase, l logger.Interface) {
 	newAuthRoutes(routes, uc.Auth, l)
-	newAuthorRoutes(routes, uc.Author, l)
-	newBookRoutes(routes, uc.Book, l)
+	newAuthorRoutes(routes, uc.Author, uc.Auth, l)
+	newBookRoutes(routes, uc.Book, uc.Auth, l)
 	newCommandRoutes(routes, uc.Command, l)
-	newOperationRoutes(routes, uc.Operation, l)
+	newOperationRoutes(routes, uc.Operation, uc.Auth, l)
 }
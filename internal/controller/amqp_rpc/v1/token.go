@@ -0,0 +1,55 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	rmqrpc "github.com/Alice00021/test_common/pkg/rabbitmq/rmq_rpc"
+	"strings"
+	"test_go/internal/entity"
+	"test_go/internal/usecase"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const authorizationHeader = "Authorization"
+
+var (
+	errNoAccessToken = errors.New("message does not contain an access token")
+	// errPermissionDenied - the token is valid but does not allow the change, a fresh token does not help.
+	errPermissionDenied = errors.New("permission denied")
+)
+
+// currentUser - validates the "Authorization: Bearer <token>" message header.
+func currentUser(d *amqp.Delivery, uc usecase.Auth) (*entity.UserInfoToken, error) {
+	header, ok := d.Headers[authorizationHeader].(string)
+	if !ok {
+		return nil, rmqrpc.NewMessageError(rmqrpc.Unauthorized, errNoAccessToken)
+	}
+
+	t := strings.Split(header, " ")
+	if len(t) != 2 || t[0] != "Bearer" {
+		return nil, rmqrpc.NewMessageError(rmqrpc.Unauthorized, errNoAccessToken)
+	}
+
+	userInfo, err := uc.ValidateToken(context.Background(), t[1])
+	if err != nil {
+		return nil, rmqrpc.NewMessageError(rmqrpc.Unauthorized, err)
+	}
+
+	return userInfo, nil
+}
+
+// optionalUser - like currentUser, but a message without the header is made by the system and yields a nil user.
+func optionalUser(d *amqp.Delivery, uc usecase.Auth) (*entity.UserInfoToken, error) {
+	if _, ok := d.Headers[authorizationHeader]; !ok {
+		return nil, nil
+	}
+
+	return currentUser(d, uc)
+}
+
+// permissionDenied - the reply to entity.ErrAccessDenied, its message tells it apart from an invalid token.
+func permissionDenied(err error) error {
+	return rmqrpc.NewMessageError(rmqrpc.Unauthorized, fmt.Errorf("%w: %w", errPermissionDenied, err))
+}
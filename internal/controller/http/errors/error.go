@@ -8,6 +8,66 @@ import (
 	"test_go/internal/entity"
 )
 
+// statusByError - domain errors that are returned to the client as is.
+var statusByError = []struct {
+	err    error
+	status int
+}{
+	{entity.ErrUserNotFound, http.StatusNotFound},
+	{entity.ErrAuthorNotFound, http.StatusNotFound},
+	{entity.ErrBookNotFound, http.StatusNotFound},
+	{entity.ErrNotInTrash, http.StatusNotFound},
+	{entity.ErrAuthorHasBooks, http.StatusConflict},
+	{entity.ErrInvalidDeletePolicy, http.StatusBadRequest},
+	{entity.ErrReassignAuthorRequired, http.StatusBadRequest},
+	{entity.ErrUnsupportedImportFormat, http.StatusBadRequest},
+	{entity.ErrImportColumnNotFound, http.StatusBadRequest},
+	{entity.ErrInvalidImportMode, http.StatusBadRequest},
+	{entity.ErrEmptyImportFile, http.StatusBadRequest},
+	{entity.ErrImportFileTooLarge, http.StatusRequestEntityTooLarge},
+	{entity.ErrTooManyImportRows, http.StatusRequestEntityTooLarge},
+	{entity.ErrShelfNotFound, http.StatusNotFound},
+	{entity.ErrShelfBookNotFound, http.StatusNotFound},
+	{entity.ErrShelfNameUsed, http.StatusConflict},
+	{entity.ErrDefaultShelfDelete, http.StatusConflict},
+	{entity.ErrInvalidShelfProgress, http.StatusBadRequest},
+	{entity.ErrReviewNotFound, http.StatusNotFound},
+	{entity.ErrReviewAlreadyExists, http.StatusConflict},
+	{entity.ErrReviewAlreadyFlagged, http.StatusConflict},
+	{entity.ErrInvalidRating, http.StatusBadRequest},
+	{entity.ErrInvalidReviewStatus, http.StatusBadRequest},
+	{entity.ErrCoverNotFound, http.StatusNotFound},
+	{entity.ErrCoverTooLarge, http.StatusRequestEntityTooLarge},
+	{entity.ErrUnsupportedCoverType, http.StatusUnsupportedMediaType},
+	{entity.ErrRevisionNotFound, http.StatusNotFound},
+	{entity.ErrRevisionNotRestorable, http.StatusConflict},
+	{entity.ErrPatchFieldRequired, http.StatusBadRequest},
+	{entity.ErrUserNameUsed, http.StatusConflict},
+	{entity.ErrInvalidLocale, http.StatusBadRequest},
+	{entity.ErrInvalidCommandCatalog, http.StatusBadRequest},
+	{entity.ErrCommandCatalogTooLarge, http.StatusRequestEntityTooLarge},
+	{entity.ErrCommandNameNotFound, http.StatusBadRequest},
+	{entity.ErrCommandCatalogVersionNotFound, http.StatusNotFound},
+	{entity.ErrCommandNotFound, http.StatusNotFound},
+	{entity.ErrCommandAlreadyExists, http.StatusConflict},
+	{entity.ErrOperationNotFound, http.StatusNotFound},
+	{entity.ErrCommandDisabled, http.StatusConflict},
+	{entity.ErrInvalidCommandValue, http.StatusBadRequest},
+	{entity.ErrDeckProfileNotFound, http.StatusNotFound},
+	{entity.ErrDeckProfileAlreadyExists, http.StatusConflict},
+	{entity.ErrDeckProfileIsDefault, http.StatusConflict},
+	{entity.ErrDeckProfileInUse, http.StatusConflict},
+	{entity.ErrInvalidDeckProfile, http.StatusBadRequest},
+	{entity.ErrOperationRunNotFound, http.StatusNotFound},
+	{entity.ErrOperationHasNoCommands, http.StatusConflict},
+	{entity.ErrInvalidRunTransition, http.StatusConflict},
+	{entity.ErrInvalidRunState, http.StatusBadRequest},
+	{entity.ErrInvalidRunPriority, http.StatusBadRequest},
+	{entity.ErrInstrumentNotFound, http.StatusNotFound},
+	{entity.ErrInstrumentAlreadyExists, http.StatusConflict},
+	{entity.ErrQueueConflict, http.StatusConflict},
+}
+
 func ErrorResponse(c *gin.Context, err error) {
 	var httpErr httpError.HttpError
 	if errors.As(err, &httpErr) {
@@ -15,11 +75,38 @@ func ErrorResponse(c *gin.Context, err error) {
 		return
 	}
 
+	var catalogReport *entity.CommandCatalogReport
+	if errors.As(err, &catalogReport) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": catalogReport.Error(),
+			"issues":  catalogReport.Issues,
+		})
+		return
+	}
+
+	var operationReport *entity.OperationValidationReport
+	if errors.As(err, &operationReport) {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+			"status":     http.StatusUnprocessableEntity,
+			"message":    operationReport.Error(),
+			"violations": operationReport.Violations,
+		})
+		return
+	}
+
 	if errors.Is(err, entity.ErrAccessDenied) {
 		httpErr = httpError.NewForbiddenError(err.Error())
 		c.AbortWithStatusJSON(httpErr.Status, httpErr)
 		return
 	}
 
+	for _, e := range statusByError {
+		if errors.Is(err, e.err) {
+			c.AbortWithStatusJSON(e.status, gin.H{"status": e.status, "message": e.err.Error()})
+			return
+		}
+	}
+
 	c.AbortWithStatusJSON(http.StatusInternalServerError, httpError.NewInternalServerError(err))
 }
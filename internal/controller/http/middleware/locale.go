@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"strings"
+	"test_go/internal/entity"
+)
+
+const localeKey string = "x-locale"
+
+// LocaleMiddleware - resolves the response locale: the preferred Accept-Language tag,
+// then the locale of the user profile carried by the token, then the default one.
+func LocaleMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := parseAcceptLanguage(c.GetHeader("Accept-Language"))
+		if locale == "" {
+			if userInfo, err := GetCurrentUser(c); err == nil {
+				locale = userInfo.Locale
+			}
+		}
+		if locale == "" {
+			locale = entity.DefaultLocale
+		}
+
+		c.Set(localeKey, locale)
+		c.Next()
+	}
+}
+
+// GetLocale - the locale resolved by LocaleMiddleware.
+func GetLocale(c *gin.Context) string {
+	if locale, ok := c.Get(localeKey); ok {
+		if s, ok := locale.(string); ok {
+			return s
+		}
+	}
+	return entity.DefaultLocale
+}
+
+// parseAcceptLanguage - the valid tag with the highest weight, the wildcard is skipped.
+func parseAcceptLanguage(header string) string {
+	var (
+		best  string
+		bestQ float64
+	)
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || !entity.IsValidLocale(tag) {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > bestQ {
+			best, bestQ = tag, q
+		}
+	}
+	return best
+}
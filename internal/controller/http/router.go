@@ -47,17 +47,29 @@ func NewRouter(handler *gin.Engine, cfg *config.Config, l logger.Interface, uc *
 	publicV1Group := handler.Group("/v1")
 	{
 		v1.NewAuthRoutes(publicV1Group, l, uc.Auth)
+		v1.NewWsRoutes(publicV1Group, l, uc.Auth, uc.OperationRun)
 	}
 
 	privateV1Group := handler.Group("/v1")
-	v1.NewBookRoutes(privateV1Group, l, uc.Book)
-
 	privateV1Group.Use(middleware.JwtAuthMiddleware(uc.Auth))
 	{
+		v1.NewBookRoutes(privateV1Group, l, uc.Book)
+		v1.NewReviewRoutes(privateV1Group, l, uc.Review)
 		v1.NewUserRoutes(privateV1Group, l, uc.User)
-		v1.NewExportRoutes(privateV1Group, l, uc.Export)
+		v1.NewShelfRoutes(privateV1Group, l, uc.Shelf)
+		v1.NewImportRoutes(privateV1Group, l, uc.Import)
 		v1.NewAuthorRoutes(privateV1Group, l, uc.Author)
-		v1.NewCommandRoutes(privateV1Group, l, uc.Command)
-		v1.NewOperationRoutes(privateV1Group, l, uc.OperationMongo)
+		v1.NewTrashRoutes(privateV1Group, l, uc.Trash)
+		v1.NewDeckProfileRoutes(privateV1Group, l, uc.DeckProfile)
+		v1.NewOperationRunRoutes(privateV1Group, l, uc.OperationRun)
+		v1.NewInstrumentRoutes(privateV1Group, l, uc.Instrument, uc.OperationRun)
+	}
+
+	// Command names in these responses follow the locale of the request
+	localizedV1Group := privateV1Group.Group("", middleware.LocaleMiddleware())
+	{
+		v1.NewExportRoutes(localizedV1Group, l, uc.Export)
+		v1.NewCommandRoutes(localizedV1Group, l, uc.Command, uc.CommandWatcher)
+		v1.NewOperationRoutes(localizedV1Group, l, uc.Operation)
 	}
 }
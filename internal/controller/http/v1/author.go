@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"test_go/internal/controller/http/errors"
+	"test_go/internal/controller/http/middleware"
 	"test_go/internal/controller/http/v1/request"
 	"test_go/internal/usecase"
 	"test_go/internal/utils"
@@ -26,6 +27,12 @@ func NewAuthorRoutes(privateGroup *gin.RouterGroup, l logger.Interface, uc useca
 		h.GET("/:id", r.getAuthor)
 		h.GET("/", r.getAuthors)
 	}
+	{
+		h := privateGroup.Group("/authors")
+		h.GET("/:id/stats", r.getAuthorStats)
+		h.GET("/:id/history", r.getAuthorHistory)
+		h.POST("/:id/history/:revisionId/restore", r.restoreAuthorRevision)
+	}
 }
 
 func (r *authorRoutes) createAuthor(c *gin.Context) {
@@ -35,7 +42,13 @@ func (r *authorRoutes) createAuthor(c *gin.Context) {
 		errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
 		return
 	}
-	res, err := r.uc.CreateAuthor(c.Request.Context(), req.ToEntity())
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	res, err := r.uc.CreateAuthor(c.Request.Context(), currentUser, req.ToEntity())
 	if err != nil {
 		r.l.Error(err, "http - v1 - createAuthor")
 		errors.ErrorResponse(c, err)
@@ -59,10 +72,16 @@ func (r *authorRoutes) updateAuthor(c *gin.Context) {
 		errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
 		return
 	}
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
 	inp := req.ToEntity()
 	inp.ID = id
 
-	if err = r.uc.UpdateAuthor(c.Request.Context(), inp); err != nil {
+	if err = r.uc.UpdateAuthor(c.Request.Context(), currentUser, inp); err != nil {
 		r.l.Error(err, "http - v1 - updateAuthor")
 		errors.ErrorResponse(c, err)
 		return
@@ -79,7 +98,22 @@ func (r *authorRoutes) deleteAuthor(c *gin.Context) {
 		return
 	}
 
-	err = r.uc.DeleteAuthor(c.Request.Context(), id)
+	var req request.DeleteAuthorRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		r.l.Error(err, "http - v1 - deleteAuthor")
+		errors.ErrorResponse(c, httpError.NewBadQueryParamsError(err))
+		return
+	}
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	inp := req.ToEntity()
+	inp.ID = id
+
+	err = r.uc.DeleteAuthor(c.Request.Context(), currentUser, inp)
 	if err != nil {
 		r.l.Error(err, "http - v1 - deleteAuthor")
 		errors.ErrorResponse(c, err)
@@ -117,3 +151,70 @@ func (r *authorRoutes) getAuthors(c *gin.Context) {
 
 	c.JSON(http.StatusOK, res)
 }
+
+func (r *authorRoutes) getAuthorStats(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getAuthorStats")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	res, err := r.uc.GetAuthorStats(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getAuthorStats")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *authorRoutes) getAuthorHistory(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getAuthorHistory")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	res, err := r.uc.GetAuthorHistory(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getAuthorHistory")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *authorRoutes) restoreAuthorRevision(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - restoreAuthorRevision")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	revisionId, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "revisionId"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - restoreAuthorRevision")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	res, err := r.uc.RestoreAuthorRevision(c.Request.Context(), currentUser, id, revisionId)
+	if err != nil {
+		r.l.Error(err, "http - v1 - restoreAuthorRevision")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
@@ -5,7 +5,10 @@ import (
 	"github.com/Alice00021/test_common/pkg/logger"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"os"
+	"path/filepath"
 	"test_go/internal/controller/http/errors"
+	"test_go/internal/controller/http/middleware"
 	"test_go/internal/controller/http/v1/request"
 	"test_go/internal/usecase"
 	"test_go/internal/utils"
@@ -25,6 +28,11 @@ func NewBookRoutes(privateGroup *gin.RouterGroup, l logger.Interface, uc usecase
 		h.DELETE("/:id", r.deleteBook)
 		h.GET("/:id", r.getBook)
 		h.GET("/", r.getBooks)
+		h.PUT("/:id/cover", r.setCover)
+		h.DELETE("/:id/cover", r.deleteCover)
+		h.GET("/:id/cover", r.getCover)
+		h.GET("/:id/history", r.getBookHistory)
+		h.POST("/:id/history/:revisionId/restore", r.restoreBookRevision)
 	}
 }
 
@@ -35,7 +43,17 @@ func (r *bookRoutes) createBook(c *gin.Context) {
 		errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
 		return
 	}
-	res, err := r.uc.CreateBook(c.Request.Context(), req.ToEntity())
+
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	inp := req.ToEntity()
+	inp.CreatedBy = currentUser.ID
+
+	res, err := r.uc.CreateBook(c.Request.Context(), inp)
 	if err != nil {
 		r.l.Error(err, "http - v1 - createBook")
 		errors.ErrorResponse(c, err)
@@ -59,10 +77,16 @@ func (r *bookRoutes) updateBook(c *gin.Context) {
 		errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
 		return
 	}
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
 	inp := req.ToEntity()
 	inp.ID = id
 
-	if err = r.uc.UpdateBook(c.Request.Context(), inp); err != nil {
+	if err = r.uc.UpdateBook(c.Request.Context(), currentUser, inp); err != nil {
 		r.l.Error(err, "http - v1 - updateBook")
 		errors.ErrorResponse(c, err)
 		return
@@ -79,7 +103,13 @@ func (r *bookRoutes) deleteBook(c *gin.Context) {
 		return
 	}
 
-	err = r.uc.DeleteBook(c.Request.Context(), id)
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	err = r.uc.DeleteBook(c.Request.Context(), currentUser, id)
 	if err != nil {
 		r.l.Error(err, "http - v1 - deleteBook")
 		errors.ErrorResponse(c, err)
@@ -117,3 +147,143 @@ func (r *bookRoutes) getBooks(c *gin.Context) {
 
 	c.JSON(http.StatusOK, res)
 }
+
+func (r *bookRoutes) setCover(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - setCover")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	var req request.SetCoverRequest
+	if err = c.ShouldBind(&req); err != nil {
+		r.l.Error(err, "http - v1 - setCover")
+		errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
+		return
+	}
+
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	res, err := r.uc.SetCover(c.Request.Context(), currentUser, id, req.File)
+	if err != nil {
+		r.l.Error(err, "http - v1 - setCover")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *bookRoutes) deleteCover(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - deleteCover")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	if err = r.uc.DeleteCover(c.Request.Context(), currentUser, id); err != nil {
+		r.l.Error(err, "http - v1 - deleteCover")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// getCover - serves the cover with an ETag, http.ServeContent answers 304 to a matching If-None-Match.
+func (r *bookRoutes) getCover(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getCover")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	var req request.GetCoverRequest
+	if err = c.ShouldBindQuery(&req); err != nil {
+		r.l.Error(err, "http - v1 - getCover")
+		errors.ErrorResponse(c, httpError.NewBadQueryParamsError(err))
+		return
+	}
+
+	res, err := r.uc.GetCover(c.Request.Context(), id, req.Size)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getCover")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	f, err := os.Open(res.Path)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getCover")
+		errors.ErrorResponse(c, err)
+		return
+	}
+	defer f.Close()
+
+	c.Header("ETag", res.ETag)
+	c.Header("Cache-Control", "private, no-cache")
+	c.Header("Content-Type", res.ContentType)
+	http.ServeContent(c.Writer, c.Request, filepath.Base(res.Path), res.ModTime, f)
+}
+
+func (r *bookRoutes) getBookHistory(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getBookHistory")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	res, err := r.uc.GetBookHistory(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getBookHistory")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *bookRoutes) restoreBookRevision(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - restoreBookRevision")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	revisionId, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "revisionId"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - restoreBookRevision")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	res, err := r.uc.RestoreBookRevision(c.Request.Context(), currentUser, id, revisionId)
+	if err != nil {
+		r.l.Error(err, "http - v1 - restoreBookRevision")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
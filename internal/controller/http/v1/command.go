@@ -1,25 +1,48 @@
 package v1
 
 import (
+	httpError "github.com/Alice00021/test_common/pkg/httpserver"
 	"github.com/Alice00021/test_common/pkg/logger"
 	"github.com/gin-gonic/gin"
+	"io"
 	"net/http"
 	"test_go/internal/controller/http/errors"
+	"test_go/internal/controller/http/middleware"
+	"test_go/internal/controller/http/v1/request"
+	"test_go/internal/entity"
 	"test_go/internal/usecase"
+	"test_go/internal/utils"
 )
 
+// catalogFormOverhead - what a multipart form adds to the catalog file.
+const catalogFormOverhead = 64 << 10
+
 type commandRoutes struct {
-	l  logger.Interface
-	uc usecase.Command
+	l         logger.Interface
+	uc        usecase.Command
+	watcherUc usecase.CommandWatcher
 }
 
-func NewCommandRoutes(privateGroup *gin.RouterGroup, l logger.Interface, uc usecase.Command) {
-	r := &commandRoutes{l, uc}
+func NewCommandRoutes(privateGroup *gin.RouterGroup, l logger.Interface, uc usecase.Command, watcherUc usecase.CommandWatcher) {
+	r := &commandRoutes{l, uc, watcherUc}
 	{
 		h := privateGroup.Group("/commands")
 		h.GET("", r.getCommands)
+		h.GET("/:systemName", r.getCommand)
 		h.POST("", r.updateCommands)
 	}
+	{
+		h := privateGroup.Group("/commands", middleware.IsRoleMiddleware(entity.UserRoleAdmin))
+		h.POST("/import", r.importCommands)
+		h.GET("/watch/status", r.getWatchStatus)
+		h.GET("/versions", r.getCatalogVersions)
+		h.GET("/versions/compare", r.compareCatalogVersions)
+		h.GET("/versions/:id", r.getCatalogVersion)
+		h.POST("/versions/:id/rollback", r.rollbackCatalog)
+		h.PATCH("/:systemName", r.patchCommand)
+		h.POST("/:systemName/disable", r.disableCommand)
+		h.POST("/:systemName/enable", r.enableCommand)
+	}
 }
 
 func (r *commandRoutes) updateCommands(c *gin.Context) {
@@ -34,7 +57,7 @@ func (r *commandRoutes) updateCommands(c *gin.Context) {
 }
 
 func (r *commandRoutes) getCommands(c *gin.Context) {
-	res, err := r.uc.GetCommands(c.Request.Context())
+	res, err := r.uc.GetCommands(c.Request.Context(), middleware.GetLocale(c))
 	if err != nil {
 		r.l.Error(err, "http - v1 - getCommands")
 		errors.ErrorResponse(c, err)
@@ -43,3 +66,194 @@ func (r *commandRoutes) getCommands(c *gin.Context) {
 
 	c.JSON(http.StatusOK, res)
 }
+
+func (r *commandRoutes) getCommand(c *gin.Context) {
+	res, err := r.uc.GetCommand(c.Request.Context(), c.Param("systemName"), middleware.GetLocale(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - getCommand")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *commandRoutes) patchCommand(c *gin.Context) {
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		r.l.Error(err, "http - v1 - patchCommand")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	var req request.PatchCommandRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - patchCommand")
+		errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
+		return
+	}
+
+	res, err := r.uc.PatchCommand(c.Request.Context(), req.ToEntity(c.Param("systemName"), currentUser))
+	if err != nil {
+		r.l.Error(err, "http - v1 - patchCommand")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *commandRoutes) disableCommand(c *gin.Context) {
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		r.l.Error(err, "http - v1 - disableCommand")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	if err := r.uc.DisableCommand(c.Request.Context(), currentUser, c.Param("systemName"), true); err != nil {
+		r.l.Error(err, "http - v1 - disableCommand")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (r *commandRoutes) enableCommand(c *gin.Context) {
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		r.l.Error(err, "http - v1 - enableCommand")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	if err := r.uc.DisableCommand(c.Request.Context(), currentUser, c.Param("systemName"), false); err != nil {
+		r.l.Error(err, "http - v1 - enableCommand")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// importCommands - the catalog is either the "file" part of a multipart form or the json body itself.
+func (r *commandRoutes) importCommands(c *gin.Context) {
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		r.l.Error(err, "http - v1 - importCommands")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	var req request.ImportCommandsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		r.l.Error(err, "http - v1 - importCommands")
+		errors.ErrorResponse(c, httpError.NewBadQueryParamsError(err))
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, entity.CommandCatalogMaxSize+catalogFormOverhead)
+
+	var body io.Reader = c.Request.Body
+	if c.ContentType() == gin.MIMEMultipartPOSTForm {
+		header, err := c.FormFile("file")
+		if err != nil {
+			r.l.Error(err, "http - v1 - importCommands")
+			errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
+			return
+		}
+
+		file, err := header.Open()
+		if err != nil {
+			r.l.Error(err, "http - v1 - importCommands")
+			errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	res, err := r.uc.ImportCommands(c.Request.Context(), entity.ImportCommandsInput{
+		File:   body,
+		DryRun: req.DryRun,
+		User:   currentUser,
+	})
+	if err != nil {
+		r.l.Error(err, "http - v1 - importCommands")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// getWatchStatus - the state of the catalog file watcher, including the error of the last failed reload.
+func (r *commandRoutes) getWatchStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, r.watcherUc.Status())
+}
+
+func (r *commandRoutes) getCatalogVersions(c *gin.Context) {
+	res, err := r.uc.GetCatalogVersions(c.Request.Context())
+	if err != nil {
+		r.l.Error(err, "http - v1 - getCatalogVersions")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *commandRoutes) getCatalogVersion(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getCatalogVersion")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	res, err := r.uc.GetCatalogVersion(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getCatalogVersion")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *commandRoutes) compareCatalogVersions(c *gin.Context) {
+	var req request.CompareCommandCatalogVersionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		r.l.Error(err, "http - v1 - compareCatalogVersions")
+		errors.ErrorResponse(c, httpError.NewBadQueryParamsError(err))
+		return
+	}
+
+	res, err := r.uc.CompareCatalogVersions(c.Request.Context(), req.From, req.To)
+	if err != nil {
+		r.l.Error(err, "http - v1 - compareCatalogVersions")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *commandRoutes) rollbackCatalog(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - rollbackCatalog")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	res, err := r.uc.RollbackCatalog(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - rollbackCatalog")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
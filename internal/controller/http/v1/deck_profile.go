@@ -0,0 +1,168 @@
+package v1
+
+import (
+	httpError "github.com/Alice00021/test_common/pkg/httpserver"
+	"github.com/Alice00021/test_common/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"test_go/internal/controller/http/errors"
+	"test_go/internal/controller/http/middleware"
+	"test_go/internal/controller/http/v1/request"
+	"test_go/internal/entity"
+	"test_go/internal/usecase"
+	"test_go/internal/utils"
+)
+
+type deckProfileRoutes struct {
+	l  logger.Interface
+	uc usecase.DeckProfile
+}
+
+func NewDeckProfileRoutes(privateGroup *gin.RouterGroup, l logger.Interface, uc usecase.DeckProfile) {
+	r := &deckProfileRoutes{l, uc}
+	{
+		h := privateGroup.Group("/deck-profiles")
+		h.GET("", r.getDeckProfiles)
+		h.GET("/:id", r.getDeckProfile)
+	}
+	{
+		h := privateGroup.Group("/deck-profiles", middleware.IsRoleMiddleware(entity.UserRoleAdmin))
+		h.POST("", r.createDeckProfile)
+		h.PUT("/:id", r.updateDeckProfile)
+		h.PATCH("/:id", r.patchDeckProfile)
+		h.DELETE("/:id", r.deleteDeckProfile)
+		h.POST("/:id/default", r.setDefaultDeckProfile)
+	}
+}
+
+func (r *deckProfileRoutes) getDeckProfiles(c *gin.Context) {
+	res, err := r.uc.GetDeckProfiles(c.Request.Context())
+	if err != nil {
+		r.l.Error(err, "http - v1 - getDeckProfiles")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *deckProfileRoutes) getDeckProfile(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getDeckProfile")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	res, err := r.uc.GetDeckProfile(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getDeckProfile")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *deckProfileRoutes) createDeckProfile(c *gin.Context) {
+	var req request.CreateDeckProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - createDeckProfile")
+		errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
+		return
+	}
+
+	res, err := r.uc.CreateDeckProfile(c.Request.Context(), req.ToEntity())
+	if err != nil {
+		r.l.Error(err, "http - v1 - createDeckProfile")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+func (r *deckProfileRoutes) updateDeckProfile(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - updateDeckProfile")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	var req request.UpdateDeckProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - updateDeckProfile")
+		errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
+		return
+	}
+
+	res, err := r.uc.UpdateDeckProfile(c.Request.Context(), req.ToEntity(id))
+	if err != nil {
+		r.l.Error(err, "http - v1 - updateDeckProfile")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *deckProfileRoutes) patchDeckProfile(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - patchDeckProfile")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	var req request.PatchDeckProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - patchDeckProfile")
+		errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
+		return
+	}
+
+	res, err := r.uc.UpdateDeckProfile(c.Request.Context(), req.ToEntity(id))
+	if err != nil {
+		r.l.Error(err, "http - v1 - patchDeckProfile")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *deckProfileRoutes) deleteDeckProfile(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - deleteDeckProfile")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	if err := r.uc.DeleteDeckProfile(c.Request.Context(), id); err != nil {
+		r.l.Error(err, "http - v1 - deleteDeckProfile")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// setDefaultDeckProfile - operations without a profile are validated against this one from now on.
+func (r *deckProfileRoutes) setDefaultDeckProfile(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - setDefaultDeckProfile")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	if err := r.uc.SetDefaultDeckProfile(c.Request.Context(), id); err != nil {
+		r.l.Error(err, "http - v1 - setDefaultDeckProfile")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
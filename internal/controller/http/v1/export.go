@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"test_go/internal/controller/http/errors"
+	"test_go/internal/controller/http/middleware"
 	"test_go/internal/usecase"
 )
 
@@ -46,7 +47,7 @@ func (r *exportRoutes) generateExportFile(c *gin.Context) {
 }
 
 func (r *exportRoutes) exportCommandsToCSV(c *gin.Context) {
-	res, fileName, err := r.uc.ExportCommandsToCSV(c.Request.Context())
+	res, fileName, err := r.uc.ExportCommandsToCSV(c.Request.Context(), middleware.GetLocale(c))
 	if err != nil {
 		r.l.Error(err, "http - v1 - exportCommandsToCSV")
 		errors.ErrorResponse(c, err)
@@ -60,7 +61,7 @@ func (r *exportRoutes) exportCommandsToCSV(c *gin.Context) {
 }
 
 func (r *exportRoutes) exportCommandsToPDF(c *gin.Context) {
-	res, fileName, err := r.uc.ExportCommandsToPDF(c.Request.Context())
+	res, fileName, err := r.uc.ExportCommandsToPDF(c.Request.Context(), middleware.GetLocale(c))
 	if err != nil {
 		r.l.Error(err, "http - v1 - exportCommandsToPDF")
 		errors.ErrorResponse(c, err)
@@ -74,7 +75,7 @@ func (r *exportRoutes) exportCommandsToPDF(c *gin.Context) {
 }
 
 func (r *exportRoutes) exportOperationsToCSV(c *gin.Context) {
-	res, fileName, err := r.uc.ExportOperationsToCSV(c.Request.Context())
+	res, fileName, err := r.uc.ExportOperationsToCSV(c.Request.Context(), middleware.GetLocale(c))
 	if err != nil {
 		r.l.Error(err, "http - v1 - exportOperationsToCSV")
 		errors.ErrorResponse(c, err)
@@ -88,7 +89,7 @@ func (r *exportRoutes) exportOperationsToCSV(c *gin.Context) {
 }
 
 func (r *exportRoutes) exportOperationsToPDF(c *gin.Context) {
-	res, fileName, err := r.uc.ExportOperationsToPDF(c.Request.Context())
+	res, fileName, err := r.uc.ExportOperationsToPDF(c.Request.Context(), middleware.GetLocale(c))
 	if err != nil {
 		r.l.Error(err, "http - v1 - exportOperationsToPDF")
 		errors.ErrorResponse(c, err)
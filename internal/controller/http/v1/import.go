@@ -0,0 +1,58 @@
+package v1
+
+import (
+	httpError "github.com/Alice00021/test_common/pkg/httpserver"
+	"github.com/Alice00021/test_common/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"test_go/internal/controller/http/errors"
+	"test_go/internal/controller/http/middleware"
+	"test_go/internal/controller/http/v1/request"
+	"test_go/internal/entity"
+	"test_go/internal/usecase"
+)
+
+// importFormOverhead - what a multipart form adds to the imported file and its options.
+const importFormOverhead = 64 << 10
+
+type importRoutes struct {
+	l  logger.Interface
+	uc usecase.Import
+}
+
+func NewImportRoutes(privateGroup *gin.RouterGroup, l logger.Interface, uc usecase.Import) {
+	r := &importRoutes{l, uc}
+	{
+		h := privateGroup.Group("/import")
+		h.POST("/library", r.importLibrary)
+	}
+}
+
+func (r *importRoutes) importLibrary(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, entity.ImportMaxSize+importFormOverhead)
+
+	var req request.ImportLibraryRequest
+	if err := c.ShouldBind(&req); err != nil {
+		r.l.Error(err, "http - v1 - importLibrary")
+		errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
+		return
+	}
+
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	inp := req.ToEntity()
+	inp.User = currentUser
+
+	res, err := r.uc.ImportLibrary(c.Request.Context(), inp)
+	if err != nil {
+		r.l.Error(err, "http - v1 - importLibrary")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,126 @@
+package v1
+
+import (
+	httpError "github.com/Alice00021/test_common/pkg/httpserver"
+	"github.com/Alice00021/test_common/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"test_go/internal/controller/http/errors"
+	"test_go/internal/controller/http/middleware"
+	"test_go/internal/controller/http/v1/request"
+	"test_go/internal/entity"
+	"test_go/internal/usecase"
+	"test_go/internal/utils"
+)
+
+type instrumentRoutes struct {
+	l     logger.Interface
+	uc    usecase.Instrument
+	runUc usecase.OperationRun
+}
+
+func NewInstrumentRoutes(privateGroup *gin.RouterGroup, l logger.Interface, uc usecase.Instrument, runUc usecase.OperationRun) {
+	r := &instrumentRoutes{l, uc, runUc}
+	{
+		h := privateGroup.Group("/instruments")
+		h.GET("", r.getInstruments)
+		h.GET("/:id", r.getInstrument)
+		h.GET("/:id/queue", r.getQueue)
+	}
+	{
+		h := privateGroup.Group("/instruments", middleware.IsRoleMiddleware(entity.UserRoleAdmin))
+		h.POST("", r.createInstrument)
+		h.PUT("/:id/queue", r.reorderQueue)
+	}
+}
+
+func (r *instrumentRoutes) getInstruments(c *gin.Context) {
+	res, err := r.uc.GetInstruments(c.Request.Context())
+	if err != nil {
+		r.l.Error(err, "http - v1 - getInstruments")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *instrumentRoutes) getInstrument(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getInstrument")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	res, err := r.uc.GetInstrument(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getInstrument")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *instrumentRoutes) createInstrument(c *gin.Context) {
+	var req request.CreateInstrumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - createInstrument")
+		errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
+		return
+	}
+
+	res, err := r.uc.CreateInstrument(c.Request.Context(), req.ToEntity())
+	if err != nil {
+		r.l.Error(err, "http - v1 - createInstrument")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+// getQueue - the estimates are computed on every request, so they follow the progress of the runs.
+func (r *instrumentRoutes) getQueue(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getQueue")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	res, err := r.runUc.GetQueue(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getQueue")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *instrumentRoutes) reorderQueue(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - reorderQueue")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	var req request.ReorderQueueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - reorderQueue")
+		errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
+		return
+	}
+
+	res, err := r.runUc.ReorderQueue(c.Request.Context(), req.ToEntity(id))
+	if err != nil {
+		r.l.Error(err, "http - v1 - reorderQueue")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
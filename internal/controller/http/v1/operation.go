@@ -4,26 +4,32 @@ import (
 	httpError "github.com/Alice00021/test_common/pkg/httpserver"
 	"github.com/Alice00021/test_common/pkg/logger"
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"net/http"
 	"test_go/internal/controller/http/errors"
+	"test_go/internal/controller/http/middleware"
 	"test_go/internal/controller/http/v1/request"
 	"test_go/internal/usecase"
+	"test_go/internal/utils"
 )
 
 type operationRoutes struct {
 	l  logger.Interface
-	uc usecase.OperationMongo
+	uc usecase.Operation
 }
 
-func NewOperationRoutes(privateGroup *gin.RouterGroup, l logger.Interface, uc usecase.OperationMongo) {
+func NewOperationRoutes(privateGroup *gin.RouterGroup, l logger.Interface, uc usecase.Operation) {
 	r := &operationRoutes{l, uc}
 	{
 		h := privateGroup.Group("/operation")
 		h.GET("", r.getOperations)
+		h.GET("/:id", r.getOperation)
 		h.POST("", r.createOperation)
+		h.POST("/validate", r.validateOperation)
 		h.PUT("/:id", r.updateOperation)
+		h.PATCH("/:id", r.patchOperation)
 		h.DELETE("/:id", r.deleteOperation)
+		h.GET("/:id/history", r.getOperationHistory)
+		h.POST("/:id/history/:revisionId/restore", r.restoreOperationRevision)
 	}
 }
 
@@ -34,7 +40,13 @@ func (r *operationRoutes) createOperation(c *gin.Context) {
 		errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
 		return
 	}
-	res, err := r.uc.CreateOperation(c.Request.Context(), req.ToEntity())
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	res, err := r.uc.CreateOperation(c.Request.Context(), currentUser, req.ToEntity())
 	if err != nil {
 		r.l.Error(err, "http - v1 - createOperation")
 		errors.ErrorResponse(c, err)
@@ -44,26 +56,42 @@ func (r *operationRoutes) createOperation(c *gin.Context) {
 	c.JSON(http.StatusCreated, res)
 }
 
-func (r *operationRoutes) updateOperation(c *gin.Context) {
-	idStr := c.Param("id")
+// validateOperation - a dry run of createOperation, violations are part of the 200 response.
+func (r *operationRoutes) validateOperation(c *gin.Context) {
+	var req request.ValidateOperationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - validateOperation")
+		errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
+		return
+	}
 
-	id, err := primitive.ObjectIDFromHex(idStr)
+	res, err := r.uc.ValidateOperation(c.Request.Context(), req.ToEntity())
 	if err != nil {
-		r.l.Error(err, "http - v1 - updateOperation")
-		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		r.l.Error(err, "http - v1 - validateOperation")
+		errors.ErrorResponse(c, err)
 		return
 	}
 
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *operationRoutes) updateOperation(c *gin.Context) {
 	var req request.UpdateOperationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		r.l.Error(err, "http - v1 - updateOperation")
 		errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
 		return
 	}
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
 	inp := req.ToEntity()
-	inp.ID = id
+	inp.ID = c.Param("id")
 
-	if err = r.uc.UpdateOperation(c.Request.Context(), inp); err != nil {
+	if err = r.uc.UpdateOperation(c.Request.Context(), currentUser, inp); err != nil {
 		r.l.Error(err, "http - v1 - updateOperation")
 		errors.ErrorResponse(c, err)
 		return
@@ -72,17 +100,40 @@ func (r *operationRoutes) updateOperation(c *gin.Context) {
 	c.Status(http.StatusOK)
 }
 
-func (r *operationRoutes) deleteOperation(c *gin.Context) {
-	idStr := c.Param("id")
+func (r *operationRoutes) patchOperation(c *gin.Context) {
+	var req request.PatchOperationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - patchOperation")
+		errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
+		return
+	}
 
-	id, err := primitive.ObjectIDFromHex(idStr)
+	currentUser, err := middleware.GetCurrentUser(c)
 	if err != nil {
-		r.l.Error(err, "http - v1 - deleteOperation")
-		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		errors.ErrorResponse(c, err)
 		return
 	}
 
-	err = r.uc.DeleteOperation(c.Request.Context(), id)
+	inp := req.ToEntity()
+	inp.ID = c.Param("id")
+
+	if err = r.uc.UpdateOperation(c.Request.Context(), currentUser, inp); err != nil {
+		r.l.Error(err, "http - v1 - patchOperation")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (r *operationRoutes) deleteOperation(c *gin.Context) {
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	err = r.uc.DeleteOperation(c.Request.Context(), currentUser, c.Param("id"))
 	if err != nil {
 		r.l.Error(err, "http - v1 - deleteOperation")
 		errors.ErrorResponse(c, err)
@@ -93,7 +144,7 @@ func (r *operationRoutes) deleteOperation(c *gin.Context) {
 }
 
 func (r *operationRoutes) getOperations(c *gin.Context) {
-	res, err := r.uc.GetOperations(c.Request.Context())
+	res, err := r.uc.GetOperations(c.Request.Context(), middleware.GetLocale(c))
 	if err != nil {
 		r.l.Error(err, "http - v1 - getOperations")
 		errors.ErrorResponse(c, err)
@@ -102,3 +153,49 @@ func (r *operationRoutes) getOperations(c *gin.Context) {
 
 	c.JSON(http.StatusOK, res)
 }
+
+func (r *operationRoutes) getOperation(c *gin.Context) {
+	res, err := r.uc.GetOperation(c.Request.Context(), c.Param("id"), middleware.GetLocale(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - getOperation")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *operationRoutes) getOperationHistory(c *gin.Context) {
+	res, err := r.uc.GetOperationHistory(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - getOperationHistory")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *operationRoutes) restoreOperationRevision(c *gin.Context) {
+	revisionId, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "revisionId"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - restoreOperationRevision")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	res, err := r.uc.RestoreOperationRevision(c.Request.Context(), currentUser, c.Param("id"), revisionId)
+	if err != nil {
+		r.l.Error(err, "http - v1 - restoreOperationRevision")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,147 @@
+package v1
+
+import (
+	httpError "github.com/Alice00021/test_common/pkg/httpserver"
+	"github.com/Alice00021/test_common/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"test_go/internal/controller/http/errors"
+	"test_go/internal/controller/http/middleware"
+	"test_go/internal/controller/http/v1/request"
+	"test_go/internal/usecase"
+	"test_go/internal/utils"
+)
+
+type operationRunRoutes struct {
+	l  logger.Interface
+	uc usecase.OperationRun
+}
+
+func NewOperationRunRoutes(privateGroup *gin.RouterGroup, l logger.Interface, uc usecase.OperationRun) {
+	r := &operationRunRoutes{l, uc}
+	{
+		h := privateGroup.Group("/runs")
+		h.GET("", r.getRuns)
+		h.GET("/:id", r.getRun)
+		h.POST("", r.startRun)
+		h.POST("/:id/pause", r.pauseRun)
+		h.POST("/:id/resume", r.resumeRun)
+		h.POST("/:id/abort", r.abortRun)
+	}
+}
+
+// startRun - queues the run, its progress is read from getRun.
+func (r *operationRunRoutes) startRun(c *gin.Context) {
+	var req request.StartOperationRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - startRun")
+		errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
+		return
+	}
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	res, err := r.uc.StartRun(c.Request.Context(), currentUser, req.ToEntity())
+	if err != nil {
+		r.l.Error(err, "http - v1 - startRun")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+func (r *operationRunRoutes) getRuns(c *gin.Context) {
+	var req request.GetOperationRunsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		r.l.Error(err, "http - v1 - getRuns")
+		errors.ErrorResponse(c, httpError.NewBadQueryParamsError(err))
+		return
+	}
+
+	res, err := r.uc.GetRuns(c.Request.Context(), req.ToEntity())
+	if err != nil {
+		r.l.Error(err, "http - v1 - getRuns")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *operationRunRoutes) getRun(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getRun")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	res, err := r.uc.GetRun(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getRun")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// pauseRun - the step being executed is finished, the next one waits for resumeRun.
+func (r *operationRunRoutes) pauseRun(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - pauseRun")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	res, err := r.uc.PauseRun(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - pauseRun")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *operationRunRoutes) resumeRun(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - resumeRun")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	res, err := r.uc.ResumeRun(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - resumeRun")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// abortRun - stops the step being executed, a queued run never starts.
+func (r *operationRunRoutes) abortRun(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - abortRun")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	res, err := r.uc.AbortRun(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - abortRun")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
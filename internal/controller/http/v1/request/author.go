@@ -14,12 +14,27 @@ func (req *CreateAuthorRequest) ToEntity() entity.CreateAuthorInput {
 	}
 }
 
+// UpdateAuthorRequest - a JSON merge patch, absent members stay unchanged.
 type UpdateAuthorRequest struct {
-	Name string `json:"name" validate:"required"`
+	Name   entity.PatchField[string] `json:"name"`
+	Gender entity.PatchField[bool]   `json:"gender"`
 }
 
 func (req *UpdateAuthorRequest) ToEntity() entity.UpdateAuthorInput {
 	return entity.UpdateAuthorInput{
-		Name: req.Name,
+		Name:   req.Name,
+		Gender: req.Gender,
+	}
+}
+
+type DeleteAuthorRequest struct {
+	Policy     *entity.AuthorDeletePolicy `form:"policy"`
+	ReassignTo *int64                     `form:"reassignTo"`
+}
+
+func (req *DeleteAuthorRequest) ToEntity() entity.DeleteAuthorInput {
+	return entity.DeleteAuthorInput{
+		Policy:     req.Policy,
+		ReassignTo: req.ReassignTo,
 	}
 }
@@ -1,6 +1,9 @@
 package request
 
-import "test_go/internal/entity"
+import (
+	"mime/multipart"
+	"test_go/internal/entity"
+)
 
 type CreateBookRequest struct {
 	Title    string `json:"title" validate:"required"`
@@ -14,8 +17,10 @@ func (req *CreateBookRequest) ToEntity() entity.CreateBookInput {
 	}
 }
 
+// UpdateBookRequest - a JSON merge patch, absent members stay unchanged.
 type UpdateBookRequest struct {
-	CreateBookRequest
+	Title    entity.PatchField[string] `json:"title"`
+	AuthorId entity.PatchField[int64]  `json:"authorId"`
 }
 
 func (req *UpdateBookRequest) ToEntity() entity.UpdateBookInput {
@@ -24,3 +29,11 @@ func (req *UpdateBookRequest) ToEntity() entity.UpdateBookInput {
 		AuthorId: req.AuthorId,
 	}
 }
+
+type SetCoverRequest struct {
+	File *multipart.FileHeader `form:"file" binding:"required"`
+}
+
+type GetCoverRequest struct {
+	Size int `form:"size" binding:"omitempty,min=1"`
+}
@@ -0,0 +1,27 @@
+package request
+
+import "test_go/internal/entity"
+
+type CompareCommandCatalogVersionsRequest struct {
+	From int64 `form:"from" binding:"required"`
+	To   int64 `form:"to" binding:"required"`
+}
+
+// PatchCommandRequest - a JSON merge patch of the timing and volumes of a command.
+type PatchCommandRequest struct {
+	AverageTime      entity.PatchField[int64] `json:"averageTime"`
+	VolumeWaste      entity.PatchField[int64] `json:"volumeWaste"`
+	VolumeDriveFluid entity.PatchField[int64] `json:"volumeDriveFluid"`
+	VolumeContainer  entity.PatchField[int64] `json:"volumeContainer"`
+}
+
+func (req *PatchCommandRequest) ToEntity(systemName string, user *entity.UserInfoToken) entity.PatchCommandInput {
+	return entity.PatchCommandInput{
+		SystemName:       systemName,
+		AverageTime:      req.AverageTime,
+		VolumeWaste:      req.VolumeWaste,
+		VolumeDriveFluid: req.VolumeDriveFluid,
+		VolumeContainer:  req.VolumeContainer,
+		User:             user,
+	}
+}
@@ -0,0 +1,43 @@
+package request
+
+import "test_go/internal/entity"
+
+type CreateDeckProfileRequest struct {
+	Name      string                `json:"name" binding:"required"`
+	Positions []entity.DeckPosition `json:"positions" binding:"required"`
+}
+
+func (req *CreateDeckProfileRequest) ToEntity() entity.CreateDeckProfileInput {
+	return entity.CreateDeckProfileInput{
+		Name:      req.Name,
+		Positions: req.Positions,
+	}
+}
+
+type UpdateDeckProfileRequest struct {
+	Name      string                `json:"name" binding:"required"`
+	Positions []entity.DeckPosition `json:"positions" binding:"required"`
+}
+
+// ToEntity - a full update is a merge patch that carries every field.
+func (req *UpdateDeckProfileRequest) ToEntity(id int64) entity.UpdateDeckProfileInput {
+	return entity.UpdateDeckProfileInput{
+		ID:        id,
+		Name:      entity.NewPatchField(req.Name),
+		Positions: entity.NewPatchField(req.Positions),
+	}
+}
+
+// PatchDeckProfileRequest - a JSON merge patch, positions are replaced as a whole.
+type PatchDeckProfileRequest struct {
+	Name      entity.PatchField[string]                `json:"name"`
+	Positions entity.PatchField[[]entity.DeckPosition] `json:"positions"`
+}
+
+func (req *PatchDeckProfileRequest) ToEntity(id int64) entity.UpdateDeckProfileInput {
+	return entity.UpdateDeckProfileInput{
+		ID:        id,
+		Name:      req.Name,
+		Positions: req.Positions,
+	}
+}
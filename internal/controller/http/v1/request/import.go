@@ -0,0 +1,43 @@
+package request
+
+import (
+	"mime/multipart"
+	"test_go/internal/entity"
+)
+
+type ImportLibraryRequest struct {
+	File         *multipart.FileHeader `form:"file" binding:"required"`
+	Mode         entity.ImportMode     `form:"mode"`
+	DryRun       bool                  `form:"dryRun"`
+	BookIDColumn string                `form:"bookIdColumn"`
+	TitleColumn  string                `form:"titleColumn"`
+	AuthorColumn string                `form:"authorColumn"`
+	GenderColumn string                `form:"genderColumn"`
+}
+
+func (req *ImportLibraryRequest) ToEntity() entity.ImportLibraryInput {
+	mapping := entity.DefaultImportColumnMapping()
+	if req.BookIDColumn != "" {
+		mapping.BookID = req.BookIDColumn
+	}
+	if req.TitleColumn != "" {
+		mapping.Title = req.TitleColumn
+	}
+	if req.AuthorColumn != "" {
+		mapping.Author = req.AuthorColumn
+	}
+	if req.GenderColumn != "" {
+		mapping.Gender = req.GenderColumn
+	}
+
+	return entity.ImportLibraryInput{
+		File:    req.File,
+		Mapping: mapping,
+		Mode:    req.Mode,
+		DryRun:  req.DryRun,
+	}
+}
+
+type ImportCommandsRequest struct {
+	DryRun bool `form:"dryRun"`
+}
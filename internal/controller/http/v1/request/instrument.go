@@ -0,0 +1,27 @@
+package request
+
+import "test_go/internal/entity"
+
+type CreateInstrumentRequest struct {
+	Name          string `json:"name" binding:"required"`
+	DeckProfileID *int64 `json:"deckProfileId"`
+}
+
+func (req *CreateInstrumentRequest) ToEntity() entity.CreateInstrumentInput {
+	return entity.CreateInstrumentInput{
+		Name:          req.Name,
+		DeckProfileID: req.DeckProfileID,
+	}
+}
+
+// ReorderQueueRequest - the IDs of all the queued runs of the instrument in the new order.
+type ReorderQueueRequest struct {
+	RunIDs []int64 `json:"runIds" binding:"required"`
+}
+
+func (req *ReorderQueueRequest) ToEntity(instrumentID int64) entity.ReorderQueueInput {
+	return entity.ReorderQueueInput{
+		InstrumentID: instrumentID,
+		RunIDs:       req.RunIDs,
+	}
+}
@@ -3,29 +3,63 @@ package request
 import "test_go/internal/entity"
 
 type CreateOperationRequest struct {
-	Name        string                 `json:"name"`
-	Description string                 `json:"description"`
-	Commands    []*entity.CommandInput `json:"commands"`
+	Name          string                 `json:"name"`
+	Description   string                 `json:"description"`
+	Commands      []*entity.CommandInput `json:"commands"`
+	DeckProfileID *int64                 `json:"deckProfileId"`
 }
 
 func (req *CreateOperationRequest) ToEntity() entity.CreateOperationInput {
 	return entity.CreateOperationInput{
-		Name:        req.Name,
-		Description: req.Description,
-		Commands:    req.Commands,
+		Name:          req.Name,
+		Description:   req.Description,
+		Commands:      req.Commands,
+		DeckProfileID: req.DeckProfileID,
 	}
 }
 
 type UpdateOperationRequest struct {
-	Name        string                 `json:"name"`
-	Description string                 `json:"description"`
-	Commands    []*entity.CommandInput `json:"commands"`
+	Name          string                 `json:"name"`
+	Description   string                 `json:"description"`
+	Commands      []*entity.CommandInput `json:"commands"`
+	DeckProfileID *int64                 `json:"deckProfileId"`
 }
 
-func (req *UpdateOperationRequest) ToEntity() entity.UpdateOperationInputMongo {
-	return entity.UpdateOperationInputMongo{
-		Name:        req.Name,
-		Description: req.Description,
-		Commands:    req.Commands,
+// ToEntity - a full update is a merge patch that carries every field.
+func (req *UpdateOperationRequest) ToEntity() entity.UpdateOperationInput {
+	return entity.UpdateOperationInput{
+		Name:          entity.NewPatchField(req.Name),
+		Description:   entity.NewPatchField(req.Description),
+		Commands:      entity.NewPatchField(req.Commands),
+		DeckProfileID: entity.NewPatchField(req.DeckProfileID),
+	}
+}
+
+// PatchOperationRequest - a JSON merge patch, commands are replaced as a whole.
+type PatchOperationRequest struct {
+	Name          entity.PatchField[string]                 `json:"name"`
+	Description   entity.PatchField[string]                 `json:"description"`
+	Commands      entity.PatchField[[]*entity.CommandInput] `json:"commands"`
+	DeckProfileID entity.PatchField[*int64]                 `json:"deckProfileId"`
+}
+
+func (req *PatchOperationRequest) ToEntity() entity.UpdateOperationInput {
+	return entity.UpdateOperationInput{
+		Name:          req.Name,
+		Description:   req.Description,
+		Commands:      req.Commands,
+		DeckProfileID: req.DeckProfileID,
+	}
+}
+
+type ValidateOperationRequest struct {
+	Commands      []*entity.CommandInput `json:"commands"`
+	DeckProfileID *int64                 `json:"deckProfileId"`
+}
+
+func (req *ValidateOperationRequest) ToEntity() entity.ValidateOperationInput {
+	return entity.ValidateOperationInput{
+		Commands:      req.Commands,
+		DeckProfileID: req.DeckProfileID,
 	}
 }
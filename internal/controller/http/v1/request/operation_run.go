@@ -0,0 +1,31 @@
+package request
+
+import "test_go/internal/entity"
+
+type StartOperationRunRequest struct {
+	OperationID  string `json:"operationId" binding:"required"`
+	InstrumentID int64  `json:"instrumentId" binding:"required"`
+	Priority     int    `json:"priority"`
+}
+
+func (req *StartOperationRunRequest) ToEntity() entity.StartOperationRunInput {
+	return entity.StartOperationRunInput{
+		OperationID:  req.OperationID,
+		InstrumentID: req.InstrumentID,
+		Priority:     req.Priority,
+	}
+}
+
+type GetOperationRunsRequest struct {
+	OperationID  string `form:"operationId"`
+	InstrumentID int64  `form:"instrumentId"`
+	State        string `form:"state"`
+}
+
+func (req *GetOperationRunsRequest) ToEntity() entity.OperationRunFilter {
+	return entity.OperationRunFilter{
+		OperationID:  req.OperationID,
+		InstrumentID: req.InstrumentID,
+		State:        entity.OperationRunState(req.State),
+	}
+}
@@ -0,0 +1,59 @@
+package request
+
+import "test_go/internal/entity"
+
+type ListReviewsRequest struct {
+	Page  int `form:"page" binding:"omitempty,min=1"`
+	Limit int `form:"limit" binding:"omitempty,min=1"`
+}
+
+func (req *ListReviewsRequest) ToEntity() entity.FilterReviewInput {
+	return entity.FilterReviewInput{
+		Page:  req.Page,
+		Limit: req.Limit,
+	}
+}
+
+type CreateReviewRequest struct {
+	Rating int    `json:"rating" binding:"required"`
+	Text   string `json:"text"`
+}
+
+func (req *CreateReviewRequest) ToEntity() entity.CreateReviewInput {
+	return entity.CreateReviewInput{
+		Rating: req.Rating,
+		Text:   req.Text,
+	}
+}
+
+type UpdateReviewRequest struct {
+	Rating *int    `json:"rating"`
+	Text   *string `json:"text"`
+}
+
+func (req *UpdateReviewRequest) ToEntity() entity.UpdateReviewInput {
+	return entity.UpdateReviewInput{
+		Rating: req.Rating,
+		Text:   req.Text,
+	}
+}
+
+type FlagReviewRequest struct {
+	Reason string `json:"reason" binding:"max=1000"`
+}
+
+func (req *FlagReviewRequest) ToEntity() entity.FlagReviewInput {
+	return entity.FlagReviewInput{
+		Reason: req.Reason,
+	}
+}
+
+type ModerateReviewRequest struct {
+	Status entity.ReviewStatus `json:"status" binding:"required"`
+}
+
+func (req *ModerateReviewRequest) ToEntity() entity.ModerateReviewInput {
+	return entity.ModerateReviewInput{
+		Status: req.Status,
+	}
+}
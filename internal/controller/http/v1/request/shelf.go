@@ -0,0 +1,32 @@
+package request
+
+import (
+	"test_go/internal/entity"
+	"time"
+)
+
+type CreateShelfRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+func (req *CreateShelfRequest) ToEntity() entity.CreateShelfInput {
+	return entity.CreateShelfInput{
+		Name: req.Name,
+	}
+}
+
+type PutShelfBookRequest struct {
+	Progress   *int       `json:"progress"`
+	FinishedAt *time.Time `json:"finishedAt"`
+}
+
+func (req *PutShelfBookRequest) ToEntity() entity.PutShelfBookInput {
+	return entity.PutShelfBookInput{
+		Progress:   req.Progress,
+		FinishedAt: req.FinishedAt,
+	}
+}
+
+type ReadingSummaryRequest struct {
+	Year *int `form:"year" binding:"omitempty,min=1,max=9999"`
+}
@@ -16,6 +16,24 @@ func (req *ChangePasswordRequest) ToEntity() entity.ChangePasswordInput {
 	}
 }
 
+// UpdateProfileRequest - a JSON merge patch, null clears the name or surname
+// and resets the locale to the default one.
+type UpdateProfileRequest struct {
+	Name     entity.PatchField[string] `json:"name"`
+	Surname  entity.PatchField[string] `json:"surname"`
+	Username entity.PatchField[string] `json:"username"`
+	Locale   entity.PatchField[string] `json:"locale"`
+}
+
+func (req *UpdateProfileRequest) ToEntity() entity.UpdateUserInput {
+	return entity.UpdateUserInput{
+		Name:     req.Name,
+		Surname:  req.Surname,
+		Username: req.Username,
+		Locale:   req.Locale,
+	}
+}
+
 type UpdateRatingRequest struct {
 	Rating float32 `json:"rating" validate:"required" min:"0" max:"100"`
 }
@@ -0,0 +1,14 @@
+package request
+
+const (
+	WsSubscribe   = "subscribe"
+	WsUnsubscribe = "unsubscribe"
+)
+
+// WsRequest - a message of a ws client. A subscription resumes after LastEventID,
+// 0 starts it with a snapshot of the run.
+type WsRequest struct {
+	Type        string `json:"type"`
+	RunID       int64  `json:"runId"`
+	LastEventID int64  `json:"lastEventId"`
+}
@@ -0,0 +1,244 @@
+package v1
+
+import (
+	httpError "github.com/Alice00021/test_common/pkg/httpserver"
+	"github.com/Alice00021/test_common/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"test_go/internal/controller/http/errors"
+	"test_go/internal/controller/http/middleware"
+	"test_go/internal/controller/http/v1/request"
+	"test_go/internal/entity"
+	"test_go/internal/usecase"
+	"test_go/internal/utils"
+)
+
+type reviewRoutes struct {
+	l  logger.Interface
+	uc usecase.Review
+}
+
+func NewReviewRoutes(privateGroup *gin.RouterGroup, l logger.Interface, uc usecase.Review) {
+	r := &reviewRoutes{l, uc}
+	{
+		h := privateGroup.Group("/book")
+		h.GET("/:id/reviews", r.getBookReviews)
+		h.POST("/:id/reviews", r.createReview)
+	}
+	{
+		h := privateGroup.Group("/reviews")
+		h.PATCH("/:id", r.updateReview)
+		h.DELETE("/:id", r.deleteReview)
+		h.POST("/:id/flag", r.flagReview)
+	}
+	{
+		h := privateGroup.Group("/reviews", middleware.IsRoleMiddleware(entity.UserRoleAdmin))
+		h.GET("/flagged", r.getFlaggedReviews)
+		h.POST("/:id/moderate", r.moderateReview)
+	}
+}
+
+func (r *reviewRoutes) getBookReviews(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getBookReviews")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	var req request.ListReviewsRequest
+	if err = c.ShouldBindQuery(&req); err != nil {
+		r.l.Error(err, "http - v1 - getBookReviews")
+		errors.ErrorResponse(c, httpError.NewBadQueryParamsError(err))
+		return
+	}
+
+	filter := req.ToEntity()
+	filter.BookID = &id
+	filter.Statuses = []entity.ReviewStatus{entity.ReviewStatusPublished, entity.ReviewStatusFlagged}
+
+	res, err := r.uc.GetReviews(c.Request.Context(), filter)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getBookReviews")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *reviewRoutes) createReview(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - createReview")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	var req request.CreateReviewRequest
+	if err = c.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - createReview")
+		errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
+		return
+	}
+
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	inp := req.ToEntity()
+	inp.BookID = id
+	inp.UserID = currentUser.ID
+
+	res, err := r.uc.CreateReview(c.Request.Context(), inp)
+	if err != nil {
+		r.l.Error(err, "http - v1 - createReview")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+func (r *reviewRoutes) updateReview(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - updateReview")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	var req request.UpdateReviewRequest
+	if err = c.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - updateReview")
+		errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
+		return
+	}
+
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	inp := req.ToEntity()
+	inp.ID = id
+
+	res, err := r.uc.UpdateReview(c.Request.Context(), currentUser, inp)
+	if err != nil {
+		r.l.Error(err, "http - v1 - updateReview")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *reviewRoutes) deleteReview(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - deleteReview")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	if err = r.uc.DeleteReview(c.Request.Context(), currentUser, id); err != nil {
+		r.l.Error(err, "http - v1 - deleteReview")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (r *reviewRoutes) flagReview(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - flagReview")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	var req request.FlagReviewRequest
+	if c.Request.ContentLength != 0 {
+		if err = c.ShouldBindJSON(&req); err != nil {
+			r.l.Error(err, "http - v1 - flagReview")
+			errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
+			return
+		}
+	}
+
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	inp := req.ToEntity()
+	inp.ReviewID = id
+	inp.UserID = currentUser.ID
+
+	if err = r.uc.FlagReview(c.Request.Context(), inp); err != nil {
+		r.l.Error(err, "http - v1 - flagReview")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (r *reviewRoutes) getFlaggedReviews(c *gin.Context) {
+	var req request.ListReviewsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		r.l.Error(err, "http - v1 - getFlaggedReviews")
+		errors.ErrorResponse(c, httpError.NewBadQueryParamsError(err))
+		return
+	}
+
+	filter := req.ToEntity()
+	filter.Statuses = []entity.ReviewStatus{entity.ReviewStatusFlagged}
+
+	res, err := r.uc.GetReviews(c.Request.Context(), filter)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getFlaggedReviews")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *reviewRoutes) moderateReview(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - moderateReview")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	var req request.ModerateReviewRequest
+	if err = c.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - moderateReview")
+		errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
+		return
+	}
+
+	inp := req.ToEntity()
+	inp.ID = id
+
+	if err = r.uc.ModerateReview(c.Request.Context(), inp); err != nil {
+		r.l.Error(err, "http - v1 - moderateReview")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,205 @@
+package v1
+
+import (
+	httpError "github.com/Alice00021/test_common/pkg/httpserver"
+	"github.com/Alice00021/test_common/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"test_go/internal/controller/http/errors"
+	"test_go/internal/controller/http/middleware"
+	"test_go/internal/controller/http/v1/request"
+	"test_go/internal/usecase"
+	"test_go/internal/utils"
+	"time"
+)
+
+type shelfRoutes struct {
+	l  logger.Interface
+	uc usecase.Shelf
+}
+
+func NewShelfRoutes(privateGroup *gin.RouterGroup, l logger.Interface, uc usecase.Shelf) {
+	r := &shelfRoutes{l, uc}
+	{
+		h := privateGroup.Group("/users/me/shelves")
+		h.GET("", r.getShelves)
+		h.POST("", r.createShelf)
+		h.GET("/summary", r.getReadingSummary)
+		h.DELETE("/:id", r.deleteShelf)
+		h.PUT("/:id/books/:bookId", r.putBook)
+		h.DELETE("/:id/books/:bookId", r.removeBook)
+	}
+}
+
+func (r *shelfRoutes) getShelves(c *gin.Context) {
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	res, err := r.uc.GetShelves(c.Request.Context(), currentUser.ID)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getShelves")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *shelfRoutes) createShelf(c *gin.Context) {
+	var req request.CreateShelfRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - createShelf")
+		errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
+		return
+	}
+
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	inp := req.ToEntity()
+	inp.UserID = currentUser.ID
+
+	res, err := r.uc.CreateShelf(c.Request.Context(), inp)
+	if err != nil {
+		r.l.Error(err, "http - v1 - createShelf")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+func (r *shelfRoutes) deleteShelf(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - deleteShelf")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	if err = r.uc.DeleteShelf(c.Request.Context(), currentUser.ID, id); err != nil {
+		r.l.Error(err, "http - v1 - deleteShelf")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (r *shelfRoutes) putBook(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - putBook")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	bookId, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "bookId"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - putBook")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	var req request.PutShelfBookRequest
+	if c.Request.ContentLength != 0 {
+		if err = c.ShouldBindJSON(&req); err != nil {
+			r.l.Error(err, "http - v1 - putBook")
+			errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
+			return
+		}
+	}
+
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	inp := req.ToEntity()
+	inp.UserID = currentUser.ID
+	inp.ShelfID = id
+	inp.BookID = bookId
+
+	res, err := r.uc.PutBook(c.Request.Context(), inp)
+	if err != nil {
+		r.l.Error(err, "http - v1 - putBook")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *shelfRoutes) removeBook(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - removeBook")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	bookId, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "bookId"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - removeBook")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	if err = r.uc.RemoveBook(c.Request.Context(), currentUser.ID, id, bookId); err != nil {
+		r.l.Error(err, "http - v1 - removeBook")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (r *shelfRoutes) getReadingSummary(c *gin.Context) {
+	var req request.ReadingSummaryRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		r.l.Error(err, "http - v1 - getReadingSummary")
+		errors.ErrorResponse(c, httpError.NewBadQueryParamsError(err))
+		return
+	}
+
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	year := time.Now().Year()
+	if req.Year != nil {
+		year = *req.Year
+	}
+
+	res, err := r.uc.GetReadingSummary(c.Request.Context(), currentUser.ID, year)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getReadingSummary")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
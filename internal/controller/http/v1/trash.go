@@ -0,0 +1,85 @@
+package v1
+
+import (
+	httpError "github.com/Alice00021/test_common/pkg/httpserver"
+	"github.com/Alice00021/test_common/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"test_go/internal/controller/http/errors"
+	"test_go/internal/controller/http/middleware"
+	"test_go/internal/entity"
+	"test_go/internal/usecase"
+	"test_go/internal/utils"
+)
+
+type trashRoutes struct {
+	l  logger.Interface
+	uc usecase.Trash
+}
+
+func NewTrashRoutes(privateGroup *gin.RouterGroup, l logger.Interface, uc usecase.Trash) {
+	r := &trashRoutes{l, uc}
+	{
+		h := privateGroup.Group("/trash", middleware.IsRoleMiddleware(entity.UserRoleAdmin))
+		h.GET("", r.getTrash)
+		h.POST("/authors/:id/restore", r.restoreAuthor)
+		h.POST("/books/:id/restore", r.restoreBook)
+	}
+}
+
+func (r *trashRoutes) getTrash(c *gin.Context) {
+	res, err := r.uc.GetTrash(c.Request.Context())
+	if err != nil {
+		r.l.Error(err, "http - v1 - getTrash")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (r *trashRoutes) restoreAuthor(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - restoreAuthor")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	if err = r.uc.RestoreAuthor(c.Request.Context(), currentUser, id); err != nil {
+		r.l.Error(err, "http - v1 - restoreAuthor")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (r *trashRoutes) restoreBook(c *gin.Context) {
+	id, err := utils.ParsePathParam(utils.ParseParams{Context: c, Key: "id"}, utils.ParseInt64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - restoreBook")
+		errors.ErrorResponse(c, httpError.NewBadPathParamsError(err))
+		return
+	}
+
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	if err = r.uc.RestoreBook(c.Request.Context(), currentUser, id); err != nil {
+		r.l.Error(err, "http - v1 - restoreBook")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
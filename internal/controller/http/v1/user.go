@@ -22,6 +22,7 @@ func NewUserRoutes(privateGroup *gin.RouterGroup, l logger.Interface, uc usecase
 	{
 		h := privateGroup.Group("/users")
 		h.GET("/profile", r.getProfile)
+		h.PATCH("/profile", r.updateProfile)
 		h.PATCH("/change-password", r.changePassword)
 		h.PUT("/photo", r.setProfilePhoto)
 		h.PATCH("/rating", r.updateRating)
@@ -47,6 +48,32 @@ func (r *userRoutes) getProfile(c *gin.Context) {
 	c.JSON(http.StatusOK, res)
 }
 
+func (r *userRoutes) updateProfile(c *gin.Context) {
+	var req request.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - updateProfile")
+		errors.ErrorResponse(c, httpError.NewBadRequestBodyError(err))
+		return
+	}
+
+	currentUser, err := middleware.GetCurrentUser(c)
+	if err != nil {
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	inp := req.ToEntity()
+	inp.ID = currentUser.ID
+
+	if err = r.uc.UpdateUser(c.Request.Context(), inp); err != nil {
+		r.l.Error(err, "http - v1 - updateProfile")
+		errors.ErrorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
 func (r *userRoutes) changePassword(c *gin.Context) {
 	var req request.ChangePasswordRequest
 
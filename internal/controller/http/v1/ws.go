@@ -0,0 +1,237 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	httpError "github.com/Alice00021/test_common/pkg/httpserver"
+	"github.com/Alice00021/test_common/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"sync"
+	er "test_go/internal/controller/http/errors"
+	"test_go/internal/controller/http/middleware"
+	"test_go/internal/controller/http/v1/request"
+	"test_go/internal/entity"
+	"test_go/internal/usecase"
+	"time"
+)
+
+const (
+	// wsWriteWait - a client that does not take a message in time is disconnected,
+	// it reconnects and resumes from the last event it got.
+	wsWriteWait      = 10 * time.Second
+	wsPongWait       = 60 * time.Second
+	wsPingPeriod     = wsPongWait * 9 / 10
+	wsMaxMessageSize = 4096
+
+	// wsAuthProtocol - browsers cannot set headers on a ws handshake, so the token is sent
+	// as the subprotocol after this one: new WebSocket(url, ["bearer", token]).
+	// Unlike a query parameter it does not end up in the access log.
+	wsAuthProtocol = "bearer"
+)
+
+type wsRoutes struct {
+	l        logger.Interface
+	validate func(token string) (map[string]*string, error)
+	uc       usecase.OperationRun
+	upgrader websocket.Upgrader
+}
+
+// wsMessage - a message of the server other than an event.
+type wsMessage struct {
+	Type  string `json:"type"`
+	RunID int64  `json:"runId,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// NewWsRoutes - the token is checked by the handler, see wsAuthProtocol.
+func NewWsRoutes(publicGroup *gin.RouterGroup, l logger.Interface, authUc usecase.Auth, uc usecase.OperationRun) {
+	r := &wsRoutes{
+		l:        l,
+		validate: middleware.JwtWsValidator(authUc),
+		uc:       uc,
+		upgrader: websocket.Upgrader{
+			// Clients are authenticated by the token and not by cookies, any origin may connect as with cors.
+			CheckOrigin: func(*http.Request) bool { return true },
+			// Browsers drop a connection whose response does not pick one of the requested subprotocols.
+			Subprotocols: []string{wsAuthProtocol},
+		},
+	}
+	publicGroup.GET("/ws", r.serveWs)
+}
+
+// serveWs - the client subscribes to runs by their ID and gets their events until it unsubscribes,
+// the run is over or the connection is closed.
+func (r *wsRoutes) serveWs(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" {
+		token = wsProtocolToken(c.Request)
+	}
+	if _, err := r.validate(token); err != nil {
+		r.l.Error(err, "http - v1 - serveWs")
+		er.ErrorResponse(c, httpError.NewUnauthorizedError(middleware.ErrUnauthorized))
+		return
+	}
+
+	conn, err := r.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already replied.
+		r.l.Error(err, "http - v1 - serveWs - upgrade")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	client := &wsClient{
+		conn:          conn,
+		out:           make(chan any),
+		subscriptions: make(map[int64]context.CancelFunc),
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		client.writePump(ctx)
+		// readPump may be waiting for writePump to take a message.
+		cancel()
+	}()
+
+	r.readPump(ctx, client)
+	cancel()
+	<-done
+	_ = conn.Close()
+}
+
+// wsProtocolToken - the token sent as the subprotocol after wsAuthProtocol, as an Authorization header value.
+func wsProtocolToken(req *http.Request) string {
+	protocols := websocket.Subprotocols(req)
+	for i := 0; i+1 < len(protocols); i++ {
+		if protocols[i] == wsAuthProtocol {
+			return "Bearer " + protocols[i+1]
+		}
+	}
+	return ""
+}
+
+func (r *wsRoutes) readPump(ctx context.Context, client *wsClient) {
+	conn := client.conn
+	conn.SetReadLimit(wsMaxMessageSize)
+	_ = conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				r.l.Error(err, "http - v1 - ws - readPump")
+			}
+			return
+		}
+
+		var req request.WsRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			client.send(ctx, &wsMessage{Type: "error", Error: "invalid message: " + err.Error()})
+			continue
+		}
+
+		switch req.Type {
+		case request.WsSubscribe:
+			r.subscribe(ctx, client, req)
+		case request.WsUnsubscribe:
+			client.unsubscribe(req.RunID)
+		default:
+			client.send(ctx, &wsMessage{Type: "error", RunID: req.RunID, Error: "unknown message type: " + req.Type})
+		}
+	}
+}
+
+// subscribe - a new subscription to the same run replaces the previous one.
+// Any signed in user may follow any run, the same as with GET /runs/:id.
+func (r *wsRoutes) subscribe(ctx context.Context, client *wsClient, req request.WsRequest) {
+	subCtx, cancel := context.WithCancel(ctx)
+	client.unsubscribe(req.RunID)
+	client.mu.Lock()
+	client.subscriptions[req.RunID] = cancel
+	client.mu.Unlock()
+
+	events, err := r.uc.Subscribe(subCtx, req.RunID, req.LastEventID)
+	if err != nil {
+		r.l.Error(err, "http - v1 - ws - subscribe")
+		client.unsubscribe(req.RunID)
+
+		message := "internal error"
+		if errors.Is(err, entity.ErrOperationRunNotFound) {
+			message = entity.ErrOperationRunNotFound.Error()
+		}
+		client.send(ctx, &wsMessage{Type: "error", RunID: req.RunID, Error: message})
+		return
+	}
+
+	go func() {
+		defer cancel()
+		for e := range events {
+			if !client.send(subCtx, e) {
+				return
+			}
+		}
+	}()
+}
+
+// wsClient - every message goes through writePump, one at a time. The subscriptions wait for it,
+// so a slow client holds back only its own streams while the events wait in the use case.
+type wsClient struct {
+	conn *websocket.Conn
+	out  chan any
+
+	mu            sync.Mutex
+	subscriptions map[int64]context.CancelFunc
+}
+
+func (cl *wsClient) send(ctx context.Context, message any) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case cl.out <- message:
+		return true
+	}
+}
+
+func (cl *wsClient) unsubscribe(runID int64) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	if cancel, ok := cl.subscriptions[runID]; ok {
+		cancel()
+		delete(cl.subscriptions, runID)
+	}
+}
+
+// writePump - closing the connection on a failed write stops readPump as well.
+func (cl *wsClient) writePump(ctx context.Context) {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			_ = cl.conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(wsWriteWait))
+			return
+		case message := <-cl.out:
+			_ = cl.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := cl.conn.WriteJSON(message); err != nil {
+				_ = cl.conn.Close()
+				return
+			}
+		case <-ticker.C:
+			_ = cl.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := cl.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				_ = cl.conn.Close()
+				return
+			}
+		}
+	}
+}
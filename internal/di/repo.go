@@ -4,6 +4,7 @@ import (
 	m "github.com/Alice00021/test_common/pkg/mongodb"
 	"github.com/Alice00021/test_common/pkg/postgres"
 	"test_go/internal/repo"
+	"test_go/internal/repo/filestorage"
 	mongodb "test_go/internal/repo/mongodb"
 	"test_go/internal/repo/persistent"
 )
@@ -12,21 +13,37 @@ type Repo struct {
 	UserRepo              repo.UserRepo
 	BookRepo              repo.BookRepo
 	AuthorRepo            repo.AuthorRepo
+	ShelfRepo             repo.ShelfRepo
+	ReviewRepo            repo.ReviewRepo
+	CoverStorage          repo.CoverStorage
+	RevisionRepo          repo.RevisionRepo
 	CommandRepo           repo.CommandRepo
+	CommandCatalogRepo    repo.CommandCatalogRepo
+	DeckProfileRepo       repo.DeckProfileRepo
 	OperationRepo         repo.OperationRepo
 	OperationCommandsRepo repo.OperationCommandsRepo
+	OperationRunRepo      repo.OperationRunRepo
+	InstrumentRepo        repo.InstrumentRepo
 	CommandMongoRepo      repo.CommandMongoRepo
 	OperationMongoRepo    repo.OperationMongoRepo
 }
 
-func NewRepo(pg *postgres.Postgres, mongoClient *m.Client) *Repo {
+func NewRepo(pg *postgres.Postgres, mongoClient *m.Client, fileStoragePath string) *Repo {
 	return &Repo{
 		UserRepo:              persistent.NewUserRepo(pg),
 		BookRepo:              persistent.NewBookRepo(pg),
 		AuthorRepo:            persistent.NewAuthorRepo(pg),
+		ShelfRepo:             persistent.NewShelfRepo(pg),
+		ReviewRepo:            persistent.NewReviewRepo(pg),
+		CoverStorage:          filestorage.NewCoverStorage(fileStoragePath),
+		RevisionRepo:          persistent.NewRevisionRepo(pg),
 		CommandRepo:           persistent.NewCommandRepo(pg),
+		CommandCatalogRepo:    persistent.NewCommandCatalogRepo(pg),
+		DeckProfileRepo:       persistent.NewDeckProfileRepo(pg),
 		OperationRepo:         persistent.NewOperationRepo(pg),
 		OperationCommandsRepo: persistent.NewOperationCommandsRepo(pg),
+		OperationRunRepo:      persistent.NewOperationRunRepo(pg),
+		InstrumentRepo:        persistent.NewInstrumentRepo(pg),
 		CommandMongoRepo:      mongodb.NewCommandRepo(mongoClient),
 		OperationMongoRepo:    mongodb.NewOperationRepo(mongoClient),
 	}
@@ -5,13 +5,23 @@ import (
 	"github.com/Alice00021/test_common/pkg/transactional"
 	"sync"
 	"test_go/config"
+	"test_go/internal/entity"
+	"test_go/internal/repo/executor"
 	"test_go/internal/usecase"
 	"test_go/internal/usecase/auth"
 	"test_go/internal/usecase/author"
 	"test_go/internal/usecase/book"
 	"test_go/internal/usecase/command"
+	"test_go/internal/usecase/deckprofile"
 	"test_go/internal/usecase/export"
+	"test_go/internal/usecase/importer"
+	"test_go/internal/usecase/instrument"
 	"test_go/internal/usecase/operation"
+	"test_go/internal/usecase/operationrun"
+	"test_go/internal/usecase/review"
+	"test_go/internal/usecase/revision"
+	"test_go/internal/usecase/shelf"
+	"test_go/internal/usecase/trash"
 	"test_go/internal/usecase/user"
 )
 
@@ -20,11 +30,17 @@ type UseCase struct {
 	User           usecase.User
 	Book           usecase.Book
 	Author         usecase.Author
+	Shelf          usecase.Shelf
+	Review         usecase.Review
+	Trash          usecase.Trash
 	Export         usecase.Export
+	Import         usecase.Import
 	Command        usecase.Command
+	CommandWatcher usecase.CommandWatcher
 	Operation      usecase.Operation
-	CommandMongo   usecase.CommandMongo
-	OperationMongo usecase.OperationMongo
+	DeckProfile    usecase.DeckProfile
+	OperationRun   usecase.OperationRun
+	Instrument     usecase.Instrument
 }
 
 func NewUseCase(
@@ -36,23 +52,67 @@ func NewUseCase(
 	txMtx := &sync.Mutex{}
 	authUc := auth.New(t, l, repo.UserRepo, conf.Auth, conf.LocalFileStorage.BasePath, &conf.EmailConfig, txMtx)
 	userUc := user.New(t, l, repo.UserRepo, conf.LocalFileStorage.BasePath, &conf.EmailConfig, txMtx)
-	authorUc := author.New(t, repo.AuthorRepo, l)
-	bookUc := book.New(t, repo.BookRepo, l)
-	commandUc := command.New(t, repo.CommandRepo, conf.LocalFileStorage, l)
-	commandMongoUc := command.NewMongo(repo.CommandMongoRepo, conf.LocalFileStorage, l)
-	OperationMongoUc := operation.NewMongo(repo.OperationMongoRepo, repo.CommandMongoRepo, l)
-	operationUc := operation.New(t, repo.OperationRepo, repo.OperationCommandsRepo, repo.CommandRepo, l)
-	exportUc := export.New(authorUc, bookUc, commandUc, operationUc, l, conf.LocalFileStorage.ExportPath)
+	revisionUc := revision.New(repo.RevisionRepo, l)
+	authorUc := author.New(t, repo.AuthorRepo, repo.BookRepo, repo.ShelfRepo, revisionUc,
+		entity.AuthorDeletePolicy(conf.Library.AuthorDeletePolicy),
+		entity.AuthorStatusRule{ProfessionalMinBooks: conf.Library.ProfessionalMinBooks},
+		l,
+	)
+	trashUc := trash.New(t, repo.AuthorRepo, repo.BookRepo, repo.ShelfRepo, repo.CoverStorage, revisionUc,
+		conf.Library.TrashRetention, l,
+	)
+	bookUc := book.New(t, repo.BookRepo, repo.ShelfRepo, repo.CoverStorage,
+		entity.CoverSettings{
+			MaxSize:        conf.Library.CoverMaxSize,
+			MaxPixels:      conf.Library.CoverMaxPixels,
+			ThumbnailSizes: conf.Library.CoverThumbnailSizes,
+		},
+		revisionUc,
+		l,
+	)
+	shelfUc := shelf.New(t, repo.ShelfRepo, repo.BookRepo, l)
+	reviewUc := review.New(t, repo.ReviewRepo, repo.BookRepo, l)
+
+	// commands and operations of a single backend, so every transport sees the same data;
+	// deck profiles stay in postgres and only clear their references in the operations backend
+	var (
+		deckProfileUc usecase.DeckProfile
+		commandUc     usecase.Command
+		operationUc   usecase.Operation
+	)
+	switch conf.Storage.Backend {
+	case config.StorageBackendMongo:
+		deckProfileUc = deckprofile.New(t, repo.DeckProfileRepo, repo.OperationMongoRepo, l)
+		commandUc = command.NewMongo(repo.CommandMongoRepo, repo.CommandCatalogRepo, conf.LocalFileStorage, l)
+		operationUc = operation.NewMongo(repo.OperationMongoRepo, repo.CommandMongoRepo, repo.CommandCatalogRepo, deckProfileUc, revisionUc, l)
+	default:
+		deckProfileUc = deckprofile.New(t, repo.DeckProfileRepo, nil, l)
+		commandUc = command.New(t, repo.CommandRepo, repo.CommandCatalogRepo, conf.LocalFileStorage, l)
+		operationUc = operation.New(t, repo.OperationRepo, repo.OperationCommandsRepo, repo.CommandRepo,
+			repo.CommandCatalogRepo, deckProfileUc, revisionUc, l)
+	}
+	commandWatcher := command.NewWatcher(commandUc, conf.LocalFileStorage, conf.CommandCatalog.WatchEnabled, l)
+	importUc := importer.New(t, repo.AuthorRepo, repo.BookRepo, revisionUc, l)
+	instrumentUc := instrument.New(t, repo.InstrumentRepo, deckProfileUc, l)
+	operationRunUc := operationrun.New(t, repo.OperationRunRepo, repo.InstrumentRepo, operationUc, commandUc, deckProfileUc,
+		executor.NewSimulator(executor.SystemClock{}, conf.Runs.SimulatorTimeUnit), conf.Runs.SimulatorTimeUnit, l)
+	exportUc := export.New(authorUc, commandUc, operationUc, l, conf.LocalFileStorage.ExportPath)
 
 	return &UseCase{
 		Auth:           authUc,
 		Author:         authorUc,
+		Shelf:          shelfUc,
+		Review:         reviewUc,
+		Trash:          trashUc,
 		Book:           bookUc,
 		User:           userUc,
 		Export:         exportUc,
+		Import:         importUc,
 		Command:        commandUc,
-		CommandMongo:   commandMongoUc,
+		CommandWatcher: commandWatcher,
 		Operation:      operationUc,
-		OperationMongo: OperationMongoUc,
+		DeckProfile:    deckProfileUc,
+		OperationRun:   operationRunUc,
+		Instrument:     instrumentUc,
 	}
 }
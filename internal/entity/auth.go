@@ -5,9 +5,12 @@ type TokenPair struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+// UserInfoToken - the claims of a token. Locale is the one of the profile when the token was issued,
+// a changed locale is picked up with the next refresh.
 type UserInfoToken struct {
-	ID   int64    `json:"id"`
-	Role UserRole `json:"role"`
+	ID     int64    `json:"id"`
+	Role   UserRole `json:"role"`
+	Locale string   `json:"locale,omitempty"`
 }
 
 func (u *UserInfoToken) IsEqualRole(role UserRole) bool {
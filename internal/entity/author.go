@@ -1,9 +1,12 @@
 package entity
 
+import "time"
+
 type Author struct {
 	Entity
 	Name   string
 	Gender bool
+	Stats  *AuthorStats `db:"-"`
 }
 
 type CreateAuthorInput struct {
@@ -11,9 +14,25 @@ type CreateAuthorInput struct {
 	Gender bool   `json:"gender"`
 }
 
+// UpdateAuthorInput - a merge patch of an author.
 type UpdateAuthorInput struct {
-	ID   int64  `json:"id"`
-	Name string `json:"name"`
+	ID     int64              `json:"id"`
+	Name   PatchField[string] `json:"name"`
+	Gender PatchField[bool]   `json:"gender"`
+}
+
+func (inp UpdateAuthorInput) Validate() error {
+	if err := RequiredNonZero(inp.Name, "name"); err != nil {
+		return err
+	}
+	return inp.Gender.Required("gender")
+}
+
+// Apply - the author with the patch merged in.
+func (inp UpdateAuthorInput) Apply(a Author) *Author {
+	inp.Name.Apply(&a.Name)
+	inp.Gender.Apply(&a.Gender)
+	return &a
 }
 
 func NewAuthor(name string, gender bool) *Author {
@@ -22,3 +41,40 @@ func NewAuthor(name string, gender bool) *Author {
 		Gender: gender,
 	}
 }
+
+type AuthorStatus string
+
+const (
+	AuthorStatusBeginner     AuthorStatus = "BEGINNER"
+	AuthorStatusProfessional AuthorStatus = "PROFESSIONAL"
+)
+
+// Title - human readable status used in reports.
+func (s AuthorStatus) Title() string {
+	if s == AuthorStatusProfessional {
+		return "Профессионал"
+	}
+	return "Начинающий писатель"
+}
+
+// AuthorStats - publication dates are the dates the books were added to the library.
+type AuthorStats struct {
+	BookCount        int64        `json:"bookCount"`
+	FirstPublishedAt *time.Time   `json:"firstPublishedAt"`
+	LastPublishedAt  *time.Time   `json:"lastPublishedAt"`
+	Status           AuthorStatus `json:"status"`
+	RatingAvg        float64      `json:"ratingAvg"`
+	RatingCount      int64        `json:"ratingCount"`
+}
+
+// AuthorStatusRule - an author with at least ProfessionalMinBooks books is a professional.
+type AuthorStatusRule struct {
+	ProfessionalMinBooks int64
+}
+
+func (r AuthorStatusRule) Status(bookCount int64) AuthorStatus {
+	if bookCount >= r.ProfessionalMinBooks {
+		return AuthorStatusProfessional
+	}
+	return AuthorStatusBeginner
+}
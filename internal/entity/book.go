@@ -2,25 +2,70 @@ package entity
 
 type Book struct {
 	Entity
-	Title    string
-	AuthorId int64
-	Author   Author
+	Title     string
+	AuthorId  int64
+	Author    Author
+	CreatedBy *int64
+	// RatingAvg and RatingCount cover the reviews that are not hidden by moderation.
+	RatingAvg   float64
+	RatingCount int64
+	CoverHash   *string `json:"-"`
+	CoverType   *string `json:"-"`
+	CoverURL    *string
+}
+
+// FillCoverURL - sets the public cover address when the book has a cover.
+func (b *Book) FillCoverURL() {
+	if b.CoverHash == nil {
+		b.CoverURL = nil
+		return
+	}
+	url := CoverURL(b.ID, *b.CoverHash)
+	b.CoverURL = &url
 }
 
 type CreateBookInput struct {
-	Title    string `json:"name"`
-	AuthorId int64  `json:"author_id"`
+	Title     string `json:"name"`
+	AuthorId  int64  `json:"author_id"`
+	CreatedBy int64  `json:"-"`
 }
 
+// UpdateBookInput - a merge patch of a book.
 type UpdateBookInput struct {
-	ID       int64  `json:"id"`
-	Title    string `json:"name"`
-	AuthorId int64  `json:"author_id"`
+	ID       int64              `json:"id"`
+	Title    PatchField[string] `json:"name"`
+	AuthorId PatchField[int64]  `json:"author_id"`
+}
+
+func (inp UpdateBookInput) Validate() error {
+	if err := RequiredNonZero(inp.Title, "title"); err != nil {
+		return err
+	}
+	return RequiredNonZero(inp.AuthorId, "authorId")
 }
 
-func NewBook(title string, authorId int64) *Book {
+// Apply - the book with the patch merged in.
+func (inp UpdateBookInput) Apply(b Book) *Book {
+	inp.Title.Apply(&b.Title)
+	inp.AuthorId.Apply(&b.AuthorId)
+	return &b
+}
+
+func NewBook(title string, authorId int64, createdBy int64) *Book {
 	return &Book{
-		Title:    title,
-		AuthorId: authorId,
+		Title:     title,
+		AuthorId:  authorId,
+		CreatedBy: &createdBy,
+	}
+}
+
+// CanBeModifiedBy - only the owner of the book or an admin can change it.
+func (b *Book) CanBeModifiedBy(u *UserInfoToken) bool {
+	if u == nil {
+		return false
+	}
+	if u.IsEqualRole(UserRoleAdmin) {
+		return true
 	}
+	return b.CreatedBy != nil && *b.CreatedBy == u.ID
 }
@@ -2,7 +2,9 @@ package entity
 
 import (
 	"encoding/json"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"slices"
+	"strings"
 )
 
 type ReagentType string
@@ -17,6 +19,15 @@ const (
 	ReagentTypeDF      ReagentType = "DF"
 )
 
+var reagentTypes = []ReagentType{
+	ReagentTypeVER, ReagentTypeCAL, ReagentTypeALCOHOL, ReagentTypeBLEACH,
+	ReagentTypeWATER, ReagentTypeALKALI, ReagentTypeDF,
+}
+
+func (t ReagentType) IsValid() bool {
+	return slices.Contains(reagentTypes, t)
+}
+
 type Address string
 
 const (
@@ -34,10 +45,33 @@ const (
 	AddressSH Address = "SH"
 )
 
+const (
+	// SmallContainerVolume - capacity of the S* containers.
+	SmallContainerVolume int64 = 200
+	// LargeContainerVolume - capacity of the R* containers.
+	LargeContainerVolume int64 = 5000
+)
+
+// IsValid - the address is on the standard deck, catalog default addresses refer to it.
+func (a Address) IsValid() bool {
+	_, ok := standardDeck.Position(a)
+	return ok
+}
+
+// Capacity - the volume the container at the address holds on the standard deck, 0 for unknown addresses.
+func (a Address) Capacity() int64 {
+	if p, ok := standardDeck.Position(a); ok {
+		return p.Capacity
+	}
+	return 0
+}
+
+// Container - the state of a deck position while the commands of an operation are walked.
 type Container struct {
 	Address     Address
 	ReagentType ReagentType
 	Volume      int64
+	Capacity    int64
 }
 type Command struct {
 	Entity
@@ -49,6 +83,18 @@ type Command struct {
 	VolumeDriveFluid int64
 	VolumeContainer  int64
 	DefaultAddress   Address
+	Names            LocalizedNames `db:"-"`
+	// Disabled - the command stays in the catalog but cannot be added to operations.
+	Disabled bool `db:"is_disabled"`
+	// MongoID - the id of the document when the command is stored in mongo.
+	MongoID primitive.ObjectID `db:"-" json:"-"`
+}
+
+// Localize - switches Name to the locale, commands without translations keep their name.
+func (c *Command) Localize(locale string) {
+	if name := c.Names.Get(locale); name != "" {
+		c.Name = name
+	}
 }
 
 // Временная структура для парсинга
@@ -72,21 +118,39 @@ func (c *Command) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	if raw.EnglishName() == "" {
+		return ErrCommandNameNotFound
+	}
+
+	*c = raw.ToCommand()
+	return nil
+}
+
+// EnglishName - the name used by the service, empty when the catalog has no english one.
+func (raw *CommandJSON) EnglishName() string {
 	for _, n := range raw.Name {
-		if n.Locale == "en" {
-			c.Name = n.Value
-			break
+		if strings.EqualFold(n.Locale, DefaultLocale) {
+			return n.Value
 		}
 	}
-	if c.Name == "" {
-		return ErrCommandNameNotFound
+	return ""
+}
+
+func (raw *CommandJSON) ToCommand() Command {
+	names := make(LocalizedNames, len(raw.Name))
+	for _, n := range raw.Name {
+		names[strings.ToLower(n.Locale)] = n.Value
 	}
 
-	c.SystemName = raw.SystemName
-	c.AverageTime = raw.AverageTime
-	c.VolumeWaste = raw.VolumeWaste
-	c.VolumeDriveFluid = raw.VolumeDriveFluid
-	c.VolumeContainer = raw.VolumeContainer
+	c := Command{
+		Names:            names,
+		Name:             raw.EnglishName(),
+		SystemName:       raw.SystemName,
+		AverageTime:      raw.AverageTime,
+		VolumeWaste:      raw.VolumeWaste,
+		VolumeDriveFluid: raw.VolumeDriveFluid,
+		VolumeContainer:  raw.VolumeContainer,
+	}
 
 	if raw.Reagent != nil {
 		c.Reagent = ReagentType(*raw.Reagent)
@@ -95,17 +159,11 @@ func (c *Command) UnmarshalJSON(data []byte) error {
 		c.DefaultAddress = Address(*raw.DefaultAddress)
 	}
 
-	return nil
+	return c
 }
 
 func (t Container) IsValidVolume() bool {
-	one := []Address{AddressSA, AddressSB, AddressSC, AddressSD, AddressSE, AddressSF, AddressSG, AddressSH}
-	two := []Address{AddressRA, AddressRB, AddressRC, AddressRD}
-
-	if slices.Contains(one, t.Address) && t.Volume > 200 {
-		return false
-	}
-	if slices.Contains(two, t.Address) && t.Volume > 5000 {
+	if t.Capacity > 0 && t.Volume > t.Capacity {
 		return false
 	}
 	return true
@@ -0,0 +1,146 @@
+package entity
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"sort"
+)
+
+// CommandCatalogMaxSize - the largest catalog file that is read.
+const CommandCatalogMaxSize = 4 << 20
+
+type ImportCommandsInput struct {
+	File   io.Reader
+	DryRun bool
+	User   *UserInfoToken
+}
+
+// CommandFieldChange - a field of a command that differs between the stored command and the catalog.
+type CommandFieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+type CommandChange struct {
+	SystemName string                `json:"systemName"`
+	Fields     []*CommandFieldChange `json:"fields"`
+	Command    Command               `json:"-"`
+}
+
+// CommandCatalogDiff - the difference between the stored commands and an uploaded catalog.
+// Missing commands stay stored because operations may still refer to them,
+// an import or a rollback disables them instead.
+type CommandCatalogDiff struct {
+	DryRun    bool             `json:"dryRun"`
+	Applied   bool             `json:"applied"`
+	Added     []Command        `json:"added"`
+	Changed   []*CommandChange `json:"changed"`
+	Missing   []Command        `json:"missing"`
+	Unchanged int              `json:"unchanged"`
+	// Version - the catalog version the commands are synced to once the diff is applied.
+	Version *CommandCatalogVersion `json:"version,omitempty"`
+}
+
+func (d *CommandCatalogDiff) HasChanges() bool {
+	return len(d.Added) > 0 || len(d.Changed) > 0 || len(d.MissingEnabled()) > 0
+}
+
+// MissingEnabled - the system names of the missing commands that are still enabled.
+func (d *CommandCatalogDiff) MissingEnabled() []string {
+	systemNames := make([]string, 0)
+	for i := range d.Missing {
+		if !d.Missing[i].Disabled {
+			systemNames = append(systemNames, d.Missing[i].SystemName)
+		}
+	}
+	return systemNames
+}
+
+// ParseCommandCatalog - reads and validates the catalog json, see ValidateCommandCatalog.
+func ParseCommandCatalog(r io.Reader) ([]Command, error) {
+	data, err := io.ReadAll(io.LimitReader(r, CommandCatalogMaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("io.ReadAll: %w", err)
+	}
+	if len(data) > CommandCatalogMaxSize {
+		return nil, ErrCommandCatalogTooLarge
+	}
+
+	items, err := ValidateCommandCatalog(data)
+	if err != nil {
+		return nil, err
+	}
+
+	commands := make([]Command, 0, len(items))
+	for i := range items {
+		commands = append(commands, items[i].ToCommand())
+	}
+
+	return commands, nil
+}
+
+// DiffCommandCatalog - compares the catalog with the stored commands keyed by system name.
+// Changed commands carry the id of the stored ones so the diff can be applied as is.
+func DiffCommandCatalog(existing map[string]Command, catalog []Command) *CommandCatalogDiff {
+	diff := &CommandCatalogDiff{
+		Added:   make([]Command, 0),
+		Changed: make([]*CommandChange, 0),
+		Missing: make([]Command, 0),
+	}
+
+	inCatalog := make(map[string]struct{}, len(catalog))
+	for _, cmd := range catalog {
+		inCatalog[cmd.SystemName] = struct{}{}
+
+		stored, ok := existing[cmd.SystemName]
+		if !ok {
+			diff.Added = append(diff.Added, cmd)
+			continue
+		}
+
+		fields := stored.diffFields(&cmd)
+		if len(fields) == 0 {
+			diff.Unchanged++
+			continue
+		}
+
+		cmd.ID = stored.ID
+		cmd.Disabled = stored.Disabled
+		diff.Changed = append(diff.Changed, &CommandChange{SystemName: cmd.SystemName, Fields: fields, Command: cmd})
+	}
+
+	for systemName, cmd := range existing {
+		if _, ok := inCatalog[systemName]; !ok {
+			diff.Missing = append(diff.Missing, cmd)
+		}
+	}
+	sort.Slice(diff.Missing, func(i, j int) bool {
+		return diff.Missing[i].SystemName < diff.Missing[j].SystemName
+	})
+
+	return diff
+}
+
+func (c *Command) diffFields(other *Command) []*CommandFieldChange {
+	var fields []*CommandFieldChange
+	add := func(field string, before, after any) {
+		if before != after {
+			fields = append(fields, &CommandFieldChange{Field: field, Before: before, After: after})
+		}
+	}
+
+	add("name", c.Name, other.Name)
+	add("reagent", c.Reagent, other.Reagent)
+	add("averageTime", c.AverageTime, other.AverageTime)
+	add("volumeWaste", c.VolumeWaste, other.VolumeWaste)
+	add("volumeDriveFluid", c.VolumeDriveFluid, other.VolumeDriveFluid)
+	add("volumeContainer", c.VolumeContainer, other.VolumeContainer)
+	add("defaultAddress", c.DefaultAddress, other.DefaultAddress)
+	if !maps.Equal(c.Names, other.Names) {
+		fields = append(fields, &CommandFieldChange{Field: "names", Before: c.Names, After: other.Names})
+	}
+
+	return fields
+}
@@ -0,0 +1,159 @@
+package entity_test
+
+import (
+	"bytes"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"test_go/internal/entity"
+)
+
+func TestParseCommandCatalog(t *testing.T) {
+	catalog := `[
+		{"name":[{"locale":"en","value":"Fill"},{"locale":"ru","value":"Заполнить"}],"systemName":"fill",
+		 "reagent":"VER","averageTime":30,"volumeContainer":10,"defaultAddress":"RA"},
+		{"name":[{"locale":"en","value":"Flush"}],"systemName":"flush","averageTime":5}
+	]`
+
+	commands, err := entity.ParseCommandCatalog(strings.NewReader(catalog))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commands) != 2 {
+		t.Fatalf("got %d commands, want 2", len(commands))
+	}
+
+	fill := commands[0]
+	if fill.SystemName != "fill" || fill.Name != "Fill" || fill.Names["ru"] != "Заполнить" ||
+		fill.AverageTime != 30 || fill.DefaultAddress != entity.AddressRA {
+		t.Fatalf("unexpected command: %+v", fill)
+	}
+}
+
+func TestParseCommandCatalogReportsEveryIssue(t *testing.T) {
+	catalog := `[
+		{"name":[{"locale":"ru","value":"Заполнить"}],"systemName":"fill","averageTime":"slow"},
+		{"name":[{"locale":"en","value":"Fill"}],"systemName":"fill","defaultAddress":"ZZ","color":"red"},
+		42
+	]`
+
+	_, err := entity.ParseCommandCatalog(strings.NewReader(catalog))
+	if !errors.Is(err, entity.ErrInvalidCommandCatalog) {
+		t.Fatalf("got %v, want ErrInvalidCommandCatalog", err)
+	}
+
+	var report *entity.CommandCatalogReport
+	if !errors.As(err, &report) {
+		t.Fatalf("got %T, want *CommandCatalogReport", err)
+	}
+
+	paths := make(map[string]bool, len(report.Issues))
+	for _, issue := range report.Issues {
+		paths[issue.Path] = true
+	}
+	for _, path := range []string{"$[0].name", "$[0].averageTime", "$[1].systemName", "$[1].defaultAddress", "$[1].color", "$[2]"} {
+		if !paths[path] {
+			t.Errorf("no issue reported at %s, got %v", path, paths)
+		}
+	}
+}
+
+func TestParseCommandCatalogRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"not an array", []byte(`{"systemName":"fill"}`), entity.ErrInvalidCommandCatalog},
+		{"empty", []byte(`[]`), entity.ErrInvalidCommandCatalog},
+		{"too large", bytes.Repeat([]byte(" "), entity.CommandCatalogMaxSize+1), entity.ErrCommandCatalogTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := entity.ParseCommandCatalog(bytes.NewReader(tt.data)); !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRollbackToCatalogVersion(t *testing.T) {
+	version, err := entity.NewCommandCatalogVersion(map[string]entity.Command{
+		"fill":  {SystemName: "fill", Name: "Fill", AverageTime: 30},
+		"rinse": {SystemName: "rinse", Name: "Rinse", Disabled: true},
+		"prime": {SystemName: "prime", Name: "Prime"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stored := map[string]entity.Command{
+		"fill":  {Entity: entity.Entity{ID: 1}, SystemName: "fill", Name: "Fill", AverageTime: 45},
+		"rinse": {Entity: entity.Entity{ID: 2}, SystemName: "rinse", Name: "Rinse"},
+		"prime": {Entity: entity.Entity{ID: 3}, SystemName: "prime", Name: "Prime", Disabled: true},
+		"flush": {Entity: entity.Entity{ID: 4}, SystemName: "flush", Name: "Flush"},
+		"drain": {Entity: entity.Entity{ID: 5}, SystemName: "drain", Name: "Drain", Disabled: true},
+	}
+
+	diff := entity.DiffCommandCatalog(stored, version.CatalogCommands())
+	if len(diff.Changed) != 1 || diff.Changed[0].Command.ID != 1 || diff.Changed[0].Command.AverageTime != 30 {
+		t.Fatalf("unexpected changes: %+v", diff.Changed)
+	}
+	if len(diff.Missing) != 2 {
+		t.Fatalf("got %d missing commands, want 2", len(diff.Missing))
+	}
+
+	// Commands added after the version are disabled, the flags of the others follow the version.
+	want := map[string]bool{"rinse": true, "prime": false, "flush": true}
+	if got := version.DisabledChanges(stored); !maps.Equal(got, want) {
+		t.Fatalf("disabled changes: got %v, want %v", got, want)
+	}
+}
+
+func TestImportDisablesMissingCommands(t *testing.T) {
+	stored := map[string]entity.Command{
+		"fill":  {Entity: entity.Entity{ID: 1}, SystemName: "fill", Name: "Fill"},
+		"flush": {Entity: entity.Entity{ID: 2}, SystemName: "flush", Name: "Flush"},
+		"drain": {Entity: entity.Entity{ID: 3}, SystemName: "drain", Name: "Drain", Disabled: true},
+	}
+
+	diff := entity.DiffCommandCatalog(stored, []entity.Command{{SystemName: "fill", Name: "Fill"}})
+	if got := diff.MissingEnabled(); !slices.Equal(got, []string{"flush"}) {
+		t.Fatalf("missing enabled commands: got %v, want [flush]", got)
+	}
+	if !diff.HasChanges() {
+		t.Fatalf("a catalog without an enabled command reports no changes")
+	}
+}
+
+func TestCommandCatalogVersionChecksumIgnoresIds(t *testing.T) {
+	first, err := entity.NewCommandCatalogVersion(map[string]entity.Command{
+		"fill": {Entity: entity.Entity{ID: 1}, SystemName: "fill", Name: "Fill"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	second, err := entity.NewCommandCatalogVersion(map[string]entity.Command{
+		"fill": {Entity: entity.Entity{ID: 7}, SystemName: "fill", Name: "Fill"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.Checksum != second.Checksum {
+		t.Fatalf("checksums differ: %s and %s", first.Checksum, second.Checksum)
+	}
+
+	disabled, err := entity.NewCommandCatalogVersion(map[string]entity.Command{
+		"fill": {SystemName: "fill", Name: "Fill", Disabled: true},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if disabled.Checksum == first.Checksum {
+		t.Fatalf("disabling a command does not change the checksum")
+	}
+}
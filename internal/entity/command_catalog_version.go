@@ -0,0 +1,140 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// CommandCatalogVersion - an immutable snapshot of the stored commands, taken after
+// every import, patch or disable. Active marks the version the stored commands were last synced to.
+type CommandCatalogVersion struct {
+	ID        int64             `json:"id"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Checksum  string            `json:"checksum"`
+	AuthorID  *int64            `json:"authorId"`
+	Active    bool              `json:"active"`
+	Commands  []CommandSnapshot `json:"commands,omitempty"`
+}
+
+// CommandSnapshot - the catalog fields of a command and whether it is disabled,
+// ids are left out because they belong to the storage and not to the catalog.
+type CommandSnapshot struct {
+	SystemName       string         `json:"systemName"`
+	Name             string         `json:"name"`
+	Names            LocalizedNames `json:"names"`
+	Reagent          ReagentType    `json:"reagent"`
+	AverageTime      int64          `json:"averageTime"`
+	VolumeWaste      int64          `json:"volumeWaste"`
+	VolumeDriveFluid int64          `json:"volumeDriveFluid"`
+	VolumeContainer  int64          `json:"volumeContainer"`
+	DefaultAddress   Address        `json:"defaultAddress"`
+	Disabled         bool           `json:"disabled,omitempty"`
+}
+
+func (c *Command) Snapshot() CommandSnapshot {
+	return CommandSnapshot{
+		SystemName:       c.SystemName,
+		Name:             c.Name,
+		Names:            c.Names,
+		Reagent:          c.Reagent,
+		AverageTime:      c.AverageTime,
+		VolumeWaste:      c.VolumeWaste,
+		VolumeDriveFluid: c.VolumeDriveFluid,
+		VolumeContainer:  c.VolumeContainer,
+		DefaultAddress:   c.DefaultAddress,
+		Disabled:         c.Disabled,
+	}
+}
+
+func (s *CommandSnapshot) Command() Command {
+	return Command{
+		Name:             s.Name,
+		Names:            s.Names,
+		SystemName:       s.SystemName,
+		Reagent:          s.Reagent,
+		AverageTime:      s.AverageTime,
+		VolumeWaste:      s.VolumeWaste,
+		VolumeDriveFluid: s.VolumeDriveFluid,
+		VolumeContainer:  s.VolumeContainer,
+		DefaultAddress:   s.DefaultAddress,
+		Disabled:         s.Disabled,
+	}
+}
+
+// NewCommandCatalogVersion - snapshots the commands keyed by system name sorted by it,
+// so that the same commands always get the same checksum.
+func NewCommandCatalogVersion(stored map[string]Command, authorID *int64) (*CommandCatalogVersion, error) {
+	commands := make([]CommandSnapshot, 0, len(stored))
+	for _, cmd := range stored {
+		commands = append(commands, cmd.Snapshot())
+	}
+	sort.Slice(commands, func(i, j int) bool {
+		return commands[i].SystemName < commands[j].SystemName
+	})
+
+	data, err := json.Marshal(commands)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+
+	return &CommandCatalogVersion{
+		Checksum: hex.EncodeToString(sum[:]),
+		AuthorID: authorID,
+		Commands: commands,
+	}, nil
+}
+
+// Summary - the version without its snapshot.
+func (v *CommandCatalogVersion) Summary() *CommandCatalogVersion {
+	summary := *v
+	summary.Commands = nil
+	return &summary
+}
+
+// CatalogCommands - the snapshot as commands ready to be diffed against the stored ones.
+func (v *CommandCatalogVersion) CatalogCommands() []Command {
+	commands := make([]Command, 0, len(v.Commands))
+	for i := range v.Commands {
+		commands = append(commands, v.Commands[i].Command())
+	}
+	return commands
+}
+
+// DisabledChanges - the disabled flag each stored command needs to match the version,
+// stored commands the version does not have are disabled.
+func (v *CommandCatalogVersion) DisabledChanges(existing map[string]Command) map[string]bool {
+	changes := make(map[string]bool)
+	inVersion := make(map[string]struct{}, len(v.Commands))
+	for i := range v.Commands {
+		snapshot := &v.Commands[i]
+		inVersion[snapshot.SystemName] = struct{}{}
+
+		stored, ok := existing[snapshot.SystemName]
+		if ok && stored.Disabled == snapshot.Disabled || !ok && !snapshot.Disabled {
+			continue
+		}
+		changes[snapshot.SystemName] = snapshot.Disabled
+	}
+
+	for systemName, cmd := range existing {
+		if _, ok := inVersion[systemName]; !ok && !cmd.Disabled {
+			changes[systemName] = true
+		}
+	}
+
+	return changes
+}
+
+// CompareCommandCatalogVersions - what changes when moving from one version to the other.
+func CompareCommandCatalogVersions(from, to *CommandCatalogVersion) *CommandCatalogDiff {
+	existing := make(map[string]Command, len(from.Commands))
+	for i := range from.Commands {
+		existing[from.Commands[i].SystemName] = from.Commands[i].Command()
+	}
+
+	return DiffCommandCatalog(existing, to.CatalogCommands())
+}
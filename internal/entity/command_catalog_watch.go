@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// CommandCatalogWatchStatus - the state of the catalog file watcher. Checksum belongs to the
+// last applied file or the one found at startup, a failed reload keeps it and reports
+// the checksum and error of the rejected one.
+type CommandCatalogWatchStatus struct {
+	Enabled        bool                   `json:"enabled"`
+	Path           string                 `json:"path"`
+	Checksum       string                 `json:"checksum"`
+	CheckedAt      *time.Time             `json:"checkedAt"`
+	AppliedAt      *time.Time             `json:"appliedAt"`
+	FailedChecksum string                 `json:"failedChecksum,omitempty"`
+	FailedAt       *time.Time             `json:"failedAt,omitempty"`
+	Error          string                 `json:"error,omitempty"`
+	Issues         []*CommandCatalogIssue `json:"issues,omitempty"`
+}
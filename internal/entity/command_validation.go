@@ -0,0 +1,265 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MaxCommandAverageTime - the longest a single command may take, one day in seconds.
+const MaxCommandAverageTime int64 = 24 * 60 * 60
+
+// CommandCatalogIssue - a problem found in a catalog entry, Path points into the uploaded json.
+type CommandCatalogIssue struct {
+	Index   int    `json:"index"`
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// CommandCatalogReport - every issue found in a catalog, returned as an error when it is not empty.
+type CommandCatalogReport struct {
+	Issues []*CommandCatalogIssue `json:"issues"`
+}
+
+func (r *CommandCatalogReport) Error() string {
+	return fmt.Sprintf("%s: %d issue(s)", ErrInvalidCommandCatalog, len(r.Issues))
+}
+
+func (r *CommandCatalogReport) Unwrap() error {
+	return ErrInvalidCommandCatalog
+}
+
+func (r *CommandCatalogReport) add(index int, field, format string, args ...any) {
+	path := fmt.Sprintf("$[%d]", index)
+	if field != "" {
+		path += "." + field
+	}
+	r.Issues = append(r.Issues, &CommandCatalogIssue{
+		Index:   index,
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+var commandRanges = []struct {
+	field    string
+	value    func(*CommandJSON) int64
+	min, max int64
+}{
+	{"averageTime", func(c *CommandJSON) int64 { return c.AverageTime }, 0, MaxCommandAverageTime},
+	{"volumeWaste", func(c *CommandJSON) int64 { return c.VolumeWaste }, 0, LargeContainerVolume},
+	{"volumeDriveFluid", func(c *CommandJSON) int64 { return c.VolumeDriveFluid }, 0, LargeContainerVolume},
+	{"volumeContainer", func(c *CommandJSON) int64 { return c.VolumeContainer }, 0, LargeContainerVolume},
+}
+
+// PatchCommandInput - a merge patch of the tunable values of a single command.
+type PatchCommandInput struct {
+	SystemName       string            `json:"-"`
+	AverageTime      PatchField[int64] `json:"averageTime"`
+	VolumeWaste      PatchField[int64] `json:"volumeWaste"`
+	VolumeDriveFluid PatchField[int64] `json:"volumeDriveFluid"`
+	VolumeContainer  PatchField[int64] `json:"volumeContainer"`
+	User             *UserInfoToken    `json:"-"`
+}
+
+// Validate - the values are checked against the same ranges as the catalog entries.
+func (inp PatchCommandInput) Validate() error {
+	fields := map[string]PatchField[int64]{
+		"averageTime":      inp.AverageTime,
+		"volumeWaste":      inp.VolumeWaste,
+		"volumeDriveFluid": inp.VolumeDriveFluid,
+		"volumeContainer":  inp.VolumeContainer,
+	}
+
+	for _, r := range commandRanges {
+		f := fields[r.field]
+		if err := f.Required(r.field); err != nil {
+			return err
+		}
+		if f.Present && (f.Value < r.min || f.Value > r.max) {
+			return fmt.Errorf("%w: %s must be between %d and %d, got %d", ErrInvalidCommandValue, r.field, r.min, r.max, f.Value)
+		}
+	}
+
+	return nil
+}
+
+// Apply - the command with the patch merged in, the container volume must still fit the default address.
+func (inp PatchCommandInput) Apply(c Command) (*Command, error) {
+	inp.AverageTime.Apply(&c.AverageTime)
+	inp.VolumeWaste.Apply(&c.VolumeWaste)
+	inp.VolumeDriveFluid.Apply(&c.VolumeDriveFluid)
+	inp.VolumeContainer.Apply(&c.VolumeContainer)
+
+	if c.DefaultAddress.IsValid() && c.VolumeContainer > c.DefaultAddress.Capacity() {
+		return nil, fmt.Errorf("%w: volume %d does not fit the %s container of %d",
+			ErrInvalidCommandValue, c.VolumeContainer, c.DefaultAddress, c.DefaultAddress.Capacity())
+	}
+
+	return &c, nil
+}
+
+// ValidateCommandCatalog - decodes every catalog entry on its own and checks it against the schema,
+// so that one report lists the problems of the whole file instead of the first one.
+// The returned error is a *CommandCatalogReport when the entries themselves are invalid.
+func ValidateCommandCatalog(data []byte) ([]CommandJSON, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("%w: the catalog must be a json array: %w", ErrInvalidCommandCatalog, err)
+	}
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: no commands", ErrInvalidCommandCatalog)
+	}
+
+	report := &CommandCatalogReport{}
+	commands := make([]CommandJSON, len(items))
+	firstIndex := make(map[string]int, len(items))
+	for i, item := range items {
+		cmd := &commands[i]
+		failed, ok := cmd.decodeFields(i, item, report)
+		if !ok {
+			continue
+		}
+
+		cmd.validate(i, failed, report)
+
+		if cmd.SystemName == "" || failed["systemName"] {
+			continue
+		}
+		if first, ok := firstIndex[cmd.SystemName]; ok {
+			report.add(i, "systemName", "duplicate system name %q, first used at index %d", cmd.SystemName, first)
+			continue
+		}
+		firstIndex[cmd.SystemName] = i
+	}
+
+	if len(report.Issues) > 0 {
+		return nil, report
+	}
+	return commands, nil
+}
+
+// decodeFields - decodes the entry field by field, so that a wrong type or an unknown field
+// is reported without hiding the problems of the other fields. Returns the fields that failed.
+func (raw *CommandJSON) decodeFields(index int, item json.RawMessage, report *CommandCatalogReport) (map[string]bool, bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(item, &fields); err != nil || fields == nil {
+		report.add(index, "", "command must be a json object")
+		return nil, false
+	}
+
+	targets := map[string]any{
+		"name":             &raw.Name,
+		"systemName":       &raw.SystemName,
+		"reagent":          &raw.Reagent,
+		"averageTime":      &raw.AverageTime,
+		"volumeWaste":      &raw.VolumeWaste,
+		"volumeDriveFluid": &raw.VolumeDriveFluid,
+		"volumeContainer":  &raw.VolumeContainer,
+		"defaultAddress":   &raw.DefaultAddress,
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	failed := make(map[string]bool)
+	for _, key := range keys {
+		target, ok := targets[key]
+		if !ok {
+			report.add(index, key, "unknown field")
+			continue
+		}
+		if err := json.Unmarshal(fields[key], target); err != nil {
+			failed[key] = true
+			report.add(index, key, "%s", decodeErrorMessage(err))
+		}
+	}
+
+	return failed, true
+}
+
+// validate - checks the decoded values, fields that failed to decode are already reported and skipped.
+func (raw *CommandJSON) validate(index int, failed map[string]bool, report *CommandCatalogReport) {
+	if !failed["name"] {
+		if raw.EnglishName() == "" {
+			report.add(index, "name", "english name is required")
+		}
+		seen := make(map[string]int, len(raw.Name))
+		for i, n := range raw.Name {
+			path := fmt.Sprintf("name[%d].locale", i)
+			switch first, ok := seen[strings.ToLower(n.Locale)]; {
+			case !IsValidLocale(n.Locale):
+				report.add(index, path, "invalid locale %q", n.Locale)
+			case ok:
+				report.add(index, path, "duplicate locale %q, first used at name[%d]", n.Locale, first)
+			default:
+				seen[strings.ToLower(n.Locale)] = i
+			}
+			if n.Value == "" {
+				report.add(index, fmt.Sprintf("name[%d].value", i), "name is required")
+			}
+		}
+	}
+
+	if !failed["systemName"] && strings.TrimSpace(raw.SystemName) == "" {
+		report.add(index, "systemName", "system name is required")
+	}
+
+	if !failed["reagent"] && raw.Reagent != nil && !ReagentType(*raw.Reagent).IsValid() {
+		report.add(index, "reagent", "unknown reagent %q", *raw.Reagent)
+	}
+
+	if !failed["defaultAddress"] && raw.DefaultAddress != nil {
+		address := Address(*raw.DefaultAddress)
+		if !address.IsValid() {
+			report.add(index, "defaultAddress", "unknown address %q", *raw.DefaultAddress)
+		} else if !failed["volumeContainer"] && raw.VolumeContainer > address.Capacity() {
+			report.add(index, "volumeContainer", "volume %d does not fit the %s container of %d",
+				raw.VolumeContainer, address, address.Capacity())
+		}
+	}
+
+	for _, r := range commandRanges {
+		if failed[r.field] {
+			continue
+		}
+		if v := r.value(raw); v < r.min || v > r.max {
+			report.add(index, r.field, "must be between %d and %d, got %d", r.min, r.max, v)
+		}
+	}
+}
+
+func decodeErrorMessage(err error) string {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return fmt.Sprintf("expected %s, got %s", jsonKind(typeErr.Type), typeErr.Value)
+	}
+	return err.Error()
+}
+
+// jsonKind - the json name of the go type a value is decoded into.
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return jsonKind(t.Elem())
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	}
+	return t.Kind().String()
+}
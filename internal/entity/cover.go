@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// CoverVariantOriginal - the uploaded image as is, thumbnails are named by their size.
+const CoverVariantOriginal = "original"
+
+// CoverContentTypes - accepted cover formats detected by content sniffing, with the stored file extension.
+var CoverContentTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+// CoverSettings - upload size limit and the longest side of every generated thumbnail.
+// MaxPixels limits the decoded image, a small file may declare a huge one.
+type CoverSettings struct {
+	MaxSize        int64
+	MaxPixels      int
+	ThumbnailSizes []int
+}
+
+// CoverImage - a processed upload: the original and its thumbnails keyed by variant.
+type CoverImage struct {
+	Hash        string
+	ContentType string
+	Files       map[string][]byte
+}
+
+func CoverVariant(size int) string {
+	if size <= 0 {
+		return CoverVariantOriginal
+	}
+	return strconv.Itoa(size)
+}
+
+func CoverFileName(variant, contentType string) string {
+	return variant + CoverContentTypes[contentType]
+}
+
+// CoverURL - the hash in the query makes clients fetch the new image once the cover changes.
+func CoverURL(bookID int64, hash string) string {
+	if len(hash) > 12 {
+		hash = hash[:12]
+	}
+	return fmt.Sprintf("/v1/book/%d/cover?v=%s", bookID, hash)
+}
+
+type CoverFile struct {
+	Path        string
+	ContentType string
+	ETag        string
+	ModTime     time.Time
+}
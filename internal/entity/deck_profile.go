@@ -0,0 +1,147 @@
+package entity
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	DeckGroupSmall = "small"
+	DeckGroupLarge = "large"
+)
+
+// DeckPosition - a container slot of the deck. Reagents lists the reagent types
+// the container may hold, an empty list allows any of them.
+type DeckPosition struct {
+	Address  Address       `json:"address"`
+	Capacity int64         `json:"capacity"`
+	Reagents []ReagentType `json:"reagents,omitempty"`
+	Group    string        `json:"group"`
+}
+
+func (p *DeckPosition) Allows(reagent ReagentType) bool {
+	return len(p.Reagents) == 0 || slices.Contains(p.Reagents, reagent)
+}
+
+// DeckProfile - the container layout of an instrument. Operations are validated against
+// the profile they reference, or the default one when they reference none.
+type DeckProfile struct {
+	ID        int64          `json:"id"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	Name      string         `json:"name"`
+	Default   bool           `json:"default"`
+	Positions []DeckPosition `json:"positions"`
+}
+
+type CreateDeckProfileInput struct {
+	Name      string         `json:"name"`
+	Positions []DeckPosition `json:"positions"`
+}
+
+// UpdateDeckProfileInput - a merge patch of a profile, positions are replaced as a whole.
+type UpdateDeckProfileInput struct {
+	ID        int64                      `json:"-"`
+	Name      PatchField[string]         `json:"name"`
+	Positions PatchField[[]DeckPosition] `json:"positions"`
+}
+
+func (inp UpdateDeckProfileInput) Validate() error {
+	if err := RequiredNonZero(inp.Name, "name"); err != nil {
+		return err
+	}
+	return inp.Positions.Required("positions")
+}
+
+// standardDeck - the layout the addresses were hard-coded to before profiles existed,
+// used when no default profile is stored and for the default addresses of the catalog.
+var standardDeck = &DeckProfile{
+	Name:    "standard",
+	Default: true,
+	Positions: []DeckPosition{
+		{Address: AddressRA, Capacity: LargeContainerVolume, Group: DeckGroupLarge},
+		{Address: AddressRB, Capacity: LargeContainerVolume, Group: DeckGroupLarge},
+		{Address: AddressRC, Capacity: LargeContainerVolume, Group: DeckGroupLarge},
+		{Address: AddressRD, Capacity: LargeContainerVolume, Group: DeckGroupLarge},
+		{Address: AddressSA, Capacity: SmallContainerVolume, Group: DeckGroupSmall},
+		{Address: AddressSB, Capacity: SmallContainerVolume, Group: DeckGroupSmall},
+		{Address: AddressSC, Capacity: SmallContainerVolume, Group: DeckGroupSmall},
+		{Address: AddressSD, Capacity: SmallContainerVolume, Group: DeckGroupSmall},
+		{Address: AddressSE, Capacity: SmallContainerVolume, Group: DeckGroupSmall},
+		{Address: AddressSF, Capacity: SmallContainerVolume, Group: DeckGroupSmall},
+		{Address: AddressSG, Capacity: SmallContainerVolume, Group: DeckGroupSmall},
+		{Address: AddressSH, Capacity: SmallContainerVolume, Group: DeckGroupSmall},
+	},
+}
+
+// StandardDeckProfile - a copy of the built-in layout.
+func StandardDeckProfile() *DeckProfile {
+	deck := *standardDeck
+	deck.Positions = slices.Clone(standardDeck.Positions)
+	return &deck
+}
+
+// Position - the slot at the address, false when the deck has none.
+func (d *DeckProfile) Position(address Address) (*DeckPosition, bool) {
+	for i := range d.Positions {
+		if d.Positions[i].Address == address {
+			return &d.Positions[i], true
+		}
+	}
+	return nil, false
+}
+
+// ParseAddress - the address of the deck written as s, case and surrounding spaces are ignored.
+func (d *DeckProfile) ParseAddress(s string) (Address, error) {
+	address := Address(strings.ToUpper(strings.TrimSpace(s)))
+	if _, ok := d.Position(address); !ok {
+		return "", fmt.Errorf("%w: %q on deck %q", ErrUnknownDeckAddress, s, d.Name)
+	}
+	return address, nil
+}
+
+// Normalize - trims the name and upper-cases the addresses, so that they parse back to themselves.
+func (d *DeckProfile) Normalize() {
+	d.Name = strings.TrimSpace(d.Name)
+	for i := range d.Positions {
+		d.Positions[i].Address = Address(strings.ToUpper(strings.TrimSpace(string(d.Positions[i].Address))))
+		d.Positions[i].Group = strings.TrimSpace(d.Positions[i].Group)
+	}
+}
+
+// Validate - reports every problem of the layout in one ErrInvalidDeckProfile error.
+func (d *DeckProfile) Validate() error {
+	var issues []string
+	if d.Name == "" {
+		issues = append(issues, "name is required")
+	}
+	if len(d.Positions) == 0 {
+		issues = append(issues, "at least one position is required")
+	}
+
+	seen := make(map[Address]int, len(d.Positions))
+	for i, p := range d.Positions {
+		if p.Address == "" {
+			issues = append(issues, fmt.Sprintf("positions[%d].address is required", i))
+		} else if first, ok := seen[p.Address]; ok {
+			issues = append(issues, fmt.Sprintf("positions[%d].address %q is already used by positions[%d]", i, p.Address, first))
+		} else {
+			seen[p.Address] = i
+		}
+		if p.Capacity <= 0 {
+			issues = append(issues, fmt.Sprintf("positions[%d].capacity must be positive, got %d", i, p.Capacity))
+		}
+		for _, r := range p.Reagents {
+			if !r.IsValid() {
+				issues = append(issues, fmt.Sprintf("positions[%d].reagents: unknown reagent %q", i, r))
+			}
+		}
+	}
+
+	if len(issues) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidDeckProfile, strings.Join(issues, "; "))
+	}
+	return nil
+}
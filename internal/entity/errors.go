@@ -16,15 +16,75 @@ var (
 	ErrAuthorNotFound = errors.New("author not found")
 	ErrBookNotFound   = errors.New("book not found")
 
+	ErrAuthorHasBooks         = errors.New("author has books")
+	ErrInvalidDeletePolicy    = errors.New("invalid delete policy")
+	ErrReassignAuthorRequired = errors.New("another author to reassign books to is required")
+	ErrNotInTrash             = errors.New("record is not in trash")
+
+	ErrUnsupportedImportFormat = errors.New("unsupported import file format, only csv and xlsx are allowed")
+	ErrImportColumnNotFound    = errors.New("required column not found in import file")
+	ErrInvalidImportMode       = errors.New("invalid import mode")
+	ErrEmptyImportFile         = errors.New("import file has no rows")
+	ErrImportFileTooLarge      = errors.New("import file is too large")
+	ErrTooManyImportRows       = errors.New("import file has too many rows")
+
+	ErrShelfNotFound        = errors.New("shelf not found")
+	ErrShelfBookNotFound    = errors.New("book is not on the shelf")
+	ErrShelfNameUsed        = errors.New("shelf name already used")
+	ErrDefaultShelfDelete   = errors.New("default shelves cannot be deleted")
+	ErrInvalidShelfProgress = errors.New("progress must be between 0 and 100")
+
+	ErrReviewNotFound       = errors.New("review not found")
+	ErrReviewAlreadyExists  = errors.New("book already reviewed by the user")
+	ErrReviewAlreadyFlagged = errors.New("review already flagged by the user")
+	ErrInvalidRating        = errors.New("rating must be between 1 and 5")
+	ErrInvalidReviewStatus  = errors.New("invalid review moderation status")
+
+	ErrCoverNotFound        = errors.New("book has no cover")
+	ErrCoverTooLarge        = errors.New("cover image is too large")
+	ErrUnsupportedCoverType = errors.New("unsupported cover image, only jpeg and png are allowed")
+
+	ErrRevisionNotFound      = errors.New("revision not found")
+	ErrRevisionNotRestorable = errors.New("revision has no state to restore")
+
+	ErrPatchFieldRequired = errors.New("field cannot be cleared")
+	ErrUserNameUsed       = errors.New("username already used")
+	ErrInvalidLocale      = errors.New("invalid locale")
+
 	ErrPasswordMismatch = errors.New("newPassword and confirmPassword must be the same")
 
 	ErrOpenFile   = errors.New("failed to open file")
 	ErrCreateFile = errors.New("failed to create file")
 	ErrSaveFile   = errors.New("failed to save file")
 
-	ErrCommandNotFound         = errors.New("command not found")
-	ErrCommandDuplicateAddress = errors.New("address is used by multiple commands")
-	ErrCommandVolumeExceeded   = errors.New("volume exceeded")
-	ErrCommandNameNotFound     = errors.New("command name not found")
-	ErrOperationNotFound       = errors.New("operation not found")
+	ErrCommandNotFound               = errors.New("command not found")
+	ErrCommandDuplicateAddress       = errors.New("address is used by multiple commands")
+	ErrCommandVolumeExceeded         = errors.New("volume exceeded")
+	ErrCommandNameNotFound           = errors.New("command name not found")
+	ErrCommandAlreadyExists          = errors.New("command already exists")
+	ErrCommandDisabled               = errors.New("command is disabled")
+	ErrInvalidCommandValue           = errors.New("command value out of range")
+	ErrInvalidCommandCatalog         = errors.New("invalid command catalog")
+	ErrCommandCatalogTooLarge        = errors.New("command catalog is too large")
+	ErrCommandCatalogVersionNotFound = errors.New("command catalog version not found")
+	ErrOperationNotFound             = errors.New("operation not found")
+	ErrInvalidOperation              = errors.New("invalid operation")
+	ErrEmptyOperationCommand         = errors.New("operation command is empty")
+	ErrStoreMigrationMismatch        = errors.New("store migration verification failed")
+	ErrDeckProfileNotFound           = errors.New("deck profile not found")
+	ErrDeckProfileAlreadyExists      = errors.New("deck profile already exists")
+	ErrDeckProfileIsDefault          = errors.New("default deck profile cannot be deleted")
+	ErrDeckProfileInUse              = errors.New("deck profile is used by operations or instruments")
+	ErrInvalidDeckProfile            = errors.New("invalid deck profile")
+	ErrUnknownDeckAddress            = errors.New("address is not on the deck")
+	ErrReagentNotAllowed             = errors.New("reagent is not allowed at the address")
+	ErrNoFeasibleAddress             = errors.New("no container fits the command")
+	ErrOperationRunNotFound          = errors.New("operation run not found")
+	ErrOperationHasNoCommands        = errors.New("operation has no commands to run")
+	ErrInvalidRunTransition          = errors.New("invalid operation run transition")
+	ErrInvalidRunState               = errors.New("invalid operation run state")
+	ErrInvalidRunPriority            = errors.New("invalid operation run priority")
+	ErrInstrumentNotFound            = errors.New("instrument not found")
+	ErrInstrumentAlreadyExists       = errors.New("instrument already exists")
+	ErrQueueConflict                 = errors.New("instrument queue conflict")
 )
@@ -0,0 +1,90 @@
+package entity
+
+import "mime/multipart"
+
+const (
+	// ImportMaxSize - the largest csv or xlsx file that is read.
+	ImportMaxSize = 10 << 20
+	// ImportMaxRows - the most rows a file may have, the header not included.
+	ImportMaxRows = 10000
+)
+
+type ImportMode string
+
+const (
+	// ImportModeAtomic - the whole file is imported in one transaction, any failed row rolls everything back.
+	ImportModeAtomic ImportMode = "atomic"
+	// ImportModeBestEffort - every row is imported on its own, failed rows are reported and skipped.
+	ImportModeBestEffort ImportMode = "best_effort"
+)
+
+func (m ImportMode) IsValid() bool {
+	return m == ImportModeAtomic || m == ImportModeBestEffort
+}
+
+type ImportRowStatus string
+
+const (
+	ImportRowStatusCreated ImportRowStatus = "created"
+	ImportRowStatusUpdated ImportRowStatus = "updated"
+	ImportRowStatusSkipped ImportRowStatus = "skipped"
+	ImportRowStatusFailed  ImportRowStatus = "failed"
+)
+
+// ImportColumnMapping - names of the header cells holding each field.
+type ImportColumnMapping struct {
+	BookID string `json:"bookId"`
+	Title  string `json:"title"`
+	Author string `json:"author"`
+	Gender string `json:"gender"`
+}
+
+func DefaultImportColumnMapping() ImportColumnMapping {
+	return ImportColumnMapping{
+		BookID: "id",
+		Title:  "title",
+		Author: "author",
+		Gender: "gender",
+	}
+}
+
+type ImportLibraryInput struct {
+	File    *multipart.FileHeader
+	Mapping ImportColumnMapping
+	Mode    ImportMode
+	DryRun  bool
+	User    *UserInfoToken
+}
+
+type ImportRowResult struct {
+	Row      int             `json:"row"`
+	Status   ImportRowStatus `json:"status"`
+	BookID   *int64          `json:"bookId,omitempty"`
+	AuthorID *int64          `json:"authorId,omitempty"`
+	Message  string          `json:"message,omitempty"`
+}
+
+type ImportReport struct {
+	Mode      ImportMode         `json:"mode"`
+	DryRun    bool               `json:"dryRun"`
+	Committed bool               `json:"committed"`
+	Created   int                `json:"created"`
+	Updated   int                `json:"updated"`
+	Skipped   int                `json:"skipped"`
+	Failed    int                `json:"failed"`
+	Rows      []*ImportRowResult `json:"rows"`
+}
+
+func (r *ImportReport) Add(row *ImportRowResult) {
+	switch row.Status {
+	case ImportRowStatusCreated:
+		r.Created++
+	case ImportRowStatusUpdated:
+		r.Updated++
+	case ImportRowStatusSkipped:
+		r.Skipped++
+	case ImportRowStatusFailed:
+		r.Failed++
+	}
+	r.Rows = append(r.Rows, row)
+}
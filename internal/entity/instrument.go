@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// Instrument - a device the runs are executed on, one at a time in the order of its queue.
+// DeckProfileID is the deck mounted on it, nil for the default deck.
+type Instrument struct {
+	ID            int64     `json:"id"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+	Name          string    `json:"name"`
+	DeckProfileID *int64    `json:"deckProfileId"`
+}
+
+type CreateInstrumentInput struct {
+	Name          string
+	DeckProfileID *int64
+}
@@ -0,0 +1,140 @@
+package entity
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// InstrumentQueue - the runs on the instrument and the runs waiting for it, in the order
+// they are expected to start. FreeAt is when the last of them is expected to finish.
+type InstrumentQueue struct {
+	Instrument *Instrument   `json:"instrument"`
+	Entries    []*QueueEntry `json:"entries"`
+	FreeAt     time.Time     `json:"freeAt"`
+}
+
+// QueueEntry - a run with its estimates, Position is 0 for a run already on the instrument.
+type QueueEntry struct {
+	Position        int               `json:"position"`
+	RunID           int64             `json:"runId"`
+	OperationID     string            `json:"operationId"`
+	OperationName   string            `json:"operationName"`
+	State           OperationRunState `json:"state"`
+	Priority        int               `json:"priority"`
+	StartedBy       *int64            `json:"startedBy"`
+	EstimatedStart  time.Time         `json:"estimatedStart"`
+	EstimatedFinish time.Time         `json:"estimatedFinish"`
+}
+
+type ReorderQueueInput struct {
+	InstrumentID int64
+	RunIDs       []int64
+}
+
+// NewInstrumentQueue - the estimates as of now. A started run finishes after its remaining steps,
+// a paused one as if it was resumed now, and each queued run starts when the one before it
+// finishes and takes the average time of its operation.
+func NewInstrumentQueue(instrument *Instrument, started, queued []*OperationRun, now time.Time, timeUnit time.Duration) *InstrumentQueue {
+	q := &InstrumentQueue{
+		Instrument: instrument,
+		Entries:    make([]*QueueEntry, 0, len(started)+len(queued)),
+		FreeAt:     now,
+	}
+
+	for _, run := range started {
+		start := now
+		if run.StartedAt != nil {
+			start = *run.StartedAt
+		}
+		finish := now.Add(run.Remaining(now, timeUnit))
+		q.Entries = append(q.Entries, newQueueEntry(run, 0, start, finish))
+		q.FreeAt = maxTime(q.FreeAt, finish)
+	}
+
+	for i, run := range queued {
+		finish := q.FreeAt.Add(run.expectedDuration(timeUnit))
+		q.Entries = append(q.Entries, newQueueEntry(run, i+1, q.FreeAt, finish))
+		q.FreeAt = finish
+	}
+
+	return q
+}
+
+func newQueueEntry(run *OperationRun, position int, start, finish time.Time) *QueueEntry {
+	return &QueueEntry{
+		Position:        position,
+		RunID:           run.ID,
+		OperationID:     run.OperationID,
+		OperationName:   run.OperationName,
+		State:           run.State,
+		Priority:        run.Priority,
+		StartedBy:       run.StartedBy,
+		EstimatedStart:  start,
+		EstimatedFinish: finish,
+	}
+}
+
+// expectedDuration - the average time of the operation, the sum of the steps when it has none.
+func (r *OperationRun) expectedDuration(timeUnit time.Duration) time.Duration {
+	if r.AverageTime > 0 {
+		return time.Duration(r.AverageTime) * timeUnit
+	}
+	total, _ := r.measure(time.Time{}, timeUnit)
+	return total
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// Enqueue - puts the run after the queued runs of the same or a higher priority, so runs of
+// one priority start in the order they came. It returns the other runs whose position changed.
+func Enqueue(queue []*OperationRun, run *OperationRun) []*OperationRun {
+	i := len(queue)
+	for i > 0 && queue[i-1].Priority < run.Priority {
+		i--
+	}
+
+	moved := numberQueue(slices.Insert(slices.Clone(queue), i, run))
+	return slices.DeleteFunc(moved, func(r *OperationRun) bool { return r == run })
+}
+
+// ReorderQueue - the queued runs in the order of ids, which has to list each of them once.
+// A list made before the queue changed is a conflict. It returns the runs whose position changed.
+func ReorderQueue(queue []*OperationRun, ids []int64) ([]*OperationRun, error) {
+	byID := make(map[int64]*OperationRun, len(queue))
+	for _, run := range queue {
+		byID[run.ID] = run
+	}
+	if len(ids) != len(queue) {
+		return nil, fmt.Errorf("%w: the queue has %d runs, %d given", ErrQueueConflict, len(queue), len(ids))
+	}
+
+	ordered := make([]*OperationRun, 0, len(ids))
+	for _, id := range ids {
+		run, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: run %d is not queued on the instrument or is listed twice", ErrQueueConflict, id)
+		}
+		delete(byID, id)
+		ordered = append(ordered, run)
+	}
+
+	return numberQueue(ordered), nil
+}
+
+// numberQueue - sets the positions from 1 and returns the runs whose position changed.
+func numberQueue(queue []*OperationRun) []*OperationRun {
+	moved := make([]*OperationRun, 0, len(queue))
+	for i, run := range queue {
+		if run.QueuePosition != i+1 {
+			run.QueuePosition = i + 1
+			moved = append(moved, run)
+		}
+	}
+	return moved
+}
@@ -0,0 +1,95 @@
+package entity_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"test_go/internal/entity"
+)
+
+func queuedRuns(priorities ...int) []*entity.OperationRun {
+	runs := make([]*entity.OperationRun, 0, len(priorities))
+	for i, p := range priorities {
+		runs = append(runs, &entity.OperationRun{
+			ID:            int64(i + 1),
+			State:         entity.OperationRunQueued,
+			Priority:      p,
+			QueuePosition: i + 1,
+			AverageTime:   10,
+		})
+	}
+	return runs
+}
+
+func TestEnqueueKeepsOrderWithinPriority(t *testing.T) {
+	queue := queuedRuns(5, 5, 0, 0)
+	run := &entity.OperationRun{ID: 9, Priority: 5}
+
+	moved := entity.Enqueue(queue, run)
+	if run.QueuePosition != 3 {
+		t.Fatalf("position: got %d, want 3", run.QueuePosition)
+	}
+	if len(moved) != 2 || moved[0].ID != 3 || moved[0].QueuePosition != 4 || moved[1].ID != 4 || moved[1].QueuePosition != 5 {
+		t.Fatalf("unexpected moved runs: %+v", moved)
+	}
+
+	low := &entity.OperationRun{ID: 10}
+	if moved = entity.Enqueue(queuedRuns(5, 5, 0, 0), low); len(moved) != 0 || low.QueuePosition != 5 {
+		t.Fatalf("lowest priority: position %d, moved %d", low.QueuePosition, len(moved))
+	}
+}
+
+func TestReorderQueueDetectsStaleOrder(t *testing.T) {
+	queue := queuedRuns(0, 0, 0)
+
+	if _, err := entity.ReorderQueue(queue, []int64{3, 1}); !errors.Is(err, entity.ErrQueueConflict) {
+		t.Fatalf("missing run: got %v, want ErrQueueConflict", err)
+	}
+	if _, err := entity.ReorderQueue(queue, []int64{3, 3, 1}); !errors.Is(err, entity.ErrQueueConflict) {
+		t.Fatalf("duplicate run: got %v, want ErrQueueConflict", err)
+	}
+
+	moved, err := entity.ReorderQueue(queue, []int64{3, 1, 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(moved) != 3 || queue[2].QueuePosition != 1 || queue[0].QueuePosition != 2 || queue[1].QueuePosition != 3 {
+		t.Fatalf("unexpected positions: %d %d %d", queue[0].QueuePosition, queue[1].QueuePosition, queue[2].QueuePosition)
+	}
+}
+
+func TestInstrumentQueueEstimates(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	started := now.Add(-2 * time.Second)
+	running := &entity.OperationRun{
+		ID:        1,
+		State:     entity.OperationRunRunning,
+		StartedAt: &started,
+		Steps: []*entity.OperationRunStep{
+			{AverageTime: 5, State: entity.OperationRunStepRunning, StartedAt: &started},
+		},
+	}
+
+	q := entity.NewInstrumentQueue(&entity.Instrument{ID: 1}, []*entity.OperationRun{running}, queuedRuns(0, 0), now, time.Second)
+	if len(q.Entries) != 3 {
+		t.Fatalf("entries: got %d, want 3", len(q.Entries))
+	}
+
+	want := []struct {
+		start, finish time.Duration
+	}{
+		{-2 * time.Second, 3 * time.Second},
+		{3 * time.Second, 13 * time.Second},
+		{13 * time.Second, 23 * time.Second},
+	}
+	for i, w := range want {
+		e := q.Entries[i]
+		if e.Position != i || !e.EstimatedStart.Equal(now.Add(w.start)) || !e.EstimatedFinish.Equal(now.Add(w.finish)) {
+			t.Errorf("entry %d: position %d, %v - %v", i, e.Position, e.EstimatedStart.Sub(now), e.EstimatedFinish.Sub(now))
+		}
+	}
+	if !q.FreeAt.Equal(now.Add(23 * time.Second)) {
+		t.Errorf("free at: got %v", q.FreeAt.Sub(now))
+	}
+}
@@ -0,0 +1,41 @@
+package entity
+
+import "strings"
+
+// DefaultLocale - the locale every catalog entry has, used when a translation is missing.
+const DefaultLocale = "en"
+
+// LocalizedNames - names of a catalog entry by locale.
+type LocalizedNames map[string]string
+
+// Get - the name for the locale, tries the exact tag, then its language, then the default locale.
+func (n LocalizedNames) Get(locale string) string {
+	locale = strings.ToLower(locale)
+	if name := n[locale]; name != "" {
+		return name
+	}
+	if name := n[LocaleLanguage(locale)]; name != "" {
+		return name
+	}
+	return n[DefaultLocale]
+}
+
+// LocaleLanguage - the language subtag of the locale, "pt-BR" becomes "pt".
+func LocaleLanguage(locale string) string {
+	language, _, _ := strings.Cut(strings.ToLower(locale), "-")
+	return language
+}
+
+// IsValidLocale - a language of two or three latin letters with optional subtags.
+func IsValidLocale(locale string) bool {
+	language := LocaleLanguage(locale)
+	if len(language) < 2 || len(language) > 3 {
+		return false
+	}
+	for _, r := range locale {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
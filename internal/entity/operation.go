@@ -1,5 +1,7 @@
 package entity
 
+import "time"
+
 type OperationCommand struct {
 	ID          int64
 	OperationID int64
@@ -7,21 +9,42 @@ type OperationCommand struct {
 	Address Address
 }
 
+// Operation - an operation of the configured storage backend, ID is opaque to the callers:
+// a decimal number for postgres and an ObjectID hex for mongo.
 type Operation struct {
-	Entity
+	ID          string    `json:"id"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
 	Name        string
 	Description string
 	AverageTime int64
 	Commands    []*OperationCommand
+	// CatalogVersionID - the catalog version the commands were resolved against.
+	CatalogVersionID *int64
+	// DeckProfileID - the deck the commands are placed on, nil for the default deck.
+	DeckProfileID *int64
+	// SourceID - the id the operation had in the other backend before it was migrated.
+	SourceID string `json:"-"`
+	// Assignments - the addresses the planner picked while the operation was saved, they are not stored.
+	Assignments []*AddressAssignment `json:"assignments,omitempty"`
 }
 
+// UpdateOperationInput - a merge patch of an operation, commands are replaced as a whole.
+// A full update is a patch with every field present.
 type UpdateOperationInput struct {
-	ID          int64           `json:"id"`
-	Name        string          `json:"name"`
-	Description string          `json:"description"`
-	Commands    []*CommandInput `json:"commands"`
+	ID          string                      `json:"id"`
+	Name        PatchField[string]          `json:"name"`
+	Description PatchField[string]          `json:"description"`
+	Commands    PatchField[[]*CommandInput] `json:"commands"`
+	// DeckProfileID - null moves the operation to the default deck.
+	DeckProfileID PatchField[*int64] `json:"deckProfileId"`
+}
+
+func (inp UpdateOperationInput) Validate() error {
+	return RequiredNonZero(inp.Name, "name")
 }
 
+// CommandInput - an empty Address leaves the container to the planner.
 type CommandInput struct {
 	ID         *int64
 	SystemName string
@@ -33,6 +56,8 @@ type CreateOperationInput struct {
 	Description string
 	AverageTime int64
 	Commands    []*CommandInput
+	// DeckProfileID - nil places the commands on the default deck.
+	DeckProfileID *int64
 }
 
 func (e *Operation) SumAverageTime() error {
@@ -43,3 +68,14 @@ func (e *Operation) SumAverageTime() error {
 	e.AverageTime = total
 	return nil
 }
+
+// LocalizeOperationCommands - names the commands of an operation in the locale
+// using the translations of the current catalog.
+func LocalizeOperationCommands(commands []*OperationCommand, catalog map[string]Command, locale string) {
+	for _, c := range commands {
+		if stored, ok := catalog[c.SystemName]; ok {
+			c.Names = stored.Names
+		}
+		c.Localize(locale)
+	}
+}
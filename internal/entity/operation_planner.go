@@ -0,0 +1,103 @@
+package entity
+
+import "fmt"
+
+type AddressAssignmentReason string
+
+const (
+	// AddressAssignmentDefault - the default address of the command had room for it.
+	AddressAssignmentDefault AddressAssignmentReason = "default_address"
+	// AddressAssignmentShared - another container already holds the reagent of the command.
+	AddressAssignmentShared AddressAssignmentReason = "shared_container"
+	// AddressAssignmentSpillOver - the default container could not take the command, an empty one was used.
+	AddressAssignmentSpillOver AddressAssignmentReason = "spill_over"
+	// AddressAssignmentFree - the command has no default address on the deck, an empty container was used.
+	AddressAssignmentFree AddressAssignmentReason = "free_container"
+)
+
+// AddressAssignment - the address the planner picked for a command that came without one.
+// Volume is what the container holds after the command.
+type AddressAssignment struct {
+	Step       int                     `json:"step"`
+	SystemName string                  `json:"systemName"`
+	Address    Address                 `json:"address"`
+	Reason     AddressAssignmentReason `json:"reason"`
+	Volume     int64                   `json:"volume"`
+	Capacity   int64                   `json:"capacity"`
+	Message    string                  `json:"message"`
+}
+
+// planAddress - picks the container for the command given what the previous steps left in them:
+// the default address first, then a container that already holds the reagent, then an empty one,
+// preferring the group of the default address. Without a fit it returns the reason instead.
+func planAddress(deck *DeckProfile, containers map[Address]Container, command Command) (*AddressAssignment, string) {
+	assign := func(p *DeckPosition, reason AddressAssignmentReason, message string) *AddressAssignment {
+		return &AddressAssignment{
+			Address:  p.Address,
+			Reason:   reason,
+			Volume:   containers[p.Address].Volume + command.VolumeContainer,
+			Capacity: p.Capacity,
+			Message:  message,
+		}
+	}
+
+	defaultPosition, onDeck := deck.Position(command.DefaultAddress)
+	if onDeck {
+		if problem := containerProblem(defaultPosition, containers, command); problem == "" {
+			return assign(defaultPosition, AddressAssignmentDefault,
+				fmt.Sprintf("default address of %q", command.SystemName)), ""
+		}
+	}
+
+	for i := range deck.Positions {
+		p := &deck.Positions[i]
+		if c, used := containers[p.Address]; used && containerProblem(p, containers, command) == "" {
+			return assign(p, AddressAssignmentShared,
+				fmt.Sprintf("container %s already holds %s", p.Address, c.ReagentType)), ""
+		}
+	}
+
+	var free *DeckPosition
+	for i := range deck.Positions {
+		p := &deck.Positions[i]
+		if _, used := containers[p.Address]; used || containerProblem(p, containers, command) != "" {
+			continue
+		}
+		if free == nil || (onDeck && free.Group != defaultPosition.Group && p.Group == defaultPosition.Group) {
+			free = p
+		}
+	}
+
+	var why string
+	switch {
+	case command.DefaultAddress == "":
+		why = fmt.Sprintf("command %q has no default address", command.SystemName)
+	case !onDeck:
+		why = fmt.Sprintf("default address %s is not on deck %q", command.DefaultAddress, deck.Name)
+	default:
+		why = "default " + containerProblem(defaultPosition, containers, command)
+	}
+
+	if free == nil {
+		return nil, fmt.Sprintf("no container on deck %q can take %d of %s: %s",
+			deck.Name, command.VolumeContainer, command.Reagent, why)
+	}
+	if onDeck {
+		return assign(free, AddressAssignmentSpillOver, fmt.Sprintf("%s, moved to empty container %s", why, free.Address)), ""
+	}
+	return assign(free, AddressAssignmentFree, fmt.Sprintf("%s, first empty container %s", why, free.Address)), ""
+}
+
+// containerProblem - why the container cannot take the command, empty when it can.
+func containerProblem(p *DeckPosition, containers map[Address]Container, command Command) string {
+	c, used := containers[p.Address]
+	switch {
+	case !p.Allows(command.Reagent):
+		return fmt.Sprintf("container %s does not take %s", p.Address, command.Reagent)
+	case used && c.ReagentType != command.Reagent:
+		return fmt.Sprintf("container %s holds %s", p.Address, c.ReagentType)
+	case c.Volume+command.VolumeContainer > p.Capacity:
+		return fmt.Sprintf("container %s would need %d of %d", p.Address, c.Volume+command.VolumeContainer, p.Capacity)
+	}
+	return ""
+}
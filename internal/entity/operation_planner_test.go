@@ -0,0 +1,82 @@
+package entity_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"test_go/internal/entity"
+)
+
+var plannerCatalog = map[string]entity.Command{
+	"fill_ver":  {SystemName: "fill_ver", Reagent: entity.ReagentTypeVER, VolumeContainer: 120, DefaultAddress: entity.AddressSA},
+	"fill_cal":  {SystemName: "fill_cal", Reagent: entity.ReagentTypeCAL, VolumeContainer: 50, DefaultAddress: entity.AddressSA},
+	"rinse_ver": {SystemName: "rinse_ver", Reagent: entity.ReagentTypeVER, VolumeContainer: 60},
+	"flush":     {SystemName: "flush", Reagent: entity.ReagentTypeWATER, VolumeContainer: 3000, DefaultAddress: entity.AddressRA},
+}
+
+func TestValidateOperationCommandsPlansAddresses(t *testing.T) {
+	plan, err := entity.ValidateOperationCommands(entity.StandardDeckProfile(), plannerCatalog, []*entity.CommandInput{
+		{SystemName: "fill_ver"},
+		{SystemName: "fill_ver"},
+		{SystemName: "fill_cal"},
+		{SystemName: "rinse_ver"},
+		{SystemName: "flush", Address: entity.AddressRA},
+		{SystemName: "flush"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct {
+		step    int
+		address entity.Address
+		reason  entity.AddressAssignmentReason
+	}{
+		{0, entity.AddressSA, entity.AddressAssignmentDefault},
+		{1, entity.AddressSB, entity.AddressAssignmentSpillOver},
+		{2, entity.AddressSC, entity.AddressAssignmentSpillOver},
+		{3, entity.AddressSA, entity.AddressAssignmentShared},
+		{5, entity.AddressRB, entity.AddressAssignmentSpillOver},
+	}
+	if len(plan.Assignments) != len(want) {
+		t.Fatalf("got %d assignments, want %d", len(plan.Assignments), len(want))
+	}
+	for i, w := range want {
+		a := plan.Assignments[i]
+		if a.Step != w.step || a.Address != w.address || a.Reason != w.reason {
+			t.Errorf("assignment %d: got step %d %s %s, want step %d %s %s",
+				i, a.Step, a.Address, a.Reason, w.step, w.address, w.reason)
+		}
+		if plan.Commands[a.Step].Address != a.Address {
+			t.Errorf("step %d: command placed at %s, assignment says %s", a.Step, plan.Commands[a.Step].Address, a.Address)
+		}
+	}
+}
+
+func TestValidateOperationCommandsReportsInfeasiblePlan(t *testing.T) {
+	deck := &entity.DeckProfile{
+		Name:      "tiny",
+		Positions: []entity.DeckPosition{{Address: "P1", Capacity: 100}},
+	}
+
+	_, err := entity.ValidateOperationCommands(deck, plannerCatalog, []*entity.CommandInput{
+		{SystemName: "fill_cal"},
+		{SystemName: "fill_ver"},
+	})
+
+	var report *entity.OperationValidationReport
+	if !errors.As(err, &report) {
+		t.Fatalf("got %v, want *OperationValidationReport", err)
+	}
+	if len(report.Violations) != 1 {
+		t.Fatalf("got %d violations, want 1", len(report.Violations))
+	}
+	v := report.Violations[0]
+	if v.Step != 1 || v.Code != entity.OperationViolationNoAddress || !strings.Contains(v.Message, "not on deck") {
+		t.Errorf("got step %d %s %q, want step 1 no_address explaining the default address", v.Step, v.Code, v.Message)
+	}
+	if !errors.Is(err, entity.ErrNoFeasibleAddress) {
+		t.Errorf("errors.Is(err, ErrNoFeasibleAddress) = false")
+	}
+}
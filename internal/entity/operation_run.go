@@ -0,0 +1,185 @@
+package entity
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+type OperationRunState string
+
+const (
+	OperationRunQueued    OperationRunState = "queued"
+	OperationRunRunning   OperationRunState = "running"
+	OperationRunPaused    OperationRunState = "paused"
+	OperationRunCompleted OperationRunState = "completed"
+	OperationRunFailed    OperationRunState = "failed"
+	OperationRunAborted   OperationRunState = "aborted"
+)
+
+// operationRunTransitions - the states a run may move to from each state,
+// completed, failed and aborted are final.
+var operationRunTransitions = map[OperationRunState][]OperationRunState{
+	OperationRunQueued:  {OperationRunRunning, OperationRunAborted, OperationRunFailed},
+	OperationRunRunning: {OperationRunPaused, OperationRunCompleted, OperationRunFailed, OperationRunAborted},
+	// A step that was already executing when the run was paused may still fail.
+	OperationRunPaused: {OperationRunRunning, OperationRunAborted, OperationRunFailed},
+}
+
+func (s OperationRunState) IsValid() bool {
+	switch s {
+	case OperationRunQueued, OperationRunRunning, OperationRunPaused,
+		OperationRunCompleted, OperationRunFailed, OperationRunAborted:
+		return true
+	}
+	return false
+}
+
+func (s OperationRunState) IsFinal() bool {
+	_, ok := operationRunTransitions[s]
+	return !ok
+}
+
+type OperationRunStepState string
+
+const (
+	OperationRunStepPending   OperationRunStepState = "pending"
+	OperationRunStepRunning   OperationRunStepState = "running"
+	OperationRunStepCompleted OperationRunStepState = "completed"
+	OperationRunStepFailed    OperationRunStepState = "failed"
+	OperationRunStepAborted   OperationRunStepState = "aborted"
+)
+
+// OperationRun - an execution of an operation. The steps are copied from the operation
+// when the run is started, so later edits of the operation do not change it.
+// QueuePosition is the place in the queue of the instrument while the run is queued, from 1,
+// AverageTime is the average time of the operation when the run was started.
+type OperationRun struct {
+	ID            int64               `json:"id"`
+	CreatedAt     time.Time           `json:"createdAt"`
+	UpdatedAt     time.Time           `json:"updatedAt"`
+	OperationID   string              `json:"operationId"`
+	OperationName string              `json:"operationName"`
+	InstrumentID  int64               `json:"instrumentId"`
+	Priority      int                 `json:"priority"`
+	QueuePosition int                 `json:"queuePosition"`
+	AverageTime   int64               `json:"averageTime"`
+	State         OperationRunState   `json:"state"`
+	CurrentStep   int                 `json:"currentStep"`
+	StartedBy     *int64              `json:"startedBy"`
+	StartedAt     *time.Time          `json:"startedAt"`
+	FinishedAt    *time.Time          `json:"finishedAt"`
+	Error         string              `json:"error,omitempty"`
+	Steps         []*OperationRunStep `json:"steps"`
+}
+
+// OperationRunStep - a command of the run, AverageTime is taken from the catalog at start.
+type OperationRunStep struct {
+	Index       int                   `json:"index"`
+	SystemName  string                `json:"systemName"`
+	Name        string                `json:"name"`
+	Address     Address               `json:"address"`
+	AverageTime int64                 `json:"averageTime"`
+	State       OperationRunStepState `json:"state"`
+	StartedAt   *time.Time            `json:"startedAt"`
+	FinishedAt  *time.Time            `json:"finishedAt"`
+	Error       string                `json:"error,omitempty"`
+}
+
+type OperationRunFilter struct {
+	OperationID  string
+	InstrumentID int64
+	State        OperationRunState
+}
+
+// MaxRunPriority - runs of a higher priority join the queue ahead of the lower ones, 0 is the lowest.
+const MaxRunPriority = 10
+
+type StartOperationRunInput struct {
+	OperationID  string `json:"operationId"`
+	InstrumentID int64  `json:"instrumentId"`
+	Priority     int    `json:"priority"`
+}
+
+func (inp StartOperationRunInput) Validate() error {
+	if inp.Priority < 0 || inp.Priority > MaxRunPriority {
+		return fmt.Errorf("%w: %d is not between 0 and %d", ErrInvalidRunPriority, inp.Priority, MaxRunPriority)
+	}
+	return nil
+}
+
+// NewOperationRun - a queued run of the operation, the average times of the steps
+// come from the catalog and fall back to the copies kept with the operation.
+func NewOperationRun(operation *Operation, catalog map[string]Command, startedBy *int64) (*OperationRun, error) {
+	if len(operation.Commands) == 0 {
+		return nil, ErrOperationHasNoCommands
+	}
+
+	run := &OperationRun{
+		OperationID:   operation.ID,
+		OperationName: operation.Name,
+		AverageTime:   operation.AverageTime,
+		State:         OperationRunQueued,
+		StartedBy:     startedBy,
+		Steps:         make([]*OperationRunStep, 0, len(operation.Commands)),
+	}
+	for i, c := range operation.Commands {
+		step := &OperationRunStep{
+			Index:       i,
+			SystemName:  c.SystemName,
+			Name:        c.Name,
+			Address:     c.Address,
+			AverageTime: c.AverageTime,
+			State:       OperationRunStepPending,
+		}
+		if command, ok := catalog[c.SystemName]; ok {
+			step.AverageTime = command.AverageTime
+		}
+		run.Steps = append(run.Steps, step)
+	}
+
+	return run, nil
+}
+
+// Transition - moves the run to the state, setting StartedAt on the first start
+// and FinishedAt on a final state.
+func (r *OperationRun) Transition(to OperationRunState, at time.Time) error {
+	if !slices.Contains(operationRunTransitions[r.State], to) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidRunTransition, r.State, to)
+	}
+
+	r.State = to
+	if to == OperationRunRunning && r.StartedAt == nil {
+		r.StartedAt = &at
+	}
+	if to.IsFinal() {
+		r.FinishedAt = &at
+	}
+	return nil
+}
+
+// StartStep - marks the current step as executing.
+func (r *OperationRun) StartStep(at time.Time) *OperationRunStep {
+	step := r.Steps[r.CurrentStep]
+	step.State = OperationRunStepRunning
+	step.StartedAt = &at
+	return step
+}
+
+// FinishStep - closes the current step with the state and moves to the next one when it completed.
+func (r *OperationRun) FinishStep(state OperationRunStepState, err error, at time.Time) {
+	step := r.Steps[r.CurrentStep]
+	step.State = state
+	step.FinishedAt = &at
+	if err != nil {
+		step.Error = err.Error()
+	}
+	if state == OperationRunStepCompleted {
+		r.CurrentStep++
+	}
+}
+
+// HasNextStep - some steps are still to be executed.
+func (r *OperationRun) HasNextStep() bool {
+	return r.CurrentStep < len(r.Steps)
+}
@@ -0,0 +1,77 @@
+package entity
+
+import "time"
+
+type OperationRunEventType string
+
+const (
+	// OperationRunEventSnapshot - the whole run, sent first and whenever the missed events are no longer kept.
+	OperationRunEventSnapshot     OperationRunEventType = "snapshot"
+	OperationRunEventStateChanged OperationRunEventType = "state_changed"
+	OperationRunEventStepStarted  OperationRunEventType = "step_started"
+	OperationRunEventStepFinished OperationRunEventType = "step_finished"
+	OperationRunEventProgress     OperationRunEventType = "progress"
+)
+
+// OperationRunEvent - a change of a run. IDs grow across runs and restarts,
+// a client resumes the stream with the ID of the last event it handled.
+type OperationRunEvent struct {
+	ID       int64                 `json:"id"`
+	RunID    int64                 `json:"runId"`
+	Type     OperationRunEventType `json:"type"`
+	At       time.Time             `json:"at"`
+	State    OperationRunState     `json:"state,omitempty"`
+	Error    string                `json:"error,omitempty"`
+	Step     *OperationRunStep     `json:"step,omitempty"`
+	Progress *OperationRunProgress `json:"progress,omitempty"`
+	Run      *OperationRun         `json:"run,omitempty"`
+}
+
+// OperationRunProgress - Percent is the share of the average time of the run already done,
+// ETA is nil while the run waits in the queue, is paused or is over.
+type OperationRunProgress struct {
+	Percent float64    `json:"percent"`
+	ETA     *time.Time `json:"eta"`
+}
+
+// Progress - measured in average time, the step being executed counts for the time it has
+// taken so far, up to its average time. timeUnit is what a unit of AverageTime lasts.
+// Failed and aborted runs keep the percent they stopped at.
+func (r *OperationRun) Progress(now time.Time, timeUnit time.Duration) *OperationRunProgress {
+	total, done := r.measure(now, timeUnit)
+
+	progress := &OperationRunProgress{}
+	switch {
+	case total > 0:
+		progress.Percent = float64(done) / float64(total) * 100
+	case len(r.Steps) > 0:
+		// Steps without an average time count the same.
+		progress.Percent = float64(r.CurrentStep) / float64(len(r.Steps)) * 100
+	}
+	if r.State == OperationRunRunning {
+		eta := now.Add(total - done)
+		progress.ETA = &eta
+	}
+	return progress
+}
+
+// Remaining - the time the steps still to be executed are expected to take.
+func (r *OperationRun) Remaining(now time.Time, timeUnit time.Duration) time.Duration {
+	total, done := r.measure(now, timeUnit)
+	return total - done
+}
+
+// measure - the average time of all the steps and of what is done of them.
+func (r *OperationRun) measure(now time.Time, timeUnit time.Duration) (total, done time.Duration) {
+	for i, step := range r.Steps {
+		expected := time.Duration(step.AverageTime) * timeUnit
+		total += expected
+		switch {
+		case i < r.CurrentStep:
+			done += expected
+		case i == r.CurrentStep && step.State == OperationRunStepRunning && step.StartedAt != nil:
+			done += min(now.Sub(*step.StartedAt), expected)
+		}
+	}
+	return total, done
+}
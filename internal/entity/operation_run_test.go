@@ -0,0 +1,96 @@
+package entity_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"test_go/internal/entity"
+)
+
+func TestOperationRunStateMachine(t *testing.T) {
+	operation := &entity.Operation{
+		ID:   "1",
+		Name: "prime",
+		Commands: []*entity.OperationCommand{
+			{Command: entity.Command{SystemName: "fill_ver", AverageTime: 3}, Address: entity.AddressSA},
+			{Command: entity.Command{SystemName: "flush", AverageTime: 1}, Address: entity.AddressRA},
+		},
+	}
+	catalog := map[string]entity.Command{"flush": {SystemName: "flush", AverageTime: 7}}
+
+	run, err := entity.NewOperationRun(operation, catalog, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if run.State != entity.OperationRunQueued || run.Steps[0].AverageTime != 3 || run.Steps[1].AverageTime != 7 {
+		t.Fatalf("unexpected run: state %s, average times %d and %d", run.State, run.Steps[0].AverageTime, run.Steps[1].AverageTime)
+	}
+
+	at := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	if err := run.Transition(entity.OperationRunPaused, at); !errors.Is(err, entity.ErrInvalidRunTransition) {
+		t.Fatalf("pausing a queued run: got %v, want ErrInvalidRunTransition", err)
+	}
+
+	for _, to := range []entity.OperationRunState{entity.OperationRunRunning, entity.OperationRunPaused, entity.OperationRunRunning} {
+		if err := run.Transition(to, at.Add(time.Minute)); err != nil {
+			t.Fatalf("transition to %s: %v", to, err)
+		}
+	}
+	if !run.StartedAt.Equal(at.Add(time.Minute)) || run.FinishedAt != nil {
+		t.Fatalf("unexpected timestamps: started %v, finished %v", run.StartedAt, run.FinishedAt)
+	}
+
+	for run.HasNextStep() {
+		run.StartStep(at)
+		run.FinishStep(entity.OperationRunStepCompleted, nil, at)
+	}
+	if err := run.Transition(entity.OperationRunCompleted, at.Add(time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if run.FinishedAt == nil || !run.State.IsFinal() {
+		t.Fatalf("completed run is not final")
+	}
+	if err := run.Transition(entity.OperationRunAborted, at); !errors.Is(err, entity.ErrInvalidRunTransition) {
+		t.Fatalf("aborting a completed run: got %v, want ErrInvalidRunTransition", err)
+	}
+}
+
+func TestNewOperationRunWithoutCommands(t *testing.T) {
+	_, err := entity.NewOperationRun(&entity.Operation{ID: "1"}, nil, nil)
+	if !errors.Is(err, entity.ErrOperationHasNoCommands) {
+		t.Fatalf("got %v, want ErrOperationHasNoCommands", err)
+	}
+}
+
+func TestOperationRunProgress(t *testing.T) {
+	at := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	run := &entity.OperationRun{
+		State:       entity.OperationRunRunning,
+		CurrentStep: 1,
+		Steps: []*entity.OperationRunStep{
+			{AverageTime: 2, State: entity.OperationRunStepCompleted},
+			{AverageTime: 4, State: entity.OperationRunStepRunning, StartedAt: &at},
+			{AverageTime: 2, State: entity.OperationRunStepPending},
+		},
+	}
+
+	progress := run.Progress(at.Add(time.Second), time.Second)
+	if progress.Percent != 37.5 {
+		t.Fatalf("percent: got %v, want 37.5", progress.Percent)
+	}
+	if progress.ETA == nil || !progress.ETA.Equal(at.Add(6*time.Second)) {
+		t.Fatalf("eta: got %v, want %v", progress.ETA, at.Add(6*time.Second))
+	}
+
+	// A step running longer than its average time does not push the progress past it.
+	progress = run.Progress(at.Add(time.Minute), time.Second)
+	if progress.Percent != 75 || !progress.ETA.Equal(at.Add(time.Minute+2*time.Second)) {
+		t.Fatalf("overdue step: got %v%% and %v", progress.Percent, progress.ETA)
+	}
+
+	run.State = entity.OperationRunPaused
+	if progress = run.Progress(at, time.Second); progress.ETA != nil {
+		t.Fatalf("paused run has an eta: %v", progress.ETA)
+	}
+}
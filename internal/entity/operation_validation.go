@@ -0,0 +1,267 @@
+package entity
+
+import "fmt"
+
+type OperationViolationCode string
+
+const (
+	// OperationViolationEmptyCommand - the step is null instead of a command.
+	OperationViolationEmptyCommand    OperationViolationCode = "empty_command"
+	OperationViolationCommandNotFound OperationViolationCode = "command_not_found"
+	OperationViolationCommandDisabled OperationViolationCode = "command_disabled"
+	// OperationViolationUnknownAddress - the deck has no container at the address.
+	OperationViolationUnknownAddress OperationViolationCode = "unknown_address"
+	// OperationViolationReagentNotAllowed - the container does not take the reagent of the command.
+	OperationViolationReagentNotAllowed OperationViolationCode = "reagent_not_allowed"
+	// OperationViolationNoAddress - the address was left to the planner and no container fits the command.
+	OperationViolationNoAddress OperationViolationCode = "no_address"
+	// OperationViolationReagentClash - the container already holds another reagent.
+	OperationViolationReagentClash OperationViolationCode = "reagent_clash"
+	// OperationViolationVolumeExceeded - the step leaves more in the container than it holds.
+	OperationViolationVolumeExceeded OperationViolationCode = "volume_exceeded"
+)
+
+// violationErrors - the errors the codes were reported with before the report existed,
+// so that errors.Is keeps matching them.
+var violationErrors = map[OperationViolationCode]error{
+	OperationViolationEmptyCommand:      ErrEmptyOperationCommand,
+	OperationViolationCommandNotFound:   ErrCommandNotFound,
+	OperationViolationCommandDisabled:   ErrCommandDisabled,
+	OperationViolationUnknownAddress:    ErrUnknownDeckAddress,
+	OperationViolationReagentNotAllowed: ErrReagentNotAllowed,
+	OperationViolationNoAddress:         ErrNoFeasibleAddress,
+	OperationViolationReagentClash:      ErrCommandDuplicateAddress,
+	OperationViolationVolumeExceeded:    ErrCommandVolumeExceeded,
+}
+
+// OperationViolation - a problem with one step of an operation, Step is the index of the command.
+// Volume is what the container holds after the step, the rejected command included for volume_exceeded.
+type OperationViolation struct {
+	Step             int                    `json:"step"`
+	Code             OperationViolationCode `json:"code"`
+	SystemName       string                 `json:"systemName"`
+	Address          Address                `json:"address"`
+	Reagent          ReagentType            `json:"reagent,omitempty"`
+	ContainerReagent ReagentType            `json:"containerReagent,omitempty"`
+	Volume           int64                  `json:"volume"`
+	Capacity         int64                  `json:"capacity,omitempty"`
+	Message          string                 `json:"message"`
+}
+
+// OperationValidationReport - every violation found in the commands of an operation,
+// returned as an error when it is not empty.
+type OperationValidationReport struct {
+	Violations []*OperationViolation `json:"violations"`
+}
+
+func (r *OperationValidationReport) Error() string {
+	return fmt.Sprintf("%s: %d violation(s)", ErrInvalidOperation, len(r.Violations))
+}
+
+func (r *OperationValidationReport) Unwrap() []error {
+	errs := []error{ErrInvalidOperation}
+	seen := make(map[OperationViolationCode]bool)
+	for _, v := range r.Violations {
+		if !seen[v.Code] {
+			seen[v.Code] = true
+			errs = append(errs, violationErrors[v.Code])
+		}
+	}
+	return errs
+}
+
+// OperationPlan - the commands of an operation placed on the deck, Assignments explains
+// the addresses the planner picked for the commands that came without one.
+// The totals and Containers cover the placed commands.
+type OperationPlan struct {
+	Commands         []*OperationCommand
+	AverageTime      int64
+	VolumeWaste      int64
+	VolumeDriveFluid int64
+	Containers       []*ContainerUsage
+	Assignments      []*AddressAssignment
+}
+
+// ContainerUsage - what the commands leave in a container of the deck.
+type ContainerUsage struct {
+	Address  Address     `json:"address"`
+	Group    string      `json:"group"`
+	Reagent  ReagentType `json:"reagent"`
+	Volume   int64       `json:"volume"`
+	Capacity int64       `json:"capacity"`
+}
+
+// ValidateOperationCommands - resolves the command inputs against the catalog and the deck and walks
+// them in order, checking that every container holds a single allowed reagent within its capacity.
+// Inputs without an address get one from the planner as the walk reaches them.
+// The whole list is checked; the returned error is a *OperationValidationReport with every violation.
+func ValidateOperationCommands(deck *DeckProfile, catalog map[string]Command, inputs []*CommandInput) (*OperationPlan, error) {
+	plan, report := planOperationCommands(deck, catalog, inputs)
+	if len(report.Violations) > 0 {
+		return nil, report
+	}
+	return plan, nil
+}
+
+// planOperationCommands - the walk behind ValidateOperationCommands, the plan of the commands
+// that could be placed is returned next to the violations of the others.
+func planOperationCommands(deck *DeckProfile, catalog map[string]Command, inputs []*CommandInput) (*OperationPlan, *OperationValidationReport) {
+	var (
+		report     = &OperationValidationReport{Violations: []*OperationViolation{}}
+		plan       = &OperationPlan{Commands: make([]*OperationCommand, 0, len(inputs))}
+		containers = make(map[Address]Container)
+	)
+
+	for step, inp := range inputs {
+		if inp == nil {
+			report.Violations = append(report.Violations, &OperationViolation{
+				Step:    step,
+				Code:    OperationViolationEmptyCommand,
+				Message: fmt.Sprintf("step %d has no command", step),
+			})
+			continue
+		}
+		violation := &OperationViolation{Step: step, SystemName: inp.SystemName, Address: inp.Address}
+
+		command, ok := catalog[inp.SystemName]
+		if !ok {
+			violation.Code = OperationViolationCommandNotFound
+			violation.Volume = containers[inp.Address].Volume
+			violation.Message = fmt.Sprintf("command %q is not in the catalog", inp.SystemName)
+			report.Violations = append(report.Violations, violation)
+			continue
+		}
+		violation.Reagent = command.Reagent
+
+		if command.Disabled {
+			violation.Code = OperationViolationCommandDisabled
+			violation.Volume = containers[inp.Address].Volume
+			violation.Message = fmt.Sprintf("command %q is disabled", inp.SystemName)
+			report.Violations = append(report.Violations, violation)
+			continue
+		}
+
+		if inp.Address == "" {
+			assignment, reason := planAddress(deck, containers, command)
+			if assignment == nil {
+				violation.Code = OperationViolationNoAddress
+				violation.Volume = command.VolumeContainer
+				violation.Message = reason
+				report.Violations = append(report.Violations, violation)
+				continue
+			}
+			assignment.Step, assignment.SystemName = step, inp.SystemName
+			plan.Assignments = append(plan.Assignments, assignment)
+			inp = &CommandInput{ID: inp.ID, SystemName: inp.SystemName, Address: assignment.Address}
+		}
+
+		address, err := deck.ParseAddress(string(inp.Address))
+		if err != nil {
+			violation.Code = OperationViolationUnknownAddress
+			violation.Message = fmt.Sprintf("deck %q has no container %s", deck.Name, inp.Address)
+			report.Violations = append(report.Violations, violation)
+			continue
+		}
+		violation.Address = address
+		position, _ := deck.Position(address)
+
+		if !position.Allows(command.Reagent) {
+			violation.Code = OperationViolationReagentNotAllowed
+			violation.Volume = containers[address].Volume
+			violation.Capacity = position.Capacity
+			violation.Message = fmt.Sprintf("container %s does not take %s, command %q",
+				address, command.Reagent, inp.SystemName)
+			report.Violations = append(report.Violations, violation)
+			continue
+		}
+
+		container, used := containers[address]
+		if !used {
+			container = Container{Address: address, ReagentType: command.Reagent, Capacity: position.Capacity}
+		} else if container.ReagentType != command.Reagent {
+			violation.Code = OperationViolationReagentClash
+			violation.ContainerReagent = container.ReagentType
+			violation.Volume = container.Volume
+			violation.Capacity = container.Capacity
+			violation.Message = fmt.Sprintf("container %s holds %s, command %q uses %s",
+				address, container.ReagentType, inp.SystemName, command.Reagent)
+			report.Violations = append(report.Violations, violation)
+			continue
+		}
+
+		container.Volume += command.VolumeContainer
+		containers[address] = container
+		if !container.IsValidVolume() {
+			violation.Code = OperationViolationVolumeExceeded
+			violation.Volume = container.Volume
+			violation.Capacity = container.Capacity
+			violation.Message = fmt.Sprintf("container %s needs %d of %d",
+				address, container.Volume, violation.Capacity)
+			report.Violations = append(report.Violations, violation)
+		}
+
+		plan.Commands = append(plan.Commands, &OperationCommand{Command: command, Address: address})
+		plan.AverageTime += command.AverageTime
+		plan.VolumeWaste += command.VolumeWaste
+		plan.VolumeDriveFluid += command.VolumeDriveFluid
+	}
+
+	for _, p := range deck.Positions {
+		if c, used := containers[p.Address]; used {
+			plan.Containers = append(plan.Containers, &ContainerUsage{
+				Address:  p.Address,
+				Group:    p.Group,
+				Reagent:  c.ReagentType,
+				Volume:   c.Volume,
+				Capacity: c.Capacity,
+			})
+		}
+	}
+
+	return plan, report
+}
+
+// OperationValidation - the outcome of a dry run: the resources the operation needs
+// on its deck and every violation that would stop it from being saved.
+type OperationValidation struct {
+	Valid            bool                  `json:"valid"`
+	DeckProfileID    int64                 `json:"deckProfileId,omitempty"`
+	DeckProfile      string                `json:"deckProfile"`
+	AverageTime      int64                 `json:"averageTime"`
+	VolumeWaste      int64                 `json:"volumeWaste"`
+	VolumeDriveFluid int64                 `json:"volumeDriveFluid"`
+	Containers       []*ContainerUsage     `json:"containers"`
+	Assignments      []*AddressAssignment  `json:"assignments"`
+	Violations       []*OperationViolation `json:"violations"`
+}
+
+type ValidateOperationInput struct {
+	Commands []*CommandInput `json:"commands"`
+	// DeckProfileID - nil checks the commands against the default deck.
+	DeckProfileID *int64 `json:"deckProfileId"`
+}
+
+// CheckOperationCommands - runs the validation of ValidateOperationCommands without failing,
+// so that the resources of an invalid operation can be shown along with its violations.
+func CheckOperationCommands(deck *DeckProfile, catalog map[string]Command, inputs []*CommandInput) *OperationValidation {
+	plan, report := planOperationCommands(deck, catalog, inputs)
+
+	res := &OperationValidation{
+		Valid:            len(report.Violations) == 0,
+		DeckProfileID:    deck.ID,
+		DeckProfile:      deck.Name,
+		AverageTime:      plan.AverageTime,
+		VolumeWaste:      plan.VolumeWaste,
+		VolumeDriveFluid: plan.VolumeDriveFluid,
+		Containers:       plan.Containers,
+		Assignments:      plan.Assignments,
+		Violations:       report.Violations,
+	}
+	if res.Containers == nil {
+		res.Containers = []*ContainerUsage{}
+	}
+	if res.Assignments == nil {
+		res.Assignments = []*AddressAssignment{}
+	}
+	return res
+}
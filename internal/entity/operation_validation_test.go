@@ -0,0 +1,177 @@
+package entity_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"test_go/internal/entity"
+)
+
+var validationCatalog = map[string]entity.Command{
+	"fill_ver":  {SystemName: "fill_ver", Reagent: entity.ReagentTypeVER, VolumeContainer: 120, AverageTime: 10},
+	"fill_cal":  {SystemName: "fill_cal", Reagent: entity.ReagentTypeCAL, VolumeContainer: 50, AverageTime: 5},
+	"fill_old":  {SystemName: "fill_old", Reagent: entity.ReagentTypeVER, VolumeContainer: 10, Disabled: true},
+	"flush_big": {SystemName: "flush_big", Reagent: entity.ReagentTypeWATER, VolumeContainer: 4000, AverageTime: 30},
+}
+
+func TestValidateOperationCommands(t *testing.T) {
+	plan, err := entity.ValidateOperationCommands(entity.StandardDeckProfile(), validationCatalog, []*entity.CommandInput{
+		{SystemName: "fill_ver", Address: entity.AddressSA},
+		{SystemName: "fill_cal", Address: entity.AddressSB},
+		{SystemName: "flush_big", Address: entity.AddressRA},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plan.Commands) != 3 || plan.AverageTime != 45 {
+		t.Fatalf("got %d commands and %d total time, want 3 and 45", len(plan.Commands), plan.AverageTime)
+	}
+}
+
+func TestValidateOperationCommandsReportsEveryViolation(t *testing.T) {
+	_, err := entity.ValidateOperationCommands(entity.StandardDeckProfile(), validationCatalog, []*entity.CommandInput{
+		{SystemName: "fill_ver", Address: entity.AddressSA},
+		{SystemName: "fill_cal", Address: entity.AddressSA},
+		{SystemName: "missing", Address: entity.AddressSB},
+		{SystemName: "fill_ver", Address: entity.AddressSA},
+		{SystemName: "fill_old", Address: entity.AddressSC},
+	})
+
+	var report *entity.OperationValidationReport
+	if !errors.As(err, &report) {
+		t.Fatalf("got %v, want *OperationValidationReport", err)
+	}
+
+	want := []struct {
+		step   int
+		code   entity.OperationViolationCode
+		volume int64
+	}{
+		{1, entity.OperationViolationReagentClash, 120},
+		{2, entity.OperationViolationCommandNotFound, 0},
+		{3, entity.OperationViolationVolumeExceeded, 240},
+		{4, entity.OperationViolationCommandDisabled, 0},
+	}
+	if len(report.Violations) != len(want) {
+		t.Fatalf("got %d violations, want %d", len(report.Violations), len(want))
+	}
+	for i, w := range want {
+		v := report.Violations[i]
+		if v.Step != w.step || v.Code != w.code || v.Volume != w.volume {
+			t.Errorf("violation %d: got step %d %s volume %d, want step %d %s volume %d",
+				i, v.Step, v.Code, v.Volume, w.step, w.code, w.volume)
+		}
+	}
+
+	clash := report.Violations[0]
+	if clash.Reagent != entity.ReagentTypeCAL || clash.ContainerReagent != entity.ReagentTypeVER {
+		t.Errorf("clash reagents: got %s in %s, want CAL in VER", clash.Reagent, clash.ContainerReagent)
+	}
+
+	for _, target := range []error{
+		entity.ErrInvalidOperation, entity.ErrCommandDuplicateAddress, entity.ErrCommandNotFound,
+		entity.ErrCommandVolumeExceeded, entity.ErrCommandDisabled,
+	} {
+		if !errors.Is(err, target) {
+			t.Errorf("errors.Is(err, %v) = false", target)
+		}
+	}
+}
+
+func TestValidateOperationCommandsFollowsTheDeck(t *testing.T) {
+	deck := &entity.DeckProfile{
+		Name: "compact",
+		Positions: []entity.DeckPosition{
+			{Address: "P1", Capacity: 100, Reagents: []entity.ReagentType{entity.ReagentTypeCAL}},
+			{Address: "P2", Capacity: 500},
+		},
+	}
+
+	plan, err := entity.ValidateOperationCommands(deck, validationCatalog, []*entity.CommandInput{
+		{SystemName: "fill_cal", Address: " p1"},
+		{SystemName: "fill_ver", Address: "P2"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.Commands[0].Address != "P1" {
+		t.Errorf("got address %q, want P1", plan.Commands[0].Address)
+	}
+
+	_, err = entity.ValidateOperationCommands(deck, validationCatalog, []*entity.CommandInput{
+		{SystemName: "fill_ver", Address: entity.AddressSA},
+		{SystemName: "fill_ver", Address: "P1"},
+		{SystemName: "fill_cal", Address: "P1"},
+		{SystemName: "fill_cal", Address: "P1"},
+		{SystemName: "fill_cal", Address: "P1"},
+	})
+
+	var report *entity.OperationValidationReport
+	if !errors.As(err, &report) {
+		t.Fatalf("got %v, want *OperationValidationReport", err)
+	}
+
+	want := []entity.OperationViolationCode{
+		entity.OperationViolationUnknownAddress,
+		entity.OperationViolationReagentNotAllowed,
+		entity.OperationViolationVolumeExceeded,
+	}
+	if len(report.Violations) != len(want) {
+		t.Fatalf("got %d violations, want %d", len(report.Violations), len(want))
+	}
+	for i, code := range want {
+		if report.Violations[i].Code != code {
+			t.Errorf("violation %d: got %s, want %s", i, report.Violations[i].Code, code)
+		}
+	}
+	if v := report.Violations[2]; v.Step != 4 || v.Volume != 150 || v.Capacity != 100 {
+		t.Errorf("got step %d volume %d of %d, want step 4 volume 150 of 100", v.Step, v.Volume, v.Capacity)
+	}
+	if !errors.Is(err, entity.ErrUnknownDeckAddress) || !errors.Is(err, entity.ErrReagentNotAllowed) {
+		t.Errorf("report does not match the deck errors: %v", err)
+	}
+}
+
+func TestCheckOperationCommandsKeepsTheBreakdown(t *testing.T) {
+	catalog := map[string]entity.Command{
+		"fill_ver": {SystemName: "fill_ver", Reagent: entity.ReagentTypeVER, VolumeContainer: 120,
+			VolumeWaste: 30, VolumeDriveFluid: 15, AverageTime: 10},
+	}
+
+	res := entity.CheckOperationCommands(entity.StandardDeckProfile(), catalog, []*entity.CommandInput{
+		{SystemName: "fill_ver", Address: entity.AddressSA},
+		{SystemName: "fill_ver", Address: entity.AddressSA},
+		{SystemName: "missing", Address: entity.AddressSB},
+	})
+
+	if res.Valid || len(res.Violations) != 2 {
+		t.Fatalf("got valid %t with %d violations, want invalid with 2", res.Valid, len(res.Violations))
+	}
+	if res.AverageTime != 20 || res.VolumeWaste != 60 || res.VolumeDriveFluid != 30 {
+		t.Errorf("got totals %d/%d/%d, want 20/60/30", res.AverageTime, res.VolumeWaste, res.VolumeDriveFluid)
+	}
+	if len(res.Containers) != 1 || res.Containers[0].Volume != 240 || res.Containers[0].Capacity != entity.SmallContainerVolume {
+		t.Errorf("got containers %+v, want SA with 240 of %d", res.Containers, entity.SmallContainerVolume)
+	}
+}
+
+func TestValidateOperationCommandsRejectsEmptySteps(t *testing.T) {
+	var inp entity.ValidateOperationInput
+	if err := json.Unmarshal([]byte(`{"commands":[null,{"systemName":"fill_ver","address":"SA"}]}`), &inp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err := entity.ValidateOperationCommands(entity.StandardDeckProfile(), validationCatalog, inp.Commands)
+	if !errors.Is(err, entity.ErrEmptyOperationCommand) {
+		t.Fatalf("got %v, want ErrEmptyOperationCommand", err)
+	}
+
+	res := entity.CheckOperationCommands(entity.StandardDeckProfile(), validationCatalog, inp.Commands)
+	if res.Valid || len(res.Violations) != 1 || res.Violations[0].Step != 0 || res.Violations[0].Code != entity.OperationViolationEmptyCommand {
+		t.Fatalf("got valid %t with violations %+v, want step 0 empty_command", res.Valid, res.Violations)
+	}
+	if res.AverageTime != 10 {
+		t.Errorf("got average time %d, want the 10 of the placed step", res.AverageTime)
+	}
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// PatchField - a member of a JSON merge patch (RFC 7396):
+// an absent member leaves the value unchanged, null clears it.
+type PatchField[T any] struct {
+	Present bool
+	Null    bool
+	Value   T
+}
+
+func NewPatchField[T any](v T) PatchField[T] {
+	return PatchField[T]{Present: true, Value: v}
+}
+
+func (f *PatchField[T]) UnmarshalJSON(data []byte) error {
+	f.Present = true
+	if string(data) == "null" {
+		f.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &f.Value)
+}
+
+// Get - the value the member sets, the zero value when it clears the field.
+func (f PatchField[T]) Get() T {
+	if f.Null {
+		var zero T
+		return zero
+	}
+	return f.Value
+}
+
+// Apply - merges the member into dst.
+func (f PatchField[T]) Apply(dst *T) {
+	if f.Present {
+		*dst = f.Get()
+	}
+}
+
+// Required - rejects null for fields that cannot be cleared.
+func (f PatchField[T]) Required(name string) error {
+	if f.Present && f.Null {
+		return fmt.Errorf("%w: %s", ErrPatchFieldRequired, name)
+	}
+	return nil
+}
+
+// RequiredNonZero - rejects null and the zero value, for the fields that cannot be blank either.
+func RequiredNonZero[T comparable](f PatchField[T], name string) error {
+	var zero T
+	if f.Present && (f.Null || f.Value == zero) {
+		return fmt.Errorf("%w: %s", ErrPatchFieldRequired, name)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package entity_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"test_go/internal/entity"
+)
+
+func TestPatchFieldUnmarshal(t *testing.T) {
+	var inp entity.UpdateAuthorInput
+	if err := json.Unmarshal([]byte(`{"name":"Leo","gender":null}`), &inp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !inp.Name.Present || inp.Name.Null || inp.Name.Value != "Leo" {
+		t.Fatalf("name: got %+v, want a present value", inp.Name)
+	}
+	if !inp.Gender.Present || !inp.Gender.Null {
+		t.Fatalf("gender: got %+v, want present null", inp.Gender)
+	}
+
+	author := inp.Apply(entity.Author{Name: "Lev", Gender: true})
+	if author.Name != "Leo" || author.Gender {
+		t.Fatalf("got %+v, want the name replaced and the gender cleared", author)
+	}
+
+	var absent entity.UpdateAuthorInput
+	if err := json.Unmarshal([]byte(`{}`), &absent); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if author := absent.Apply(entity.Author{Name: "Lev", Gender: true}); author.Name != "Lev" || !author.Gender {
+		t.Fatalf("got %+v, want absent members unchanged", author)
+	}
+}
+
+func TestUpdateAuthorInputValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{"absent", `{}`, false},
+		{"set", `{"name":"Leo","gender":false}`, false},
+		{"null name", `{"name":null}`, true},
+		{"empty name", `{"name":""}`, true},
+		{"null gender", `{"gender":null}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var inp entity.UpdateAuthorInput
+			if err := json.Unmarshal([]byte(tt.body), &inp); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err := inp.Validate()
+			if tt.wantErr != errors.Is(err, entity.ErrPatchFieldRequired) {
+				t.Fatalf("got %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateBookInputValidate(t *testing.T) {
+	for body, wantErr := range map[string]bool{
+		`{"name":"War and Peace","author_id":1}`: false,
+		`{"name":""}`:                            true,
+		`{"author_id":0}`:                        true,
+		`{"author_id":null}`:                     true,
+	} {
+		var inp entity.UpdateBookInput
+		if err := json.Unmarshal([]byte(body), &inp); err != nil {
+			t.Fatalf("%s: unexpected error: %v", body, err)
+		}
+
+		if err := inp.Validate(); wantErr != errors.Is(err, entity.ErrPatchFieldRequired) {
+			t.Fatalf("%s: got %v, want error %v", body, err, wantErr)
+		}
+	}
+}
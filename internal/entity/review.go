@@ -0,0 +1,128 @@
+package entity
+
+type ReviewStatus string
+
+const (
+	// ReviewStatusPublished - visible and counted in the book rating.
+	ReviewStatusPublished ReviewStatus = "PUBLISHED"
+	// ReviewStatusFlagged - reported by users and waiting for moderation, still visible.
+	ReviewStatusFlagged ReviewStatus = "FLAGGED"
+	// ReviewStatusHidden - hidden by a moderator and excluded from the book rating.
+	ReviewStatusHidden ReviewStatus = "HIDDEN"
+)
+
+// IsModerationResult - statuses an admin can set when resolving a flagged review.
+func (s ReviewStatus) IsModerationResult() bool {
+	return s == ReviewStatusPublished || s == ReviewStatusHidden
+}
+
+const (
+	ReviewMinRating = 1
+	ReviewMaxRating = 5
+
+	ReviewsDefaultLimit = 20
+	ReviewsMaxLimit     = 100
+)
+
+type Review struct {
+	Entity
+	BookID    int64        `json:"bookId"`
+	UserID    int64        `json:"userId"`
+	Rating    int          `json:"rating"`
+	Text      string       `json:"text"`
+	Status    ReviewStatus `json:"status"`
+	FlagCount int          `json:"flagCount"`
+}
+
+func NewReview(bookID, userID int64, rating int, text string) *Review {
+	return &Review{
+		BookID: bookID,
+		UserID: userID,
+		Rating: rating,
+		Text:   text,
+		Status: ReviewStatusPublished,
+	}
+}
+
+// CanBeModifiedBy - only the author of the review or an admin can change it.
+func (r *Review) CanBeModifiedBy(u *UserInfoToken) bool {
+	if u == nil {
+		return false
+	}
+	return u.IsEqualRole(UserRoleAdmin) || r.UserID == u.ID
+}
+
+// RatingContribution - what the review adds to the book rating sum and count, hidden reviews are not counted.
+func (r *Review) RatingContribution() (sum, count int) {
+	if r == nil || r.Status == ReviewStatusHidden {
+		return 0, 0
+	}
+	return r.Rating, 1
+}
+
+// ReviewRatingDelta - how the book rating sum and count move when the review changes from prev to next,
+// nil means the review is absent.
+func ReviewRatingDelta(prev, next *Review) (sumDelta, countDelta int) {
+	prevSum, prevCount := prev.RatingContribution()
+	nextSum, nextCount := next.RatingContribution()
+	return nextSum - prevSum, nextCount - prevCount
+}
+
+func IsValidRating(rating int) bool {
+	return rating >= ReviewMinRating && rating <= ReviewMaxRating
+}
+
+type CreateReviewInput struct {
+	BookID int64  `json:"-"`
+	UserID int64  `json:"-"`
+	Rating int    `json:"rating"`
+	Text   string `json:"text"`
+}
+
+type UpdateReviewInput struct {
+	ID     int64   `json:"-"`
+	Rating *int    `json:"rating"`
+	Text   *string `json:"text"`
+}
+
+type FlagReviewInput struct {
+	ReviewID int64  `json:"-"`
+	UserID   int64  `json:"-"`
+	Reason   string `json:"reason"`
+}
+
+type ModerateReviewInput struct {
+	ID     int64        `json:"-"`
+	Status ReviewStatus `json:"status"`
+}
+
+type FilterReviewInput struct {
+	BookID   *int64
+	Statuses []ReviewStatus
+	Page     int
+	Limit    int
+}
+
+// Normalize - fills in the default page and clamps the page size.
+func (f *FilterReviewInput) Normalize() {
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	if f.Limit < 1 {
+		f.Limit = ReviewsDefaultLimit
+	}
+	if f.Limit > ReviewsMaxLimit {
+		f.Limit = ReviewsMaxLimit
+	}
+}
+
+func (f *FilterReviewInput) Offset() int {
+	return (f.Page - 1) * f.Limit
+}
+
+type ReviewList struct {
+	Items []*Review `json:"items"`
+	Total int64     `json:"total"`
+	Page  int       `json:"page"`
+	Limit int       `json:"limit"`
+}
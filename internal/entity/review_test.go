@@ -0,0 +1,35 @@
+package entity_test
+
+import (
+	"testing"
+
+	"test_go/internal/entity"
+)
+
+func TestReviewRatingDelta(t *testing.T) {
+	review := func(rating int, status entity.ReviewStatus) *entity.Review {
+		return &entity.Review{BookID: 1, UserID: 2, Rating: rating, Status: status}
+	}
+
+	tests := []struct {
+		name       string
+		prev, next *entity.Review
+		sum, count int
+	}{
+		{"created", nil, review(4, entity.ReviewStatusPublished), 4, 1},
+		{"deleted", review(4, entity.ReviewStatusPublished), nil, -4, -1},
+		{"rating changed", review(2, entity.ReviewStatusPublished), review(5, entity.ReviewStatusPublished), 3, 0},
+		{"flagged is still counted", review(3, entity.ReviewStatusPublished), review(3, entity.ReviewStatusFlagged), 0, 0},
+		{"hidden", review(3, entity.ReviewStatusFlagged), review(3, entity.ReviewStatusHidden), -3, -1},
+		{"published again", review(3, entity.ReviewStatusHidden), review(3, entity.ReviewStatusPublished), 3, 1},
+		{"hidden deleted", review(5, entity.ReviewStatusHidden), nil, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sum, count := entity.ReviewRatingDelta(tt.prev, tt.next)
+			if sum != tt.sum || count != tt.count {
+				t.Fatalf("got (%d, %d), want (%d, %d)", sum, count, tt.sum, tt.count)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package entity
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+type RevisionEntityType string
+
+const (
+	RevisionEntityAuthor    RevisionEntityType = "author"
+	RevisionEntityBook      RevisionEntityType = "book"
+	RevisionEntityOperation RevisionEntityType = "operation"
+)
+
+type RevisionAction string
+
+const (
+	RevisionActionCreate  RevisionAction = "CREATE"
+	RevisionActionUpdate  RevisionAction = "UPDATE"
+	RevisionActionDelete  RevisionAction = "DELETE"
+	RevisionActionRestore RevisionAction = "RESTORE"
+)
+
+// Revision - a single mutation of a record. Before is empty for a create and After for a delete,
+// EntityID is a string so that mongo documents are tracked the same way as postgres rows.
+type Revision struct {
+	ID         int64                  `json:"id"`
+	CreatedAt  time.Time              `json:"createdAt"`
+	EntityType RevisionEntityType     `json:"entityType"`
+	EntityID   string                 `json:"entityId"`
+	Action     RevisionAction         `json:"action"`
+	ActorID    *int64                 `json:"actorId"`
+	Before     json.RawMessage        `json:"before"`
+	After      json.RawMessage        `json:"after"`
+	Diff       map[string]FieldChange `json:"diff"`
+}
+
+type RecordRevisionInput struct {
+	EntityType RevisionEntityType
+	EntityID   string
+	Action     RevisionAction
+	ActorID    *int64
+	Before     any
+	After      any
+}
+
+type FieldChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// NewRevision - snapshots before and after as json and keeps the top level fields that differ.
+// A nil snapshot means the record did not exist.
+func NewRevision(entityType RevisionEntityType, entityID string, action RevisionAction,
+	actorID *int64, before, after any,
+) (*Revision, error) {
+	e := &Revision{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		ActorID:    actorID,
+		Diff:       make(map[string]FieldChange),
+	}
+
+	var err error
+	if e.Before, err = marshalSnapshot(before); err != nil {
+		return nil, err
+	}
+	if e.After, err = marshalSnapshot(after); err != nil {
+		return nil, err
+	}
+
+	beforeFields, err := snapshotFields(e.Before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := snapshotFields(e.After)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range afterFields {
+		if old, ok := beforeFields[k]; !ok || !reflect.DeepEqual(old, v) {
+			e.Diff[k] = FieldChange{Before: beforeFields[k], After: v}
+		}
+	}
+	for k, v := range beforeFields {
+		if _, ok := afterFields[k]; !ok {
+			e.Diff[k] = FieldChange{Before: v}
+		}
+	}
+
+	return e, nil
+}
+
+// ActorOf - the id of the user behind a mutation, nil when it was not made on behalf of a user.
+func ActorOf(u *UserInfoToken) *int64 {
+	if u == nil {
+		return nil
+	}
+	id := u.ID
+	return &id
+}
+
+// HasChanges - false when a mutation did not change anything worth recording.
+func (r *Revision) HasChanges() bool {
+	return len(r.Diff) > 0 || r.Action != RevisionActionUpdate
+}
+
+// RestoreInto - decodes the state recorded right after the revision, a delete cannot be restored from.
+func (r *Revision) RestoreInto(snapshot any) error {
+	if len(r.After) == 0 {
+		return ErrRevisionNotRestorable
+	}
+	return json.Unmarshal(r.After, snapshot)
+}
+
+func marshalSnapshot(v any) (json.RawMessage, error) {
+	if v == nil || reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil() {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+func snapshotFields(data json.RawMessage) (map[string]any, error) {
+	fields := make(map[string]any)
+	if len(data) == 0 {
+		return fields, nil
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+type AuthorSnapshot struct {
+	Name   string `json:"name"`
+	Gender bool   `json:"gender"`
+}
+
+type BookSnapshot struct {
+	Title    string `json:"title"`
+	AuthorId int64  `json:"authorId"`
+}
+
+type OperationSnapshot struct {
+	Name        string                     `json:"name"`
+	Description string                     `json:"description"`
+	Commands    []OperationCommandSnapshot `json:"commands"`
+	// DeckProfileID - missing in revisions recorded before operations referenced a deck.
+	DeckProfileID *int64 `json:"deckProfileId,omitempty"`
+}
+
+type OperationCommandSnapshot struct {
+	SystemName string  `json:"systemName"`
+	Address    Address `json:"address"`
+}
+
+func (a *Author) Snapshot() *AuthorSnapshot {
+	return &AuthorSnapshot{Name: a.Name, Gender: a.Gender}
+}
+
+func (b *Book) Snapshot() *BookSnapshot {
+	return &BookSnapshot{Title: b.Title, AuthorId: b.AuthorId}
+}
+
+// AuthorRevision - the revision of a change of the author, a nil author means it did not exist.
+func AuthorRevision(user *UserInfoToken, id int64, action RevisionAction, before, after *Author) RecordRevisionInput {
+	inp := RecordRevisionInput{
+		EntityType: RevisionEntityAuthor,
+		EntityID:   strconv.FormatInt(id, 10),
+		Action:     action,
+		ActorID:    ActorOf(user),
+	}
+	if before != nil {
+		inp.Before = before.Snapshot()
+	}
+	if after != nil {
+		inp.After = after.Snapshot()
+	}
+	return inp
+}
+
+// BookRevision - the revision of a change of the book, a nil book means it did not exist.
+func BookRevision(user *UserInfoToken, id int64, action RevisionAction, before, after *Book) RecordRevisionInput {
+	inp := RecordRevisionInput{
+		EntityType: RevisionEntityBook,
+		EntityID:   strconv.FormatInt(id, 10),
+		Action:     action,
+		ActorID:    ActorOf(user),
+	}
+	if before != nil {
+		inp.Before = before.Snapshot()
+	}
+	if after != nil {
+		inp.After = after.Snapshot()
+	}
+	return inp
+}
+
+func (o *Operation) Snapshot() *OperationSnapshot {
+	s := &OperationSnapshot{
+		Name:          o.Name,
+		Description:   o.Description,
+		Commands:      make([]OperationCommandSnapshot, 0, len(o.Commands)),
+		DeckProfileID: o.DeckProfileID,
+	}
+	for _, c := range o.Commands {
+		s.Commands = append(s.Commands, OperationCommandSnapshot{SystemName: c.SystemName, Address: c.Address})
+	}
+	return s
+}
+
+func (s *OperationSnapshot) CommandInputs() []*CommandInput {
+	items := make([]*CommandInput, 0, len(s.Commands))
+	for _, c := range s.Commands {
+		items = append(items, &CommandInput{SystemName: c.SystemName, Address: c.Address})
+	}
+	return items
+}
+
+// UpdateInput - a full update of the operation back to the snapshot.
+func (s *OperationSnapshot) UpdateInput(id string) UpdateOperationInput {
+	return UpdateOperationInput{
+		ID:            id,
+		Name:          NewPatchField(s.Name),
+		Description:   NewPatchField(s.Description),
+		Commands:      NewPatchField(s.CommandInputs()),
+		DeckProfileID: NewPatchField(s.DeckProfileID),
+	}
+}
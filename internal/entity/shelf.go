@@ -0,0 +1,66 @@
+package entity
+
+import "time"
+
+type ShelfKind string
+
+const (
+	ShelfKindWantToRead ShelfKind = "WANT_TO_READ"
+	ShelfKindReading    ShelfKind = "READING"
+	ShelfKindFinished   ShelfKind = "FINISHED"
+	ShelfKindCustom     ShelfKind = "CUSTOM"
+)
+
+// DefaultShelves - reading status shelves every user has, a book can be on only one of them.
+var DefaultShelves = map[ShelfKind]string{
+	ShelfKindWantToRead: "Want to read",
+	ShelfKindReading:    "Reading",
+	ShelfKindFinished:   "Finished",
+}
+
+func (k ShelfKind) IsStatus() bool {
+	_, ok := DefaultShelves[k]
+	return ok
+}
+
+type Shelf struct {
+	Entity
+	UserID int64        `json:"userId"`
+	Name   string       `json:"name"`
+	Kind   ShelfKind    `json:"kind"`
+	Books  []*ShelfBook `json:"books"`
+}
+
+type ShelfBook struct {
+	Entity
+	ShelfID    int64      `json:"shelfId"`
+	BookID     int64      `json:"bookId"`
+	Book       *Book      `json:"book"`
+	Progress   int        `json:"progress"`
+	StartedAt  *time.Time `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt"`
+}
+
+func (s *Shelf) IsOwnedBy(userID int64) bool {
+	return s.UserID == userID
+}
+
+type CreateShelfInput struct {
+	UserID int64  `json:"-"`
+	Name   string `json:"name"`
+}
+
+type PutShelfBookInput struct {
+	UserID     int64      `json:"-"`
+	ShelfID    int64      `json:"shelfId"`
+	BookID     int64      `json:"bookId"`
+	Progress   *int       `json:"progress"`
+	FinishedAt *time.Time `json:"finishedAt"`
+}
+
+type ReadingSummary struct {
+	Year          int          `json:"year"`
+	FinishedCount int          `json:"finishedCount"`
+	ByMonth       [12]int      `json:"byMonth"`
+	Books         []*ShelfBook `json:"books"`
+}
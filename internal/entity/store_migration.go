@@ -0,0 +1,143 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+)
+
+type StoreMigrationInput struct {
+	From   string
+	To     string
+	DryRun bool
+}
+
+// StoreMigrationStats - what a migration did, or would do in a dry run, with one kind of records.
+type StoreMigrationStats struct {
+	Created   int64 `json:"created"`
+	Updated   int64 `json:"updated"`
+	Unchanged int64 `json:"unchanged"`
+}
+
+// StoreCounts - the number of records of a backend.
+type StoreCounts struct {
+	Commands          int64 `json:"commands"`
+	Operations        int64 `json:"operations"`
+	OperationCommands int64 `json:"operationCommands"`
+}
+
+// StoreSummary - the counts and checksums of the migrated records as seen in one backend.
+type StoreSummary struct {
+	StoreCounts
+	CommandsChecksum   string `json:"commandsChecksum"`
+	OperationsChecksum string `json:"operationsChecksum"`
+}
+
+type StoreMigrationReport struct {
+	From       string              `json:"from"`
+	To         string              `json:"to"`
+	DryRun     bool                `json:"dryRun"`
+	Commands   StoreMigrationStats `json:"commands"`
+	Operations StoreMigrationStats `json:"operations"`
+	Source     StoreSummary        `json:"source"`
+	// Target - nil in a dry run, the target is not verified when nothing was written.
+	Target   *StoreSummary `json:"target,omitempty"`
+	Verified bool          `json:"verified"`
+}
+
+// Origin - the id the operation was first created with, it stays the same
+// however many times the operation moves between the backends.
+func (e *Operation) Origin() string {
+	if e.SourceID != "" {
+		return e.SourceID
+	}
+	return e.ID
+}
+
+type commandRecord struct {
+	CommandSnapshot
+	Disabled bool `json:"disabled"`
+}
+
+type operationRecord struct {
+	Origin           string                     `json:"origin"`
+	Name             string                     `json:"name"`
+	Description      string                     `json:"description"`
+	AverageTime      int64                      `json:"averageTime"`
+	CatalogVersionID *int64                     `json:"catalogVersionId"`
+	DeckProfileID    *int64                     `json:"deckProfileId"`
+	Commands         []OperationCommandSnapshot `json:"commands"`
+}
+
+// MigrationChecksum - the checksum of the command fields both backends store,
+// storage ids and timestamps are left out.
+func (c *Command) MigrationChecksum() string {
+	r := commandRecord{CommandSnapshot: c.Snapshot(), Disabled: c.Disabled}
+	if len(r.Names) == 0 {
+		r.Names = nil
+	}
+	return recordChecksum(r)
+}
+
+// MigrationChecksum - the checksum of the operation fields both backends store,
+// the commands are taken by system name and address because the copies differ between the backends.
+func (e *Operation) MigrationChecksum() string {
+	return recordChecksum(operationRecord{
+		Origin:           e.Origin(),
+		Name:             e.Name,
+		Description:      e.Description,
+		AverageTime:      e.AverageTime,
+		CatalogVersionID: e.CatalogVersionID,
+		DeckProfileID:    e.DeckProfileID,
+		Commands:         e.Snapshot().Commands,
+	})
+}
+
+func recordChecksum(v any) string {
+	data, _ := json.Marshal(v)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// StoreDigest - accumulates the records of a backend into a StoreSummary.
+// The record checksums are added lane by lane, so the digest does not depend on the read order.
+type StoreDigest struct {
+	counts     StoreCounts
+	commands   [4]uint64
+	operations [4]uint64
+}
+
+func (d *StoreDigest) AddCommand(c *Command) {
+	d.counts.Commands++
+	addChecksum(&d.commands, c.MigrationChecksum())
+}
+
+func (d *StoreDigest) AddOperation(e *Operation) {
+	d.counts.Operations++
+	d.counts.OperationCommands += int64(len(e.Commands))
+	addChecksum(&d.operations, e.MigrationChecksum())
+}
+
+func (d *StoreDigest) Summary() StoreSummary {
+	return StoreSummary{
+		StoreCounts:        d.counts,
+		CommandsChecksum:   laneChecksum(d.commands),
+		OperationsChecksum: laneChecksum(d.operations),
+	}
+}
+
+func addChecksum(lanes *[4]uint64, checksum string) {
+	sum, _ := hex.DecodeString(checksum)
+	for i := range lanes {
+		lanes[i] += binary.BigEndian.Uint64(sum[i*8:])
+	}
+}
+
+func laneChecksum(lanes [4]uint64) string {
+	var sum [32]byte
+	for i, v := range lanes {
+		binary.BigEndian.PutUint64(sum[i*8:], v)
+	}
+	return hex.EncodeToString(sum[:])
+}
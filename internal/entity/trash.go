@@ -0,0 +1,31 @@
+package entity
+
+type AuthorDeletePolicy string
+
+const (
+	// AuthorDeletePolicyRestrict - refuse to delete an author who still has books.
+	AuthorDeletePolicyRestrict AuthorDeletePolicy = "restrict"
+	// AuthorDeletePolicyCascade - soft-delete the author's books together with the author.
+	AuthorDeletePolicyCascade AuthorDeletePolicy = "cascade"
+	// AuthorDeletePolicyReassign - move the author's books to another author.
+	AuthorDeletePolicyReassign AuthorDeletePolicy = "reassign"
+)
+
+func (p AuthorDeletePolicy) IsValid() bool {
+	switch p {
+	case AuthorDeletePolicyRestrict, AuthorDeletePolicyCascade, AuthorDeletePolicyReassign:
+		return true
+	}
+	return false
+}
+
+type DeleteAuthorInput struct {
+	ID         int64               `json:"id"`
+	Policy     *AuthorDeletePolicy `json:"policy"`
+	ReassignTo *int64              `json:"reassignTo"`
+}
+
+type Trash struct {
+	Authors []*Author `json:"authors"`
+	Books   []*Book   `json:"books"`
+}
@@ -19,6 +19,7 @@ type User struct {
 	VerifyToken *string
 	FilePath    *string
 	Rating      float32
+	Locale      string
 }
 
 type CreateUserInput struct {
@@ -30,13 +31,25 @@ type CreateUserInput struct {
 	Role     UserRole `json:"role"`
 }
 
+// UpdateUserInput - a merge patch of the user profile.
 type UpdateUserInput struct {
-	ID       int64   `json:"id"`
-	Name     string  `json:"name"`
-	Surname  string  `json:"surname"`
-	Username string  `json:"username"`
-	Rating   float32 `json:"rating"`
+	ID       int64              `json:"id"`
+	Name     PatchField[string] `json:"name"`
+	Surname  PatchField[string] `json:"surname"`
+	Username PatchField[string] `json:"username"`
+	Locale   PatchField[string] `json:"locale"`
 }
+
+func (inp UpdateUserInput) Validate() error {
+	if err := RequiredNonZero(inp.Username, "username"); err != nil {
+		return err
+	}
+	if inp.Locale.Present && !inp.Locale.Null && !IsValidLocale(inp.Locale.Value) {
+		return ErrInvalidLocale
+	}
+	return nil
+}
+
 type ChangePasswordInput struct {
 	ID              int64  `json:"id"`
 	OldPassword     string `json:"oldPassword"`
@@ -4,6 +4,7 @@ import (
 	"context"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"test_go/internal/entity"
+	"time"
 )
 
 type (
@@ -11,6 +12,7 @@ type (
 		Create(context.Context, *entity.User) (*entity.User, error)
 		GetById(context.Context, int64) (*entity.User, error)
 		Update(context.Context, *entity.User) error
+		Patch(context.Context, entity.UpdateUserInput) error
 		GetByUserName(context.Context, string) (*entity.User, error)
 		GetAll(context.Context, entity.FilterUserInput) ([]*entity.User, error)
 		GetByEmail(context.Context, string) (*entity.User, error)
@@ -20,31 +22,115 @@ type (
 	AuthorRepo interface {
 		Create(context.Context, *entity.Author) (*entity.Author, error)
 		GetById(context.Context, int64) (*entity.Author, error)
+		GetByName(context.Context, string) (*entity.Author, error)
 		Update(context.Context, *entity.Author) error
+		Patch(context.Context, entity.UpdateAuthorInput) error
 		GetAll(context.Context) ([]*entity.Author, error)
 		DeleteById(context.Context, int64) error
+		GetDeleted(context.Context) ([]*entity.Author, error)
+		GetDeletedById(context.Context, int64) (*entity.Author, error)
+		Restore(context.Context, int64) error
+		PurgeDeletedBefore(context.Context, time.Time) (int64, error)
 	}
 
 	BookRepo interface {
 		Create(context.Context, *entity.Book) (*entity.Book, error)
 		GetById(context.Context, int64) (*entity.Book, error)
+		GetByTitleAndAuthor(context.Context, string, int64) (*entity.Book, error)
 		Update(context.Context, *entity.Book) error
+		Patch(context.Context, entity.UpdateBookInput) error
 		GetAll(context.Context) ([]*entity.Book, error)
 		DeleteById(context.Context, int64) error
+		CountByAuthorId(context.Context, int64) (int64, error)
+		GetByAuthorId(context.Context, int64) ([]*entity.Book, error)
+		GetStatsByAuthorIds(context.Context, []int64) (map[int64]*entity.AuthorStats, error)
+		ApplyRating(context.Context, int64, int, int) error
+		DeleteByAuthorId(context.Context, int64) ([]int64, error)
+		UpdateCover(context.Context, int64, *string, *string) error
+		ReassignAuthor(context.Context, int64, int64) error
+		GetDeleted(context.Context) ([]*entity.Book, error)
+		GetDeletedById(context.Context, int64) (*entity.Book, error)
+		Restore(context.Context, int64) error
+		RestoreByAuthorId(context.Context, int64, time.Time) ([]int64, error)
+		PurgeDeletedBefore(context.Context, time.Time) ([]int64, error)
+	}
+
+	ShelfRepo interface {
+		EnsureDefaults(context.Context, int64) error
+		Create(context.Context, *entity.Shelf) (*entity.Shelf, error)
+		GetById(context.Context, int64) (*entity.Shelf, error)
+		GetByName(context.Context, int64, string) (*entity.Shelf, error)
+		GetByUserId(context.Context, int64) ([]*entity.Shelf, error)
+		DeleteById(context.Context, int64) error
+		GetBooks(context.Context, []int64) ([]*entity.ShelfBook, error)
+		GetBook(context.Context, int64, int64) (*entity.ShelfBook, error)
+		GetStatusBook(context.Context, int64, int64) (*entity.ShelfBook, error)
+		GetFinished(context.Context, int64, time.Time, time.Time) ([]*entity.ShelfBook, error)
+		AddBook(context.Context, *entity.ShelfBook) (*entity.ShelfBook, error)
+		UpdateBook(context.Context, *entity.ShelfBook) error
+		RemoveBook(context.Context, int64, int64) error
+		DeleteByBookId(context.Context, int64) error
+		DeleteByAuthorId(context.Context, int64) error
+		RestoreByBookId(context.Context, int64, time.Time) error
+		RestoreByAuthorId(context.Context, int64, time.Time) error
+	}
+
+	RevisionRepo interface {
+		Create(context.Context, *entity.Revision) error
+		GetById(context.Context, int64) (*entity.Revision, error)
+		GetByEntity(context.Context, entity.RevisionEntityType, string) ([]*entity.Revision, error)
+	}
+
+	CoverStorage interface {
+		Save(context.Context, int64, *entity.CoverImage) error
+		Stat(context.Context, int64, string, string) (*entity.CoverFile, error)
+		Delete(context.Context, int64) error
+	}
+
+	ReviewRepo interface {
+		Create(context.Context, *entity.Review) (*entity.Review, error)
+		GetById(context.Context, int64) (*entity.Review, error)
+		GetByIdForUpdate(context.Context, int64) (*entity.Review, error)
+		GetByBookAndUser(context.Context, int64, int64) (*entity.Review, error)
+		GetAll(context.Context, entity.FilterReviewInput) ([]*entity.Review, int64, error)
+		Update(context.Context, *entity.Review) error
+		DeleteById(context.Context, int64) error
+		AddFlag(context.Context, entity.FlagReviewInput) error
 	}
 
 	CommandRepo interface {
 		Create(context.Context, *entity.Command) (*entity.Command, error)
 		GetById(context.Context, int64) (*entity.Command, error)
 		Update(context.Context, *entity.Command) error
+		GetBySystemName(context.Context, string) (*entity.Command, error)
 		GetBySystemNames(context.Context) (map[string]entity.Command, error)
+		Disable(context.Context, string, bool) error
+	}
+
+	CommandCatalogRepo interface {
+		Create(context.Context, *entity.CommandCatalogVersion) error
+		GetById(context.Context, int64) (*entity.CommandCatalogVersion, error)
+		GetActive(context.Context) (*entity.CommandCatalogVersion, error)
+		GetAll(context.Context) ([]*entity.CommandCatalogVersion, error)
+		Activate(context.Context, int64) error
+	}
+
+	DeckProfileRepo interface {
+		Create(context.Context, *entity.DeckProfile) error
+		GetById(context.Context, int64) (*entity.DeckProfile, error)
+		GetByName(context.Context, string) (*entity.DeckProfile, error)
+		GetDefault(context.Context) (*entity.DeckProfile, error)
+		GetAll(context.Context) ([]*entity.DeckProfile, error)
+		Update(context.Context, *entity.DeckProfile) error
+		SetDefault(context.Context, int64) error
+		DeleteById(context.Context, int64) error
 	}
 
 	OperationRepo interface {
-		GetAll(context.Context) (map[int64]*entity.Operation, error)
+		GetAll(context.Context) ([]*entity.Operation, error)
 		Create(context.Context, *entity.Operation) (*entity.Operation, error)
 		GetById(context.Context, int64) (*entity.Operation, error)
-		Update(context.Context, *entity.Operation) error
+		Patch(context.Context, int64, *entity.Operation, entity.UpdateOperationInput) error
 		DeleteById(context.Context, int64) error
 	}
 
@@ -59,15 +145,62 @@ type (
 		Create(context.Context, *entity.Command) (*entity.Command, error)
 		GetById(context.Context, primitive.ObjectID) (*entity.Command, error)
 		Update(context.Context, string, *entity.Command) error
-		GetBySystemName(context.Context) (map[string]entity.Command, error)
+		GetBySystemName(context.Context, string) (*entity.Command, error)
+		GetBySystemNames(context.Context) (map[string]entity.Command, error)
 		GetAll(context.Context) ([]entity.Command, error)
+		Disable(context.Context, string, bool) error
 	}
 
 	OperationMongoRepo interface {
-		Create(context.Context, *entity.OperationMongo) (*entity.OperationMongo, error)
-		Update(context.Context, *entity.OperationMongo) error
-		GetById(context.Context, primitive.ObjectID) (*entity.OperationMongo, error)
-		GetAll(context.Context) ([]*entity.OperationMongo, error)
+		Create(context.Context, *entity.Operation) (*entity.Operation, error)
+		Update(context.Context, *entity.Operation) error
+		Patch(context.Context, *entity.Operation, entity.UpdateOperationInput) error
+		GetById(context.Context, primitive.ObjectID) (*entity.Operation, error)
+		GetAll(context.Context) ([]*entity.Operation, error)
 		DeleteById(context.Context, primitive.ObjectID) error
+		HasDeckProfile(context.Context, int64) (bool, error)
+	}
+
+	// DeckProfileRefRepo - a storage that refers to deck profiles without a foreign key,
+	// a profile it still refers to cannot be deleted.
+	DeckProfileRefRepo interface {
+		HasDeckProfile(context.Context, int64) (bool, error)
+	}
+
+	OperationRunRepo interface {
+		Create(context.Context, *entity.OperationRun) error
+		GetById(context.Context, int64) (*entity.OperationRun, error)
+		GetNextQueued(context.Context, int64) (*entity.OperationRun, error)
+		GetQueued(context.Context, int64) ([]*entity.OperationRun, error)
+		GetAll(context.Context, entity.OperationRunFilter) ([]*entity.OperationRun, error)
+		UpdateQueuePosition(context.Context, int64, int) error
+		Update(context.Context, *entity.OperationRun) error
+	}
+
+	InstrumentRepo interface {
+		Create(context.Context, *entity.Instrument) error
+		GetById(context.Context, int64) (*entity.Instrument, error)
+		GetByIdForUpdate(context.Context, int64) (*entity.Instrument, error)
+		GetByName(context.Context, string) (*entity.Instrument, error)
+		GetAll(context.Context) ([]*entity.Instrument, error)
+	}
+
+	// OperationExecutor - drives the instrument through the steps of a run. Execute returns
+	// once the step is done, or with the error of ctx when the run is aborted.
+	OperationExecutor interface {
+		Execute(context.Context, *entity.OperationRunStep) error
+	}
+
+	// StoreMigrationRepo - one side of a migration between the storage backends,
+	// commands are matched by system name and operations by their origin id.
+	StoreMigrationRepo interface {
+		Counts(context.Context) (entity.StoreCounts, error)
+		StreamCommands(context.Context, func(*entity.Command) error) error
+		StreamOperations(context.Context, func(*entity.Operation) error) error
+		GetCommand(context.Context, string) (*entity.Command, error)
+		SaveCommand(context.Context, *entity.Command) error
+		GetOperationByOrigin(context.Context, string) (*entity.Operation, error)
+		CreateOperation(context.Context, *entity.Operation) error
+		UpdateOperation(context.Context, *entity.Operation) error
 	}
 )
@@ -0,0 +1,39 @@
+package executor
+
+import (
+	"context"
+	"time"
+
+	"test_go/internal/entity"
+)
+
+// Clock - the time source of the simulator, tests replace it to run steps without waiting.
+type Clock interface {
+	After(time.Duration) <-chan time.Time
+}
+
+type SystemClock struct{}
+
+func (SystemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Simulator - an executor without an instrument, every step takes its average time.
+type Simulator struct {
+	clock Clock
+	// timeUnit - what a unit of AverageTime lasts.
+	timeUnit time.Duration
+}
+
+func NewSimulator(clock Clock, timeUnit time.Duration) *Simulator {
+	return &Simulator{clock: clock, timeUnit: timeUnit}
+}
+
+func (s *Simulator) Execute(ctx context.Context, step *entity.OperationRunStep) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.clock.After(time.Duration(step.AverageTime) * s.timeUnit):
+		return nil
+	}
+}
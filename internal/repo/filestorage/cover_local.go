@@ -0,0 +1,89 @@
+package filestorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"test_go/internal/entity"
+)
+
+// CoverStorage - keeps book covers on the local disk, one directory per book.
+type CoverStorage struct {
+	basePath string
+}
+
+func NewCoverStorage(basePath string) *CoverStorage {
+	return &CoverStorage{basePath: filepath.Join(basePath, "covers")}
+}
+
+func (s *CoverStorage) dir(bookID int64) string {
+	return filepath.Join(s.basePath, strconv.FormatInt(bookID, 10))
+}
+
+// Save - replaces every file of the book cover with the given image variants.
+func (s *CoverStorage) Save(_ context.Context, bookID int64, cover *entity.CoverImage) error {
+	op := "CoverStorage - Save"
+
+	if err := os.MkdirAll(s.basePath, 0755); err != nil {
+		return fmt.Errorf("%s - os.MkdirAll: %w", op, err)
+	}
+
+	// files are written next to the cover directory and swapped in, so readers never see a partial cover
+	tmp, err := os.MkdirTemp(s.basePath, ".upload-")
+	if err != nil {
+		return fmt.Errorf("%s - os.MkdirTemp: %w", op, err)
+	}
+
+	for variant, data := range cover.Files {
+		name := filepath.Join(tmp, entity.CoverFileName(variant, cover.ContentType))
+		if err = os.WriteFile(name, data, 0644); err != nil {
+			_ = os.RemoveAll(tmp)
+			return fmt.Errorf("%s - os.WriteFile: %w", op, err)
+		}
+	}
+
+	dir := s.dir(bookID)
+	if err = os.RemoveAll(dir); err != nil {
+		_ = os.RemoveAll(tmp)
+		return fmt.Errorf("%s - os.RemoveAll: %w", op, err)
+	}
+
+	if err = os.Rename(tmp, dir); err != nil {
+		_ = os.RemoveAll(tmp)
+		return fmt.Errorf("%s - os.Rename: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *CoverStorage) Stat(_ context.Context, bookID int64, variant, contentType string) (*entity.CoverFile, error) {
+	op := "CoverStorage - Stat"
+
+	path := filepath.Join(s.dir(bookID), entity.CoverFileName(variant, contentType))
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, entity.ErrCoverNotFound
+		}
+		return nil, fmt.Errorf("%s - os.Stat: %w", op, err)
+	}
+
+	return &entity.CoverFile{
+		Path:        path,
+		ContentType: contentType,
+		ModTime:     info.ModTime(),
+	}, nil
+}
+
+func (s *CoverStorage) Delete(_ context.Context, bookID int64) error {
+	if err := os.RemoveAll(s.dir(bookID)); err != nil {
+		return fmt.Errorf("CoverStorage - Delete - os.RemoveAll: %w", err)
+	}
+
+	return nil
+}
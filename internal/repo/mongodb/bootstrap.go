@@ -0,0 +1,99 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"github.com/Alice00021/test_common/pkg/mongodb"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// legacyCommandKeys - the keys the default bson mapping gave to entity.Command fields.
+var legacyCommandKeys = bson.M{
+	"systemname":       "systemName",
+	"averagetime":      "averageTime",
+	"volumewaste":      "volumeWaste",
+	"volumedrivefluid": "volumeDriveFluid",
+	"volumecontainer":  "volumeContainer",
+	"defaultaddress":   "defaultAddress",
+}
+
+// Bootstrap - moves documents written with the default bson mapping to the explicit one
+// and creates the indexes. It is safe to run on every start.
+func Bootstrap(ctx context.Context, client *mongodb.Client) error {
+	op := "mongodb - Bootstrap"
+
+	commands := client.Collection("commands")
+	if _, err := commands.UpdateMany(ctx,
+		bson.M{"systemname": bson.M{"$exists": true}},
+		bson.M{"$rename": legacyCommandKeys, "$unset": bson.M{"entity": ""}},
+	); err != nil {
+		return fmt.Errorf("%s - commands.UpdateMany: %w", op, err)
+	}
+
+	if _, err := commands.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "systemName", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("systemName_unique"),
+	}); err != nil {
+		return fmt.Errorf("%s - commands.Indexes.CreateOne: %w", op, err)
+	}
+
+	// Legacy operations nest the command under "command" and keep a zero "id" next to "_id".
+	operations := client.Collection("operations")
+	if _, err := operations.UpdateMany(ctx,
+		bson.M{"averagetime": bson.M{"$exists": true}},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.D{
+				{Key: "averageTime", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$averageTime", "$averagetime"}}}},
+				{Key: "commands", Value: bson.D{{Key: "$map", Value: bson.D{
+					{Key: "input", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$commands", bson.A{}}}}},
+					{Key: "as", Value: "c"},
+					{Key: "in", Value: bson.D{
+						{Key: "name", Value: "$$c.command.name"},
+						{Key: "systemName", Value: "$$c.command.systemname"},
+						{Key: "reagent", Value: "$$c.command.reagent"},
+						{Key: "averageTime", Value: "$$c.command.averagetime"},
+						{Key: "volumeWaste", Value: "$$c.command.volumewaste"},
+						{Key: "volumeDriveFluid", Value: "$$c.command.volumedrivefluid"},
+						{Key: "volumeContainer", Value: "$$c.command.volumecontainer"},
+						{Key: "defaultAddress", Value: "$$c.command.defaultaddress"},
+						{Key: "address", Value: "$$c.address"},
+					}},
+				}}}},
+			}}},
+			{{Key: "$unset", Value: bson.A{"averagetime", "id"}}},
+		},
+	); err != nil {
+		return fmt.Errorf("%s - operations.UpdateMany: %w", op, err)
+	}
+
+	// Operations written before the timestamps were stored take them from the ObjectID.
+	if _, err := operations.UpdateMany(ctx,
+		bson.M{"createdAt": bson.M{"$exists": false}},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.D{
+				{Key: "createdAt", Value: bson.D{{Key: "$toDate", Value: "$_id"}}},
+				{Key: "updatedAt", Value: bson.D{{Key: "$toDate", Value: "$_id"}}},
+			}}},
+		},
+	); err != nil {
+		return fmt.Errorf("%s - operations.UpdateMany: %w", op, err)
+	}
+
+	if _, err := operations.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "commands.systemName", Value: 1}},
+		Options: options.Index().SetName("commands_systemName"),
+	}); err != nil {
+		return fmt.Errorf("%s - operations.Indexes.CreateOne: %w", op, err)
+	}
+
+	if _, err := operations.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "sourceId", Value: 1}},
+		Options: options.Index().SetUnique(true).SetSparse(true).SetName("sourceId_unique"),
+	}); err != nil {
+		return fmt.Errorf("%s - operations.Indexes.CreateOne: %w", op, err)
+	}
+
+	return nil
+}
@@ -2,12 +2,14 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/Alice00021/test_common/pkg/mongodb"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"test_go/internal/entity"
+	"time"
 )
 
 type CommandMongoRepo struct {
@@ -23,45 +25,72 @@ func NewCommandRepo(client *mongodb.Client) *CommandMongoRepo {
 func (r *CommandMongoRepo) Create(ctx context.Context, cmd *entity.Command) (*entity.Command, error) {
 	op := "CommandMongoRepo - Create"
 
-	res, err := r.coll.InsertOne(ctx, cmd)
+	doc := newCommandDocument(cmd)
+	doc.ID = primitive.NilObjectID
+	doc.CreatedAt = time.Now()
+	doc.UpdatedAt = doc.CreatedAt
+
+	res, err := r.coll.InsertOne(ctx, doc)
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("%s - r.coll.InsertOne: %w", op, entity.ErrCommandAlreadyExists)
+		}
 		return nil, fmt.Errorf("%s - r.coll.InsertOne: %w", op, err)
 	}
 	return r.GetById(ctx, res.InsertedID.(primitive.ObjectID))
 }
 
 func (r *CommandMongoRepo) GetById(ctx context.Context, id primitive.ObjectID) (*entity.Command, error) {
-	var cmd entity.Command
+	return r.findOne(ctx, "CommandMongoRepo - GetById", bson.M{"_id": id})
+}
 
-	err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&cmd)
-	if err != nil {
-		return nil, fmt.Errorf("CommandMongoRepo - GetById: %w", err)
+func (r *CommandMongoRepo) GetBySystemName(ctx context.Context, systemName string) (*entity.Command, error) {
+	return r.findOne(ctx, "CommandMongoRepo - GetBySystemName", bson.M{"systemName": systemName})
+}
+
+func (r *CommandMongoRepo) findOne(ctx context.Context, op string, filter bson.M) (*entity.Command, error) {
+	var doc commandDocument
+
+	if err := r.coll.FindOne(ctx, filter).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, entity.ErrCommandNotFound
+		}
+		return nil, fmt.Errorf("%s - r.coll.FindOne: %w", op, err)
 	}
 
+	cmd := doc.toEntity()
 	return &cmd, nil
 }
 
-func (r *CommandMongoRepo) GetBySystemName(ctx context.Context) (map[string]entity.Command, error) {
-	op := "CommandMongoRepo - GetBySystemName"
+// Disable - hides the command from new operations, operations that use it are kept as is.
+func (r *CommandMongoRepo) Disable(ctx context.Context, systemName string, disabled bool) error {
+	op := "CommandMongoRepo - Disable"
 
-	cursor, err := r.coll.Find(ctx, bson.M{})
+	res, err := r.coll.UpdateOne(ctx,
+		bson.M{"systemName": systemName},
+		bson.M{"$set": bson.M{"disabled": disabled, "updatedAt": time.Now()}},
+	)
 	if err != nil {
-		return nil, fmt.Errorf("%s - r.coll.Find: %w", op, err)
+		return fmt.Errorf("%s - r.coll.UpdateOne: %w", op, err)
+	}
+	if res.MatchedCount == 0 {
+		return entity.ErrCommandNotFound
 	}
-	defer cursor.Close(ctx)
 
-	items := make(map[string]entity.Command)
+	return nil
+}
 
-	for cursor.Next(ctx) {
-		var cmd entity.Command
-		if err := cursor.Decode(&cmd); err != nil {
-			return nil, fmt.Errorf("%s - cursor.Decode: %w", op, err)
-		}
-		items[cmd.SystemName] = cmd
+func (r *CommandMongoRepo) GetBySystemNames(ctx context.Context) (map[string]entity.Command, error) {
+	op := "CommandMongoRepo - GetBySystemNames"
+
+	cmds, err := r.find(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s - %w", op, err)
 	}
 
-	if err := cursor.Err(); err != nil {
-		return nil, fmt.Errorf("%s - cursor.Err: %w", op, err)
+	items := make(map[string]entity.Command, len(cmds))
+	for _, cmd := range cmds {
+		items[cmd.SystemName] = cmd
 	}
 
 	return items, nil
@@ -70,28 +99,64 @@ func (r *CommandMongoRepo) GetBySystemName(ctx context.Context) (map[string]enti
 func (r *CommandMongoRepo) GetAll(ctx context.Context) ([]entity.Command, error) {
 	op := "CommandMongoRepo - GetAll"
 
+	cmds, err := r.find(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s - %w", op, err)
+	}
+
+	return cmds, nil
+}
+
+func (r *CommandMongoRepo) find(ctx context.Context) ([]entity.Command, error) {
 	cursor, err := r.coll.Find(ctx, bson.M{})
 	if err != nil {
-		return nil, fmt.Errorf("%s - r.coll.Find: %w", op, err)
+		return nil, fmt.Errorf("r.coll.Find: %w", err)
 	}
 	defer cursor.Close(ctx)
 
 	var cmds []entity.Command
 	for cursor.Next(ctx) {
-		var cmd entity.Command
-		if err := cursor.Decode(&cmd); err != nil {
-			return nil, fmt.Errorf("%s - cursor.Decode: %w", op, err)
+		var doc commandDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("cursor.Decode: %w", err)
 		}
-		cmds = append(cmds, cmd)
+		cmds = append(cmds, doc.toEntity())
 	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("cursor.Err: %w", err)
+	}
+
 	return cmds, nil
 }
 
+// Update - replaces the catalog fields of the command, the id and creation time are kept.
 func (r *CommandMongoRepo) Update(ctx context.Context, systemName string, cmd *entity.Command) error {
-	_, err := r.coll.UpdateOne(
-		ctx,
+	op := "CommandMongoRepo - Update"
+
+	doc := newCommandDocument(cmd)
+	res, err := r.coll.UpdateOne(ctx,
 		bson.M{"systemName": systemName},
-		bson.M{"$set": cmd},
+		bson.M{"$set": bson.M{
+			"updatedAt":        time.Now(),
+			"name":             doc.Name,
+			"names":            doc.Names,
+			"systemName":       doc.SystemName,
+			"reagent":          doc.Reagent,
+			"averageTime":      doc.AverageTime,
+			"volumeWaste":      doc.VolumeWaste,
+			"volumeDriveFluid": doc.VolumeDriveFluid,
+			"volumeContainer":  doc.VolumeContainer,
+			"defaultAddress":   doc.DefaultAddress,
+			"disabled":         doc.Disabled,
+		}},
 	)
-	return err
+	if err != nil {
+		return fmt.Errorf("%s - r.coll.UpdateOne: %w", op, err)
+	}
+	if res.MatchedCount == 0 {
+		return entity.ErrCommandNotFound
+	}
+
+	return nil
 }
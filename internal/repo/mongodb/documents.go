@@ -0,0 +1,159 @@
+package mongodb
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"test_go/internal/entity"
+	"time"
+)
+
+// commandDocument - a command as stored in the commands collection.
+type commandDocument struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty"`
+	CreatedAt        time.Time          `bson:"createdAt"`
+	UpdatedAt        time.Time          `bson:"updatedAt"`
+	Name             string             `bson:"name"`
+	Names            map[string]string  `bson:"names,omitempty"`
+	SystemName       string             `bson:"systemName"`
+	Reagent          string             `bson:"reagent"`
+	AverageTime      int64              `bson:"averageTime"`
+	VolumeWaste      int64              `bson:"volumeWaste"`
+	VolumeDriveFluid int64              `bson:"volumeDriveFluid"`
+	VolumeContainer  int64              `bson:"volumeContainer"`
+	DefaultAddress   string             `bson:"defaultAddress"`
+	Disabled         bool               `bson:"disabled"`
+}
+
+func newCommandDocument(e *entity.Command) *commandDocument {
+	return &commandDocument{
+		ID:               e.MongoID,
+		CreatedAt:        e.CreatedAt,
+		UpdatedAt:        e.UpdatedAt,
+		Name:             e.Name,
+		Names:            e.Names,
+		SystemName:       e.SystemName,
+		Reagent:          string(e.Reagent),
+		AverageTime:      e.AverageTime,
+		VolumeWaste:      e.VolumeWaste,
+		VolumeDriveFluid: e.VolumeDriveFluid,
+		VolumeContainer:  e.VolumeContainer,
+		DefaultAddress:   string(e.DefaultAddress),
+		Disabled:         e.Disabled,
+	}
+}
+
+func (d *commandDocument) toEntity() entity.Command {
+	return entity.Command{
+		Entity: entity.Entity{
+			CreatedAt: d.CreatedAt,
+			UpdatedAt: d.UpdatedAt,
+		},
+		MongoID:          d.ID,
+		Name:             d.Name,
+		Names:            d.Names,
+		SystemName:       d.SystemName,
+		Reagent:          entity.ReagentType(d.Reagent),
+		AverageTime:      d.AverageTime,
+		VolumeWaste:      d.VolumeWaste,
+		VolumeDriveFluid: d.VolumeDriveFluid,
+		VolumeContainer:  d.VolumeContainer,
+		DefaultAddress:   entity.Address(d.DefaultAddress),
+		Disabled:         d.Disabled,
+	}
+}
+
+// operationDocument - an operation as stored in the operations collection,
+// its commands are copies of the catalog entries at the time they were added.
+type operationDocument struct {
+	ID               primitive.ObjectID          `bson:"_id,omitempty"`
+	CreatedAt        time.Time                   `bson:"createdAt"`
+	UpdatedAt        time.Time                   `bson:"updatedAt"`
+	Name             string                      `bson:"name"`
+	Description      string                      `bson:"description"`
+	AverageTime      int64                       `bson:"averageTime"`
+	Commands         []*operationCommandDocument `bson:"commands"`
+	CatalogVersionID *int64                      `bson:"catalogVersionId,omitempty"`
+	DeckProfileID    *int64                      `bson:"deckProfileId,omitempty"`
+	SourceID         string                      `bson:"sourceId,omitempty"`
+}
+
+type operationCommandDocument struct {
+	CommandID        primitive.ObjectID `bson:"commandId,omitempty"`
+	Name             string             `bson:"name"`
+	SystemName       string             `bson:"systemName"`
+	Reagent          string             `bson:"reagent"`
+	AverageTime      int64              `bson:"averageTime"`
+	VolumeWaste      int64              `bson:"volumeWaste"`
+	VolumeDriveFluid int64              `bson:"volumeDriveFluid"`
+	VolumeContainer  int64              `bson:"volumeContainer"`
+	DefaultAddress   string             `bson:"defaultAddress"`
+	Address          string             `bson:"address"`
+}
+
+// newOperationDocument - an empty or malformed ID leaves the document without one.
+func newOperationDocument(e *entity.Operation) *operationDocument {
+	id, _ := primitive.ObjectIDFromHex(e.ID)
+
+	return &operationDocument{
+		ID:               id,
+		CreatedAt:        e.CreatedAt,
+		UpdatedAt:        e.UpdatedAt,
+		Name:             e.Name,
+		Description:      e.Description,
+		AverageTime:      e.AverageTime,
+		Commands:         newOperationCommandDocuments(e.Commands),
+		CatalogVersionID: e.CatalogVersionID,
+		DeckProfileID:    e.DeckProfileID,
+		SourceID:         e.SourceID,
+	}
+}
+
+func newOperationCommandDocuments(commands []*entity.OperationCommand) []*operationCommandDocument {
+	docs := make([]*operationCommandDocument, 0, len(commands))
+	for _, c := range commands {
+		docs = append(docs, &operationCommandDocument{
+			CommandID:        c.MongoID,
+			Name:             c.Name,
+			SystemName:       c.SystemName,
+			Reagent:          string(c.Reagent),
+			AverageTime:      c.AverageTime,
+			VolumeWaste:      c.VolumeWaste,
+			VolumeDriveFluid: c.VolumeDriveFluid,
+			VolumeContainer:  c.VolumeContainer,
+			DefaultAddress:   string(c.DefaultAddress),
+			Address:          string(c.Address),
+		})
+	}
+	return docs
+}
+
+func (d *operationDocument) toEntity() *entity.Operation {
+	e := &entity.Operation{
+		ID:               d.ID.Hex(),
+		CreatedAt:        d.CreatedAt,
+		UpdatedAt:        d.UpdatedAt,
+		Name:             d.Name,
+		Description:      d.Description,
+		AverageTime:      d.AverageTime,
+		Commands:         make([]*entity.OperationCommand, 0, len(d.Commands)),
+		CatalogVersionID: d.CatalogVersionID,
+		DeckProfileID:    d.DeckProfileID,
+		SourceID:         d.SourceID,
+	}
+	for _, c := range d.Commands {
+		e.Commands = append(e.Commands, &entity.OperationCommand{
+			Command: entity.Command{
+				MongoID:          c.CommandID,
+				Name:             c.Name,
+				SystemName:       c.SystemName,
+				Reagent:          entity.ReagentType(c.Reagent),
+				AverageTime:      c.AverageTime,
+				VolumeWaste:      c.VolumeWaste,
+				VolumeDriveFluid: c.VolumeDriveFluid,
+				VolumeContainer:  c.VolumeContainer,
+				DefaultAddress:   entity.Address(c.DefaultAddress),
+			},
+			Address: entity.Address(c.Address),
+		})
+	}
+	return e
+}
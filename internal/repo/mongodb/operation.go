@@ -2,12 +2,15 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/Alice00021/test_common/pkg/mongodb"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"test_go/internal/entity"
+	"time"
 )
 
 type OperationMongoRepo struct {
@@ -20,63 +23,129 @@ func NewOperationRepo(client *mongodb.Client) *OperationMongoRepo {
 	}
 }
 
-func (r *OperationMongoRepo) Create(ctx context.Context, operation *entity.OperationMongo) (*entity.OperationMongo, error) {
+func (r *OperationMongoRepo) Create(ctx context.Context, operation *entity.Operation) (*entity.Operation, error) {
 	op := "OperationMongoRepo - Create"
 
-	res, err := r.coll.InsertOne(ctx, operation)
+	now := time.Now()
+	doc := newOperationDocument(operation)
+	doc.ID = primitive.NilObjectID
+	doc.CreatedAt, doc.UpdatedAt = now, now
+
+	res, err := r.coll.InsertOne(ctx, doc)
 	if err != nil {
 		return nil, fmt.Errorf("%s - r.coll.InsertOne: %w", op, err)
 	}
 	return r.GetById(ctx, res.InsertedID.(primitive.ObjectID))
 }
 
-func (r *OperationMongoRepo) Update(ctx context.Context, operation *entity.OperationMongo) error {
+func (r *OperationMongoRepo) Update(ctx context.Context, operation *entity.Operation) error {
 	op := "OperationMongoRepo - Update"
-	id := bson.M{"_id": operation.Id}
 
+	doc := newOperationDocument(operation)
 	update := bson.M{
 		"$set": bson.M{
-			"name":        operation.Name,
-			"description": operation.Description,
-			"averageTime": operation.AverageTime,
-			"commands":    operation.Commands,
+			"name":             doc.Name,
+			"description":      doc.Description,
+			"averageTime":      doc.AverageTime,
+			"commands":         doc.Commands,
+			"catalogVersionId": doc.CatalogVersionID,
+			"deckProfileId":    doc.DeckProfileID,
+			"updatedAt":        time.Now(),
 		},
 	}
-	_, err := r.coll.UpdateOne(ctx, id, update)
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": doc.ID}, update)
 	if err != nil {
 		return fmt.Errorf("%s - r.coll.UpdateOne: %w", op, err)
 	}
-	return err
+	if res.MatchedCount == 0 {
+		return entity.ErrOperationNotFound
+	}
+	return nil
 }
 
-func (r *OperationMongoRepo) GetById(ctx context.Context, id primitive.ObjectID) (*entity.OperationMongo, error) {
-	var op entity.OperationMongo
+// Patch - sets only the fields carried by the patch, values are taken from e
+// so that the resolved commands and their average time are stored together.
+func (r *OperationMongoRepo) Patch(ctx context.Context, operation *entity.Operation, inp entity.UpdateOperationInput) error {
+	op := "OperationMongoRepo - Patch"
 
-	err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&op)
+	set := bson.M{}
+	if inp.Name.Present {
+		set["name"] = operation.Name
+	}
+	if inp.Description.Present {
+		set["description"] = operation.Description
+	}
+	if inp.Commands.Present {
+		set["commands"] = newOperationCommandDocuments(operation.Commands)
+		set["averageTime"] = operation.AverageTime
+		set["catalogVersionId"] = operation.CatalogVersionID
+	}
+	if inp.DeckProfileID.Present {
+		set["deckProfileId"] = operation.DeckProfileID
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	set["updatedAt"] = time.Now()
+
+	doc := newOperationDocument(operation)
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": doc.ID}, bson.M{"$set": set})
 	if err != nil {
+		return fmt.Errorf("%s - r.coll.UpdateOne: %w", op, err)
+	}
+	if res.MatchedCount == 0 {
+		return entity.ErrOperationNotFound
+	}
+	return nil
+}
+
+// HasDeckProfile - whether any operation refers to the deck profile,
+// the check the foreign key does in postgres when the profile is deleted.
+func (r *OperationMongoRepo) HasDeckProfile(ctx context.Context, deckProfileID int64) (bool, error) {
+	count, err := r.coll.CountDocuments(ctx, bson.M{"deckProfileId": deckProfileID}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("OperationMongoRepo - HasDeckProfile - r.coll.CountDocuments: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *OperationMongoRepo) GetById(ctx context.Context, id primitive.ObjectID) (*entity.Operation, error) {
+	var doc operationDocument
+
+	err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, entity.ErrOperationNotFound
+		}
 		return nil, fmt.Errorf("OperationMongoRepo - GetById: %w", err)
 	}
 
-	return &op, nil
+	return doc.toEntity(), nil
 }
 
-func (r *OperationMongoRepo) GetAll(ctx context.Context) ([]*entity.OperationMongo, error) {
+func (r *OperationMongoRepo) GetAll(ctx context.Context) ([]*entity.Operation, error) {
 	op := "OperationMongoRepo - GetAll"
 
-	cursor, err := r.coll.Find(ctx, bson.M{})
+	// newest first, the same order as the postgres repo
+	cursor, err := r.coll.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}))
 	if err != nil {
 		return nil, fmt.Errorf("%s - r.coll.Find: %w", op, err)
 	}
 	defer cursor.Close(ctx)
 
-	var operations []*entity.OperationMongo
+	var operations []*entity.Operation
 	for cursor.Next(ctx) {
-		var oper entity.OperationMongo
-		if err := cursor.Decode(&oper); err != nil {
+		var doc operationDocument
+		if err := cursor.Decode(&doc); err != nil {
 			return nil, fmt.Errorf("%s - cursor.Decode: %w", op, err)
 		}
-		operations = append(operations, &oper)
+		operations = append(operations, doc.toEntity())
 	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("%s - cursor.Err: %w", op, err)
+	}
+
 	return operations, nil
 }
 
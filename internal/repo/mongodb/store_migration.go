@@ -0,0 +1,236 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Alice00021/test_common/pkg/mongodb"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"test_go/internal/entity"
+	"time"
+)
+
+// StoreMigrationRepo - the mongo side of a store migration.
+type StoreMigrationRepo struct {
+	commands   *CommandMongoRepo
+	operations *mongo.Collection
+}
+
+func NewStoreMigrationRepo(client *mongodb.Client) *StoreMigrationRepo {
+	return &StoreMigrationRepo{
+		commands:   NewCommandRepo(client),
+		operations: client.Collection("operations"),
+	}
+}
+
+func (r *StoreMigrationRepo) Counts(ctx context.Context) (entity.StoreCounts, error) {
+	op := "StoreMigrationRepo - Counts"
+
+	var (
+		counts entity.StoreCounts
+		err    error
+	)
+	if counts.Commands, err = r.commands.coll.CountDocuments(ctx, bson.M{}); err != nil {
+		return counts, fmt.Errorf("%s - commands.CountDocuments: %w", op, err)
+	}
+
+	cursor, err := r.operations.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: nil},
+			{Key: "operations", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "commands", Value: bson.D{{Key: "$sum", Value: bson.D{
+				{Key: "$size", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$commands", bson.A{}}}}},
+			}}}},
+		}}},
+	})
+	if err != nil {
+		return counts, fmt.Errorf("%s - operations.Aggregate: %w", op, err)
+	}
+	defer cursor.Close(ctx)
+
+	var res []struct {
+		Operations int64 `bson:"operations"`
+		Commands   int64 `bson:"commands"`
+	}
+	if err := cursor.All(ctx, &res); err != nil {
+		return counts, fmt.Errorf("%s - cursor.All: %w", op, err)
+	}
+	if len(res) > 0 {
+		counts.Operations, counts.OperationCommands = res[0].Operations, res[0].Commands
+	}
+
+	return counts, nil
+}
+
+func (r *StoreMigrationRepo) StreamCommands(ctx context.Context, fn func(*entity.Command) error) error {
+	op := "StoreMigrationRepo - StreamCommands"
+
+	cursor, err := r.commands.coll.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "systemName", Value: 1}}))
+	if err != nil {
+		return fmt.Errorf("%s - commands.Find: %w", op, err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc commandDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return fmt.Errorf("%s - cursor.Decode: %w", op, err)
+		}
+
+		c := doc.toEntity()
+		if err := fn(&c); err != nil {
+			return err
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("%s - cursor.Err: %w", op, err)
+	}
+	return nil
+}
+
+func (r *StoreMigrationRepo) StreamOperations(ctx context.Context, fn func(*entity.Operation) error) error {
+	op := "StoreMigrationRepo - StreamOperations"
+
+	cursor, err := r.operations.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return fmt.Errorf("%s - operations.Find: %w", op, err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc operationDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return fmt.Errorf("%s - cursor.Decode: %w", op, err)
+		}
+
+		if err := fn(doc.toEntity()); err != nil {
+			return err
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("%s - cursor.Err: %w", op, err)
+	}
+	return nil
+}
+
+func (r *StoreMigrationRepo) GetCommand(ctx context.Context, systemName string) (*entity.Command, error) {
+	return r.commands.GetBySystemName(ctx, systemName)
+}
+
+// SaveCommand - inserts the command or overwrites the one with the same system name.
+func (r *StoreMigrationRepo) SaveCommand(ctx context.Context, e *entity.Command) error {
+	op := "StoreMigrationRepo - SaveCommand"
+
+	doc := newCommandDocument(e)
+	now := time.Now()
+	if _, err := r.commands.coll.UpdateOne(ctx,
+		bson.M{"systemName": doc.SystemName},
+		bson.M{
+			"$set": bson.M{
+				"name":             doc.Name,
+				"names":            doc.Names,
+				"reagent":          doc.Reagent,
+				"averageTime":      doc.AverageTime,
+				"volumeWaste":      doc.VolumeWaste,
+				"volumeDriveFluid": doc.VolumeDriveFluid,
+				"volumeContainer":  doc.VolumeContainer,
+				"defaultAddress":   doc.DefaultAddress,
+				"disabled":         doc.Disabled,
+				"updatedAt":        now,
+			},
+			"$setOnInsert": bson.M{"createdAt": now},
+		},
+		options.Update().SetUpsert(true),
+	); err != nil {
+		return fmt.Errorf("%s - commands.UpdateOne: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetOperationByOrigin - the operation migrated from the origin id,
+// or the one created here with that id.
+func (r *StoreMigrationRepo) GetOperationByOrigin(ctx context.Context, origin string) (*entity.Operation, error) {
+	filter := bson.A{bson.M{"sourceId": origin}}
+	if id, err := primitive.ObjectIDFromHex(origin); err == nil {
+		filter = append(filter, bson.M{"_id": id, "sourceId": bson.M{"$exists": false}})
+	}
+
+	var doc operationDocument
+	if err := r.operations.FindOne(ctx, bson.M{"$or": filter}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, entity.ErrOperationNotFound
+		}
+		return nil, fmt.Errorf("StoreMigrationRepo - GetOperationByOrigin - operations.FindOne: %w", err)
+	}
+
+	return doc.toEntity(), nil
+}
+
+// CreateOperation - keeps the origin of the operation in sourceId,
+// the commands are copied from the catalog of this store.
+func (r *StoreMigrationRepo) CreateOperation(ctx context.Context, e *entity.Operation) error {
+	op := "StoreMigrationRepo - CreateOperation"
+
+	doc := newOperationDocument(e)
+	doc.ID = primitive.NilObjectID
+	doc.SourceID = e.Origin()
+
+	var err error
+	if doc.Commands, err = r.commandCopies(ctx, e.Commands); err != nil {
+		return fmt.Errorf("%s - %w", op, err)
+	}
+
+	if _, err := r.operations.InsertOne(ctx, doc); err != nil {
+		return fmt.Errorf("%s - operations.InsertOne: %w", op, err)
+	}
+	return nil
+}
+
+func (r *StoreMigrationRepo) UpdateOperation(ctx context.Context, e *entity.Operation) error {
+	op := "StoreMigrationRepo - UpdateOperation"
+
+	doc := newOperationDocument(e)
+
+	commands, err := r.commandCopies(ctx, e.Commands)
+	if err != nil {
+		return fmt.Errorf("%s - %w", op, err)
+	}
+
+	res, err := r.operations.UpdateOne(ctx, bson.M{"_id": doc.ID}, bson.M{"$set": bson.M{
+		"name":             doc.Name,
+		"description":      doc.Description,
+		"averageTime":      doc.AverageTime,
+		"commands":         commands,
+		"catalogVersionId": doc.CatalogVersionID,
+		"deckProfileId":    doc.DeckProfileID,
+		"updatedAt":        doc.UpdatedAt,
+	}})
+	if err != nil {
+		return fmt.Errorf("%s - operations.UpdateOne: %w", op, err)
+	}
+	if res.MatchedCount == 0 {
+		return entity.ErrOperationNotFound
+	}
+	return nil
+}
+
+// commandCopies - the operation commands with the catalog fields of this store,
+// every command has to be migrated before the operations that use it.
+func (r *StoreMigrationRepo) commandCopies(ctx context.Context, commands []*entity.OperationCommand) ([]*operationCommandDocument, error) {
+	copies := make([]*entity.OperationCommand, 0, len(commands))
+	for _, c := range commands {
+		stored, err := r.commands.GetBySystemName(ctx, c.SystemName)
+		if err != nil {
+			return nil, fmt.Errorf("r.commands.GetBySystemName: %w: %s", err, c.SystemName)
+		}
+		copies = append(copies, &entity.OperationCommand{Command: *stored, Address: c.Address})
+	}
+
+	return newOperationCommandDocuments(copies), nil
+}
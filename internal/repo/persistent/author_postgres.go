@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5"
@@ -77,12 +78,50 @@ func (r *AuthorRepo) GetById(ctx context.Context, id int64) (*entity.Author, err
 	return author, nil
 }
 
+// GetByName - case-insensitive lookup among not deleted authors.
+func (r *AuthorRepo) GetByName(ctx context.Context, name string) (*entity.Author, error) {
+	op := "AuthorRepo - GetByName"
+
+	sql, args, err := r.Builder.
+		Select(
+			"id", "created_at", "updated_at", "deleted_at", "name",
+			"gender",
+		).
+		From("authors").
+		Where("deleted_at IS NULL").
+		Where("LOWER(name) = LOWER(?)", name).
+		OrderBy("id").
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	rows, err := client.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s - client.Query: %w", op, err)
+	}
+
+	author, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[entity.Author])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrAuthorNotFound
+		}
+
+		return nil, fmt.Errorf("%s - pgx.CollectOneRow: %w", op, err)
+	}
+	return author, nil
+}
+
 func (r *AuthorRepo) Update(ctx context.Context, e *entity.Author) error {
 	op := "AuthorRepo - Update"
 
 	sqlBuilder := r.Builder.
 		Update("authors").
 		Set("name", e.Name).
+		Set("gender", e.Gender).
+		Set("updated_at", squirrel.Expr("NOW()")).
 		Where(squirrel.Eq{"id": e.ID})
 
 	sql, args, err := sqlBuilder.ToSql()
@@ -98,12 +137,36 @@ func (r *AuthorRepo) Update(ctx context.Context, e *entity.Author) error {
 	return nil
 }
 
+// Patch - updates only the columns carried by the patch.
+func (r *AuthorRepo) Patch(ctx context.Context, inp entity.UpdateAuthorInput) error {
+	op := "AuthorRepo - Patch"
+
+	sqlBuilder := r.Builder.
+		Update("authors").
+		Set("updated_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"id": inp.ID})
+	sqlBuilder = setPatch(sqlBuilder, "name", inp.Name)
+	sqlBuilder = setPatch(sqlBuilder, "gender", inp.Gender)
+
+	sql, args, err := sqlBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	if _, err = client.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	return nil
+}
+
 func (r *AuthorRepo) DeleteById(ctx context.Context, id int64) error {
 	op := "AuthorRepo - DeleteById"
 
 	builder := r.Builder.
 		Update("authors").
-		Set("deleted_at", "NOW()").
+		Set("deleted_at", squirrel.Expr("NOW()")).
 		Where(squirrel.Eq{"id": id}).
 		Where("deleted_at IS NULL")
 
@@ -162,3 +225,114 @@ func (r *AuthorRepo) GetAll(ctx context.Context) ([]*entity.Author, error) {
 
 	return items, nil
 }
+
+func (r *AuthorRepo) GetDeleted(ctx context.Context) ([]*entity.Author, error) {
+	op := "AuthorRepo - GetDeleted"
+
+	sql, args, err := r.Builder.
+		Select(
+			"id", "created_at", "updated_at", "deleted_at", "name",
+			"gender",
+		).
+		From("authors").
+		Where("deleted_at IS NOT NULL").
+		OrderBy("deleted_at DESC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	rows, err := client.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s - client.Query: %w", op, err)
+	}
+
+	items, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[entity.Author])
+	if err != nil {
+		return nil, fmt.Errorf("%s - pgx.CollectRows: %w", op, err)
+	}
+
+	return items, nil
+}
+
+func (r *AuthorRepo) GetDeletedById(ctx context.Context, id int64) (*entity.Author, error) {
+	op := "AuthorRepo - GetDeletedById"
+
+	sql, args, err := r.Builder.
+		Select(
+			"id", "created_at", "updated_at", "deleted_at", "name",
+			"gender",
+		).
+		From("authors").
+		Where("deleted_at IS NOT NULL").
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	rows, err := client.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s - client.Query: %w", op, err)
+	}
+
+	author, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[entity.Author])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrNotInTrash
+		}
+
+		return nil, fmt.Errorf("%s - pgx.CollectOneRow: %w", op, err)
+	}
+	return author, nil
+}
+
+func (r *AuthorRepo) Restore(ctx context.Context, id int64) error {
+	op := "AuthorRepo - Restore"
+
+	sql, args, err := r.Builder.
+		Update("authors").
+		Set("deleted_at", nil).
+		Where(squirrel.Eq{"id": id}).
+		Where("deleted_at IS NOT NULL").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	tag, err := client.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.ErrNotInTrash
+	}
+	return nil
+}
+
+// PurgeDeletedBefore - hard-deletes authors soft-deleted before the given time.
+// Authors still referenced by a book (even a soft-deleted one) are kept until the book is purged.
+func (r *AuthorRepo) PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error) {
+	op := "AuthorRepo - PurgeDeletedBefore"
+
+	sql, args, err := r.Builder.
+		Delete("authors").
+		Where(squirrel.Lt{"deleted_at": before}).
+		Where("NOT EXISTS (SELECT 1 FROM books b WHERE b.author_id = authors.id)").
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	tag, err := client.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5"
@@ -25,8 +26,8 @@ func (r *BookRepo) Create(ctx context.Context, e *entity.Book) (*entity.Book, er
 
 	sql, args, err := r.Builder.
 		Insert("books").
-		Columns("title, author_id").
-		Values(e.Title, e.AuthorId).
+		Columns("title, author_id, created_by").
+		Values(e.Title, e.AuthorId, e.CreatedBy).
 		Suffix(`RETURNING id`).
 		ToSql()
 	if err != nil {
@@ -50,7 +51,8 @@ func (r *BookRepo) GetById(ctx context.Context, id int64) (*entity.Book, error)
 	sql, args, err := r.Builder.
 		Select(
 			"b.id", "b.created_at", "b.updated_at", "b.deleted_at",
-			"b.title", "b.author_id",
+			"b.title", "b.author_id", "b.created_by",
+			"b.rating_avg", "b.rating_count", "b.cover_hash", "b.cover_type",
 			"a.name", "a.gender",
 		).
 		From("books b").
@@ -67,7 +69,8 @@ func (r *BookRepo) GetById(ctx context.Context, id int64) (*entity.Book, error)
 
 	var e entity.Book
 	if err = row.Scan(
-		&e.ID, &e.CreatedAt, &e.UpdatedAt, &e.DeletedAt, &e.Title, &e.AuthorId,
+		&e.ID, &e.CreatedAt, &e.UpdatedAt, &e.DeletedAt, &e.Title, &e.AuthorId, &e.CreatedBy,
+		&e.RatingAvg, &e.RatingCount, &e.CoverHash, &e.CoverType,
 		&e.Author.Name, &e.Author.Gender,
 	); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -78,6 +81,46 @@ func (r *BookRepo) GetById(ctx context.Context, id int64) (*entity.Book, error)
 	}
 
 	e.Author.ID = e.AuthorId
+	e.FillCoverURL()
+	return &e, nil
+}
+
+func (r *BookRepo) GetByTitleAndAuthor(ctx context.Context, title string, authorId int64) (*entity.Book, error) {
+	op := "BookRepo - GetByTitleAndAuthor"
+
+	sql, args, err := r.Builder.
+		Select(
+			"id", "created_at", "updated_at", "deleted_at",
+			"title", "author_id", "created_by",
+			"rating_avg", "rating_count", "cover_hash", "cover_type",
+		).
+		From("books").
+		Where("deleted_at IS NULL").
+		Where("LOWER(title) = LOWER(?)", title).
+		Where(squirrel.Eq{"author_id": authorId}).
+		OrderBy("id").
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	row := client.QueryRow(ctx, sql, args...)
+
+	var e entity.Book
+	if err = row.Scan(
+		&e.ID, &e.CreatedAt, &e.UpdatedAt, &e.DeletedAt, &e.Title, &e.AuthorId, &e.CreatedBy,
+		&e.RatingAvg, &e.RatingCount, &e.CoverHash, &e.CoverType,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrBookNotFound
+		}
+
+		return nil, fmt.Errorf("%s - row.Scan: %w", op, err)
+	}
+
+	e.FillCoverURL()
 	return &e, nil
 }
 
@@ -103,12 +146,36 @@ func (r *BookRepo) Update(ctx context.Context, e *entity.Book) error {
 	return nil
 }
 
+// Patch - updates only the columns carried by the patch.
+func (r *BookRepo) Patch(ctx context.Context, inp entity.UpdateBookInput) error {
+	op := "BookRepo - Patch"
+
+	sqlBuilder := r.Builder.
+		Update("books").
+		Set("updated_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"id": inp.ID})
+	sqlBuilder = setPatch(sqlBuilder, "title", inp.Title)
+	sqlBuilder = setPatch(sqlBuilder, "author_id", inp.AuthorId)
+
+	sql, args, err := sqlBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	if _, err = client.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	return nil
+}
+
 func (r *BookRepo) DeleteById(ctx context.Context, id int64) error {
 	op := "BookRepo - DeleteById"
 
 	builder := r.Builder.
 		Update("books").
-		Set("deleted_at", "NOW()").
+		Set("deleted_at", squirrel.Expr("NOW()")).
 		Where(squirrel.Eq{"id": id}).
 		Where("deleted_at IS NULL")
 
@@ -132,7 +199,8 @@ func (r *BookRepo) GetAll(ctx context.Context) ([]*entity.Book, error) {
 	sqlBuilder := r.Builder.
 		Select(
 			"b.id", "b.created_at", "b.updated_at", "b.deleted_at",
-			"b.title", "b.author_id",
+			"b.title", "b.author_id", "b.created_by",
+			"b.rating_avg", "b.rating_count", "b.cover_hash", "b.cover_type",
 			"a.name", "a.gender",
 		).
 		From("books b").
@@ -159,7 +227,283 @@ func (r *BookRepo) GetAll(ctx context.Context) ([]*entity.Book, error) {
 		e := entity.Book{}
 
 		if err = rows.Scan(
-			&e.ID, &e.CreatedAt, &e.UpdatedAt, &e.DeletedAt, &e.Title, &e.AuthorId,
+			&e.ID, &e.CreatedAt, &e.UpdatedAt, &e.DeletedAt, &e.Title, &e.AuthorId, &e.CreatedBy,
+			&e.RatingAvg, &e.RatingCount, &e.CoverHash, &e.CoverType,
+			&e.Author.Name, &e.Author.Gender,
+		); err != nil {
+			return nil, fmt.Errorf("%s - row.Scan: %w", op, err)
+		}
+
+		e.Author.ID = e.AuthorId
+		e.FillCoverURL()
+
+		items = append(items, &e)
+	}
+
+	return items, nil
+}
+
+func (r *BookRepo) CountByAuthorId(ctx context.Context, authorId int64) (int64, error) {
+	op := "BookRepo - CountByAuthorId"
+
+	sql, args, err := r.Builder.
+		Select("COUNT(*)").
+		From("books").
+		Where("deleted_at IS NULL").
+		Where(squirrel.Eq{"author_id": authorId}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+
+	var count int64
+	if err = client.QueryRow(ctx, sql, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("%s - client.QueryRow: %w", op, err)
+	}
+
+	return count, nil
+}
+
+// GetByAuthorId - the books of the author that are not deleted.
+func (r *BookRepo) GetByAuthorId(ctx context.Context, authorId int64) ([]*entity.Book, error) {
+	op := "BookRepo - GetByAuthorId"
+
+	sql, args, err := r.Builder.
+		Select(
+			"id", "created_at", "updated_at", "deleted_at",
+			"title", "author_id", "created_by",
+			"rating_avg", "rating_count", "cover_hash", "cover_type",
+		).
+		From("books").
+		Where("deleted_at IS NULL").
+		Where(squirrel.Eq{"author_id": authorId}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	rows, err := client.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s - client.Query: %w", op, err)
+	}
+	defer rows.Close()
+
+	items := make([]*entity.Book, 0, 16)
+
+	for rows.Next() {
+		e := entity.Book{}
+
+		if err = rows.Scan(
+			&e.ID, &e.CreatedAt, &e.UpdatedAt, &e.DeletedAt, &e.Title, &e.AuthorId, &e.CreatedBy,
+			&e.RatingAvg, &e.RatingCount, &e.CoverHash, &e.CoverType,
+		); err != nil {
+			return nil, fmt.Errorf("%s - row.Scan: %w", op, err)
+		}
+
+		items = append(items, &e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s - rows.Err: %w", op, err)
+	}
+
+	return items, nil
+}
+
+// GetStatsByAuthorIds - book count, publication dates and the rating across all reviews of the books
+// per author, all authors when ids is empty.
+// Status is left empty, it is a domain rule applied by the use case.
+func (r *BookRepo) GetStatsByAuthorIds(ctx context.Context, ids []int64) (map[int64]*entity.AuthorStats, error) {
+	op := "BookRepo - GetStatsByAuthorIds"
+
+	sqlBuilder := r.Builder.
+		Select(
+			"author_id", "COUNT(*)", "MIN(created_at)", "MAX(created_at)",
+			"COALESCE(SUM(rating_sum)::FLOAT8 / NULLIF(SUM(rating_count), 0), 0)",
+			"COALESCE(SUM(rating_count), 0)",
+		).
+		From("books").
+		Where("deleted_at IS NULL").
+		GroupBy("author_id")
+
+	if len(ids) > 0 {
+		sqlBuilder = sqlBuilder.Where(squirrel.Eq{"author_id": ids})
+	}
+
+	sql, args, err := sqlBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	rows, err := client.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s - client.Query: %w", op, err)
+	}
+	defer rows.Close()
+
+	items := make(map[int64]*entity.AuthorStats)
+
+	for rows.Next() {
+		var (
+			authorId int64
+			e        entity.AuthorStats
+		)
+
+		if err = rows.Scan(
+			&authorId, &e.BookCount, &e.FirstPublishedAt, &e.LastPublishedAt, &e.RatingAvg, &e.RatingCount,
+		); err != nil {
+			return nil, fmt.Errorf("%s - rows.Scan: %w", op, err)
+		}
+
+		items[authorId] = &e
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s - rows.Err: %w", op, err)
+	}
+
+	return items, nil
+}
+
+// ApplyRating - shifts the rating sum and count by the given deltas and recalculates the average
+// in a single statement, so concurrent reviews are serialized by the row lock.
+func (r *BookRepo) ApplyRating(ctx context.Context, id int64, sumDelta, countDelta int) error {
+	op := "BookRepo - ApplyRating"
+
+	sql, args, err := r.Builder.
+		Update("books").
+		Set("rating_sum", squirrel.Expr("rating_sum + ?", sumDelta)).
+		Set("rating_count", squirrel.Expr("rating_count + ?", countDelta)).
+		Set("rating_avg", squirrel.Expr(
+			"COALESCE((rating_sum + ?)::NUMERIC / NULLIF(rating_count + ?, 0), 0)", sumDelta, countDelta,
+		)).
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	if _, err = client.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteByAuthorId - soft-deletes the author's books and returns their ids.
+func (r *BookRepo) DeleteByAuthorId(ctx context.Context, authorId int64) ([]int64, error) {
+	op := "BookRepo - DeleteByAuthorId"
+
+	sql, args, err := r.Builder.
+		Update("books").
+		Set("deleted_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"author_id": authorId}).
+		Where("deleted_at IS NULL").
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	rows, err := client.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s - client.Query: %w", op, err)
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return nil, fmt.Errorf("%s - pgx.CollectRows: %w", op, err)
+	}
+
+	return ids, nil
+}
+
+func (r *BookRepo) UpdateCover(ctx context.Context, id int64, hash, contentType *string) error {
+	op := "BookRepo - UpdateCover"
+
+	sql, args, err := r.Builder.
+		Update("books").
+		Set("cover_hash", hash).
+		Set("cover_type", contentType).
+		Set("updated_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"id": id}).
+		Where("deleted_at IS NULL").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	if _, err = client.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *BookRepo) ReassignAuthor(ctx context.Context, fromAuthorId, toAuthorId int64) error {
+	op := "BookRepo - ReassignAuthor"
+
+	sql, args, err := r.Builder.
+		Update("books").
+		Set("author_id", toAuthorId).
+		Set("updated_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"author_id": fromAuthorId}).
+		Where("deleted_at IS NULL").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	if _, err = client.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *BookRepo) GetDeleted(ctx context.Context) ([]*entity.Book, error) {
+	op := "BookRepo - GetDeleted"
+
+	sql, args, err := r.Builder.
+		Select(
+			"b.id", "b.created_at", "b.updated_at", "b.deleted_at",
+			"b.title", "b.author_id", "b.created_by",
+			"b.rating_avg", "b.rating_count", "b.cover_hash", "b.cover_type",
+			"a.name", "a.gender",
+		).
+		From("books b").
+		LeftJoin("authors a ON b.author_id = a.id").
+		Where("b.deleted_at IS NOT NULL").
+		OrderBy("b.deleted_at DESC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	rows, err := client.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s - client.Query: %w", op, err)
+	}
+	defer rows.Close()
+
+	items := make([]*entity.Book, 0, 64)
+
+	for rows.Next() {
+		e := entity.Book{}
+
+		if err = rows.Scan(
+			&e.ID, &e.CreatedAt, &e.UpdatedAt, &e.DeletedAt, &e.Title, &e.AuthorId, &e.CreatedBy,
+			&e.RatingAvg, &e.RatingCount, &e.CoverHash, &e.CoverType,
 			&e.Author.Name, &e.Author.Gender,
 		); err != nil {
 			return nil, fmt.Errorf("%s - row.Scan: %w", op, err)
@@ -170,5 +514,126 @@ func (r *BookRepo) GetAll(ctx context.Context) ([]*entity.Book, error) {
 		items = append(items, &e)
 	}
 
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s - rows.Err: %w", op, err)
+	}
+
 	return items, nil
 }
+
+func (r *BookRepo) GetDeletedById(ctx context.Context, id int64) (*entity.Book, error) {
+	op := "BookRepo - GetDeletedById"
+
+	sql, args, err := r.Builder.
+		Select(
+			"id", "created_at", "updated_at", "deleted_at",
+			"title", "author_id", "created_by",
+			"rating_avg", "rating_count", "cover_hash", "cover_type",
+		).
+		From("books").
+		Where("deleted_at IS NOT NULL").
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	row := client.QueryRow(ctx, sql, args...)
+
+	var e entity.Book
+	if err = row.Scan(
+		&e.ID, &e.CreatedAt, &e.UpdatedAt, &e.DeletedAt, &e.Title, &e.AuthorId, &e.CreatedBy,
+		&e.RatingAvg, &e.RatingCount, &e.CoverHash, &e.CoverType,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrNotInTrash
+		}
+
+		return nil, fmt.Errorf("%s - row.Scan: %w", op, err)
+	}
+
+	return &e, nil
+}
+
+func (r *BookRepo) Restore(ctx context.Context, id int64) error {
+	op := "BookRepo - Restore"
+
+	sql, args, err := r.Builder.
+		Update("books").
+		Set("deleted_at", nil).
+		Where(squirrel.Eq{"id": id}).
+		Where("deleted_at IS NOT NULL").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	tag, err := client.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.ErrNotInTrash
+	}
+	return nil
+}
+
+// RestoreByAuthorId - restores the books that were soft-deleted together with the author and returns their ids.
+func (r *BookRepo) RestoreByAuthorId(ctx context.Context, authorId int64, deletedAt time.Time) ([]int64, error) {
+	op := "BookRepo - RestoreByAuthorId"
+
+	sql, args, err := r.Builder.
+		Update("books").
+		Set("deleted_at", nil).
+		Where(squirrel.Eq{"author_id": authorId}).
+		Where(squirrel.Eq{"deleted_at": deletedAt}).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	rows, err := client.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s - client.Query: %w", op, err)
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return nil, fmt.Errorf("%s - pgx.CollectRows: %w", op, err)
+	}
+
+	return ids, nil
+}
+
+// PurgeDeletedBefore - hard-deletes books soft-deleted before the given time and returns their ids,
+// a soft-deleted book keeps its cover until then.
+func (r *BookRepo) PurgeDeletedBefore(ctx context.Context, before time.Time) ([]int64, error) {
+	op := "BookRepo - PurgeDeletedBefore"
+
+	sql, args, err := r.Builder.
+		Delete("books").
+		Where(squirrel.Lt{"deleted_at": before}).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	rows, err := client.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s - client.Query: %w", op, err)
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return nil, fmt.Errorf("%s - pgx.CollectRows: %w", op, err)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,179 @@
+package persistent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Alice00021/test_common/pkg/postgres"
+	"test_go/internal/entity"
+)
+
+type CommandCatalogRepo struct {
+	*postgres.Postgres
+}
+
+func NewCommandCatalogRepo(pg *postgres.Postgres) *CommandCatalogRepo {
+	return &CommandCatalogRepo{pg}
+}
+
+func (r *CommandCatalogRepo) Create(ctx context.Context, e *entity.CommandCatalogVersion) error {
+	op := "CommandCatalogRepo - Create"
+
+	commands, err := json.Marshal(e.Commands)
+	if err != nil {
+		return fmt.Errorf("%s - json.Marshal: %w", op, err)
+	}
+
+	sql, args, err := r.Builder.
+		Insert("command_catalog_versions").
+		Columns("checksum, author_id, commands").
+		Values(e.Checksum, e.AuthorID, string(commands)).
+		Suffix(`RETURNING id, created_at`).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	if err = client.QueryRow(ctx, sql, args...).Scan(&e.ID, &e.CreatedAt); err != nil {
+		return fmt.Errorf("%s - client.QueryRow: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *CommandCatalogRepo) selectVersions() squirrel.SelectBuilder {
+	return r.Builder.
+		Select("id", "created_at", "checksum", "author_id", "is_active", "commands").
+		From("command_catalog_versions")
+}
+
+func scanCommandCatalogVersion(row pgx.Row) (*entity.CommandCatalogVersion, error) {
+	var (
+		e        entity.CommandCatalogVersion
+		commands []byte
+	)
+
+	if err := row.Scan(&e.ID, &e.CreatedAt, &e.Checksum, &e.AuthorID, &e.Active, &commands); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(commands, &e.Commands); err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+func (r *CommandCatalogRepo) getOne(ctx context.Context, op string, builder squirrel.SelectBuilder) (*entity.CommandCatalogVersion, error) {
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+
+	e, err := scanCommandCatalogVersion(client.QueryRow(ctx, sql, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrCommandCatalogVersionNotFound
+		}
+
+		return nil, fmt.Errorf("%s - row.Scan: %w", op, err)
+	}
+
+	return e, nil
+}
+
+func (r *CommandCatalogRepo) GetById(ctx context.Context, id int64) (*entity.CommandCatalogVersion, error) {
+	return r.getOne(ctx, "CommandCatalogRepo - GetById", r.selectVersions().Where(squirrel.Eq{"id": id}))
+}
+
+// GetActive - the version the commands were last synced to, ErrCommandCatalogVersionNotFound
+// until the first catalog is imported.
+func (r *CommandCatalogRepo) GetActive(ctx context.Context) (*entity.CommandCatalogVersion, error) {
+	return r.getOne(ctx, "CommandCatalogRepo - GetActive", r.selectVersions().Where("is_active"))
+}
+
+// GetAll - the versions without their snapshots, newest first.
+func (r *CommandCatalogRepo) GetAll(ctx context.Context) ([]*entity.CommandCatalogVersion, error) {
+	op := "CommandCatalogRepo - GetAll"
+
+	sql, args, err := r.Builder.
+		Select("id", "created_at", "checksum", "author_id", "is_active").
+		From("command_catalog_versions").
+		OrderBy("id DESC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	rows, err := client.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s - client.Query: %w", op, err)
+	}
+	defer rows.Close()
+
+	items := make([]*entity.CommandCatalogVersion, 0, 16)
+
+	for rows.Next() {
+		var e entity.CommandCatalogVersion
+		if err = rows.Scan(&e.ID, &e.CreatedAt, &e.Checksum, &e.AuthorID, &e.Active); err != nil {
+			return nil, fmt.Errorf("%s - rows.Scan: %w", op, err)
+		}
+
+		items = append(items, &e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s - rows.Err: %w", op, err)
+	}
+
+	return items, nil
+}
+
+// Activate - moves the active pointer to the version.
+func (r *CommandCatalogRepo) Activate(ctx context.Context, id int64) error {
+	op := "CommandCatalogRepo - Activate"
+
+	client := r.GetClient(ctx)
+
+	sql, args, err := r.Builder.
+		Update("command_catalog_versions").
+		Set("is_active", false).
+		Where("is_active").
+		Where(squirrel.NotEq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	if _, err = client.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	sql, args, err = r.Builder.
+		Update("command_catalog_versions").
+		Set("is_active", true).
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	tag, err := client.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrCommandCatalogVersionNotFound
+	}
+
+	return nil
+}
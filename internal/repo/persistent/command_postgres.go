@@ -45,21 +45,31 @@ func (r *CommandRepo) Create(ctx context.Context, e *entity.Command) (*entity.Co
 		return nil, fmt.Errorf("%s - client.QueryRow: %w", op, err)
 	}
 
+	if err := r.saveNames(ctx, id, e.Names); err != nil {
+		return nil, fmt.Errorf("%s - %w", op, err)
+	}
+
 	return r.GetById(ctx, id)
 }
 
 func (r *CommandRepo) GetById(ctx context.Context, id int64) (*entity.Command, error) {
-	op := "CommandRepo - GetById"
+	return r.getOne(ctx, "CommandRepo - GetById", squirrel.Eq{"id": id})
+}
 
+func (r *CommandRepo) GetBySystemName(ctx context.Context, systemName string) (*entity.Command, error) {
+	return r.getOne(ctx, "CommandRepo - GetBySystemName", squirrel.Eq{"system_name": systemName})
+}
+
+func (r *CommandRepo) getOne(ctx context.Context, op string, pred squirrel.Sqlizer) (*entity.Command, error) {
 	sql, args, err := r.Builder.
 		Select(
 			"id", "created_at", "updated_at", "deleted_at", "name",
 			"system_name", "reagent", "average_time", "volume_waste", "volume_drive_fluid",
-			"volume_container", "default_address",
+			"volume_container", "default_address", "is_disabled",
 		).
 		From("commands").
 		Where("deleted_at IS NULL").
-		Where(squirrel.Eq{"id": id}).
+		Where(pred).
 		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
@@ -79,6 +89,13 @@ func (r *CommandRepo) GetById(ctx context.Context, id int64) (*entity.Command, e
 
 		return nil, fmt.Errorf("%s - pgx.CollectOneRow: %w", op, err)
 	}
+
+	names, err := r.getNames(ctx, []int64{command.ID})
+	if err != nil {
+		return nil, fmt.Errorf("%s - %w", op, err)
+	}
+	command.Names = names[command.ID]
+
 	return command, nil
 }
 
@@ -94,6 +111,7 @@ func (r *CommandRepo) Update(ctx context.Context, inp *entity.Command) error {
 		Set("volume_drive_fluid", inp.VolumeDriveFluid).
 		Set("volume_container", inp.VolumeContainer).
 		Set("default_address", inp.DefaultAddress).
+		Set("updated_at", squirrel.Expr("NOW()")).
 		Where(squirrel.Eq{"id": inp.ID})
 
 	sql, args, err := sqlBuilder.ToSql()
@@ -106,6 +124,39 @@ func (r *CommandRepo) Update(ctx context.Context, inp *entity.Command) error {
 		return fmt.Errorf("%s - client.Exec: %w", op, err)
 	}
 
+	if inp.Names != nil {
+		if err := r.saveNames(ctx, inp.ID, inp.Names); err != nil {
+			return fmt.Errorf("%s - %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+// Disable - hides the command from new operations, operations that use it are kept as is.
+func (r *CommandRepo) Disable(ctx context.Context, systemName string, disabled bool) error {
+	op := "CommandRepo - Disable"
+
+	sql, args, err := r.Builder.
+		Update("commands").
+		Set("is_disabled", disabled).
+		Set("updated_at", squirrel.Expr("NOW()")).
+		Where("deleted_at IS NULL").
+		Where(squirrel.Eq{"system_name": systemName}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	tag, err := client.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrCommandNotFound
+	}
+
 	return nil
 }
 
@@ -116,7 +167,7 @@ func (r *CommandRepo) GetBySystemNames(ctx context.Context) (map[string]entity.C
 		Select(
 			"id", "created_at", "updated_at", "deleted_at", "name",
 			"system_name", "reagent", "average_time", "volume_waste", "volume_drive_fluid",
-			"volume_container", "default_address",
+			"volume_container", "default_address", "is_disabled",
 		).
 		From("commands").
 		Where("deleted_at IS NULL").
@@ -134,6 +185,7 @@ func (r *CommandRepo) GetBySystemNames(ctx context.Context) (map[string]entity.C
 	defer rows.Close()
 
 	items := make(map[string]entity.Command)
+	ids := make([]int64, 0, 64)
 
 	for rows.Next() {
 		var e entity.Command
@@ -141,18 +193,112 @@ func (r *CommandRepo) GetBySystemNames(ctx context.Context) (map[string]entity.C
 		err = rows.Scan(
 			&e.ID, &e.CreatedAt, &e.UpdatedAt, &e.DeletedAt, &e.Name, &e.SystemName,
 			&e.Reagent, &e.AverageTime, &e.VolumeWaste, &e.VolumeDriveFluid,
-			&e.VolumeContainer, &e.DefaultAddress,
+			&e.VolumeContainer, &e.DefaultAddress, &e.Disabled,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("%s - rows.Scan: %w", op, err)
 		}
 
 		items[e.SystemName] = e
+		ids = append(ids, e.ID)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("%s - rows.Err: %w", op, err)
 	}
 
+	names, err := r.getNames(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s - %w", op, err)
+	}
+	for systemName, e := range items {
+		e.Names = names[e.ID]
+		items[systemName] = e
+	}
+
 	return items, nil
 }
+
+// getNames - the translations of the commands by command id.
+func (r *CommandRepo) getNames(ctx context.Context, ids []int64) (map[int64]entity.LocalizedNames, error) {
+	items := make(map[int64]entity.LocalizedNames, len(ids))
+	if len(ids) == 0 {
+		return items, nil
+	}
+
+	sql, args, err := r.Builder.
+		Select("command_id", "locale", "name").
+		From("command_translations").
+		Where(squirrel.Eq{"command_id": ids}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("r.Builder: %w", err)
+	}
+
+	client := r.GetClient(ctx)
+	rows, err := client.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("client.Query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id           int64
+			locale, name string
+		)
+		if err = rows.Scan(&id, &locale, &name); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		if items[id] == nil {
+			items[id] = make(entity.LocalizedNames)
+		}
+		items[id][locale] = name
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return items, nil
+}
+
+// saveNames - replaces the translations of the command.
+func (r *CommandRepo) saveNames(ctx context.Context, id int64, names entity.LocalizedNames) error {
+	client := r.GetClient(ctx)
+
+	sql, args, err := r.Builder.
+		Delete("command_translations").
+		Where(squirrel.Eq{"command_id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("r.Builder: %w", err)
+	}
+
+	if _, err = client.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("client.Exec: %w", err)
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	builder := r.Builder.
+		Insert("command_translations").
+		Columns("command_id, locale, name")
+	for locale, name := range names {
+		builder = builder.Values(id, locale, name)
+	}
+
+	sql, args, err = builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("r.Builder: %w", err)
+	}
+
+	if _, err = client.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("client.Exec: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,237 @@
+package persistent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Alice00021/test_common/pkg/postgres"
+	"test_go/internal/entity"
+)
+
+type DeckProfileRepo struct {
+	*postgres.Postgres
+}
+
+func NewDeckProfileRepo(pg *postgres.Postgres) *DeckProfileRepo {
+	return &DeckProfileRepo{pg}
+}
+
+func (r *DeckProfileRepo) Create(ctx context.Context, e *entity.DeckProfile) error {
+	op := "DeckProfileRepo - Create"
+
+	positions, err := json.Marshal(e.Positions)
+	if err != nil {
+		return fmt.Errorf("%s - json.Marshal: %w", op, err)
+	}
+
+	sql, args, err := r.Builder.
+		Insert("deck_profiles").
+		Columns("name, positions").
+		Values(e.Name, string(positions)).
+		Suffix(`RETURNING id, created_at, updated_at`).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	if err = client.QueryRow(ctx, sql, args...).Scan(&e.ID, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		return fmt.Errorf("%s - client.QueryRow: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *DeckProfileRepo) selectProfiles() squirrel.SelectBuilder {
+	return r.Builder.
+		Select("id", "created_at", "updated_at", "name", "is_default", "positions").
+		From("deck_profiles")
+}
+
+func scanDeckProfile(row pgx.Row) (*entity.DeckProfile, error) {
+	var (
+		e         entity.DeckProfile
+		positions []byte
+	)
+
+	if err := row.Scan(&e.ID, &e.CreatedAt, &e.UpdatedAt, &e.Name, &e.Default, &positions); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(positions, &e.Positions); err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+func (r *DeckProfileRepo) getOne(ctx context.Context, op string, builder squirrel.SelectBuilder) (*entity.DeckProfile, error) {
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	e, err := scanDeckProfile(client.QueryRow(ctx, sql, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrDeckProfileNotFound
+		}
+
+		return nil, fmt.Errorf("%s - row.Scan: %w", op, err)
+	}
+
+	return e, nil
+}
+
+func (r *DeckProfileRepo) GetById(ctx context.Context, id int64) (*entity.DeckProfile, error) {
+	return r.getOne(ctx, "DeckProfileRepo - GetById", r.selectProfiles().Where(squirrel.Eq{"id": id}))
+}
+
+// GetByName - case-insensitive lookup.
+func (r *DeckProfileRepo) GetByName(ctx context.Context, name string) (*entity.DeckProfile, error) {
+	return r.getOne(ctx, "DeckProfileRepo - GetByName", r.selectProfiles().Where("LOWER(name) = LOWER(?)", name))
+}
+
+// GetDefault - ErrDeckProfileNotFound when no profile is marked as the default.
+func (r *DeckProfileRepo) GetDefault(ctx context.Context) (*entity.DeckProfile, error) {
+	return r.getOne(ctx, "DeckProfileRepo - GetDefault", r.selectProfiles().Where("is_default"))
+}
+
+func (r *DeckProfileRepo) GetAll(ctx context.Context) ([]*entity.DeckProfile, error) {
+	op := "DeckProfileRepo - GetAll"
+
+	sql, args, err := r.selectProfiles().OrderBy("id").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	rows, err := client.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s - client.Query: %w", op, err)
+	}
+	defer rows.Close()
+
+	items := make([]*entity.DeckProfile, 0, 4)
+
+	for rows.Next() {
+		e, err := scanDeckProfile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s - rows.Scan: %w", op, err)
+		}
+
+		items = append(items, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s - rows.Err: %w", op, err)
+	}
+
+	return items, nil
+}
+
+func (r *DeckProfileRepo) Update(ctx context.Context, e *entity.DeckProfile) error {
+	op := "DeckProfileRepo - Update"
+
+	positions, err := json.Marshal(e.Positions)
+	if err != nil {
+		return fmt.Errorf("%s - json.Marshal: %w", op, err)
+	}
+
+	sql, args, err := r.Builder.
+		Update("deck_profiles").
+		Set("name", e.Name).
+		Set("positions", string(positions)).
+		Set("updated_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"id": e.ID}).
+		Suffix(`RETURNING updated_at`).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	if err = client.QueryRow(ctx, sql, args...).Scan(&e.UpdatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.ErrDeckProfileNotFound
+		}
+
+		return fmt.Errorf("%s - client.QueryRow: %w", op, err)
+	}
+
+	return nil
+}
+
+// SetDefault - moves the default mark to the profile.
+func (r *DeckProfileRepo) SetDefault(ctx context.Context, id int64) error {
+	op := "DeckProfileRepo - SetDefault"
+
+	client := r.GetClient(ctx)
+
+	sql, args, err := r.Builder.
+		Update("deck_profiles").
+		Set("is_default", false).
+		Where("is_default").
+		Where(squirrel.NotEq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	if _, err = client.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	sql, args, err = r.Builder.
+		Update("deck_profiles").
+		Set("is_default", true).
+		Set("updated_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	tag, err := client.Exec(ctx, sql, args...)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return entity.ErrDeckProfileInUse
+		}
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrDeckProfileNotFound
+	}
+
+	return nil
+}
+
+// DeleteById - the foreign keys refuse to delete a profile postgres operations or instruments are on.
+func (r *DeckProfileRepo) DeleteById(ctx context.Context, id int64) error {
+	op := "DeckProfileRepo - DeleteById"
+
+	sql, args, err := r.Builder.
+		Delete("deck_profiles").
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	tag, err := client.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrDeckProfileNotFound
+	}
+
+	return nil
+}
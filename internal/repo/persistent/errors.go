@@ -0,0 +1,26 @@
+package persistent
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	// uniqueViolation - the code postgres reports a broken unique index with.
+	uniqueViolation = "23505"
+	// foreignKeyViolation - the code postgres reports a row still referred to by a restricting foreign key with.
+	foreignKeyViolation = "23503"
+)
+
+// isUniqueViolation - the insert or update hit a unique index, e.g. a concurrent request stored the same row first.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
+// isForeignKeyViolation - the row is still referred to, e.g. a delete hit an ON DELETE RESTRICT foreign key.
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation
+}
@@ -0,0 +1,125 @@
+package persistent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Alice00021/test_common/pkg/postgres"
+	"test_go/internal/entity"
+)
+
+type InstrumentRepo struct {
+	*postgres.Postgres
+}
+
+func NewInstrumentRepo(pg *postgres.Postgres) *InstrumentRepo {
+	return &InstrumentRepo{pg}
+}
+
+func (r *InstrumentRepo) Create(ctx context.Context, e *entity.Instrument) error {
+	op := "InstrumentRepo - Create"
+
+	sql, args, err := r.Builder.
+		Insert("instruments").
+		Columns("name, deck_profile_id").
+		Values(e.Name, e.DeckProfileID).
+		Suffix(`RETURNING id, created_at, updated_at`).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	if err = client.QueryRow(ctx, sql, args...).Scan(&e.ID, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		return fmt.Errorf("%s - client.QueryRow: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *InstrumentRepo) selectInstruments() squirrel.SelectBuilder {
+	return r.Builder.
+		Select("id", "created_at", "updated_at", "name", "deck_profile_id").
+		From("instruments")
+}
+
+func scanInstrument(row pgx.Row) (*entity.Instrument, error) {
+	var e entity.Instrument
+	if err := row.Scan(&e.ID, &e.CreatedAt, &e.UpdatedAt, &e.Name, &e.DeckProfileID); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *InstrumentRepo) getOne(ctx context.Context, op string, builder squirrel.SelectBuilder) (*entity.Instrument, error) {
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	e, err := scanInstrument(client.QueryRow(ctx, sql, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrInstrumentNotFound
+		}
+
+		return nil, fmt.Errorf("%s - row.Scan: %w", op, err)
+	}
+
+	return e, nil
+}
+
+func (r *InstrumentRepo) GetById(ctx context.Context, id int64) (*entity.Instrument, error) {
+	return r.getOne(ctx, "InstrumentRepo - GetById", r.selectInstruments().Where(squirrel.Eq{"id": id}))
+}
+
+// GetByIdForUpdate - locks the instrument until the transaction ends, the changes
+// of its queue are made under this lock.
+func (r *InstrumentRepo) GetByIdForUpdate(ctx context.Context, id int64) (*entity.Instrument, error) {
+	return r.getOne(ctx, "InstrumentRepo - GetByIdForUpdate", r.selectInstruments().
+		Where(squirrel.Eq{"id": id}).
+		Suffix("FOR UPDATE"))
+}
+
+// GetByName - case-insensitive lookup.
+func (r *InstrumentRepo) GetByName(ctx context.Context, name string) (*entity.Instrument, error) {
+	return r.getOne(ctx, "InstrumentRepo - GetByName", r.selectInstruments().Where("LOWER(name) = LOWER(?)", name))
+}
+
+func (r *InstrumentRepo) GetAll(ctx context.Context) ([]*entity.Instrument, error) {
+	op := "InstrumentRepo - GetAll"
+
+	sql, args, err := r.selectInstruments().OrderBy("id").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	rows, err := client.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s - client.Query: %w", op, err)
+	}
+	defer rows.Close()
+
+	items := make([]*entity.Instrument, 0, 4)
+
+	for rows.Next() {
+		e, err := scanInstrument(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s - rows.Scan: %w", op, err)
+		}
+
+		items = append(items, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s - rows.Err: %w", op, err)
+	}
+
+	return items, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/Alice00021/test_common/pkg/postgres"
 	"github.com/Masterminds/squirrel"
+	"strconv"
 	"test_go/internal/entity"
 )
 
@@ -22,9 +23,9 @@ func (r *OperationRepo) Create(ctx context.Context, e *entity.Operation) (*entit
 	sql, args, err := r.Builder.
 		Insert("operations").
 		Columns(
-			"name, description, average_time").
+			"name, description, average_time, catalog_version_id, deck_profile_id").
 		Values(
-			e.Name, e.Description, e.AverageTime).
+			e.Name, e.Description, e.AverageTime, e.CatalogVersionID, e.DeckProfileID).
 		Suffix(`RETURNING id`).
 		ToSql()
 	if err != nil {
@@ -42,20 +43,20 @@ func (r *OperationRepo) Create(ctx context.Context, e *entity.Operation) (*entit
 	return r.GetById(ctx, id)
 }
 
-func (r *OperationRepo) GetAll(ctx context.Context) (map[int64]*entity.Operation, error) {
+func (r *OperationRepo) GetAll(ctx context.Context) ([]*entity.Operation, error) {
 	op := "OperationRepo - GetAll"
 
 	sqlBuilder := r.Builder.
 		Select(
-			"op.id", "op.created_at", "op.updated_at", "op.deleted_at",
-			"op.name", "op.description", "op.average_time",
-			"opc.command_id", "c.name", "c.system_name", "c.default_address", "opc.address",
+			"op.id", "op.created_at", "op.updated_at",
+			"op.name", "op.description", "op.average_time", "op.catalog_version_id", "op.deck_profile_id",
+			"opc.id", "opc.command_id", "c.name", "c.system_name", "c.default_address", "opc.address",
 		).
 		From("operations op").
 		LeftJoin("operation_commands opc ON opc.operation_id = op.id").
 		LeftJoin("commands c ON opc.command_id = c.id").
 		Where("op.deleted_at IS NULL").
-		OrderBy("op.id DESC")
+		OrderBy("op.id DESC", "opc.id")
 
 	sql, args, err := sqlBuilder.ToSql()
 	if err != nil {
@@ -69,59 +70,43 @@ func (r *OperationRepo) GetAll(ctx context.Context) (map[int64]*entity.Operation
 	}
 	defer rows.Close()
 
-	items := make(map[int64]*entity.Operation)
+	var items []*entity.Operation
+	byId := make(map[int64]*entity.Operation)
 
 	for rows.Next() {
 		var (
-			e                  entity.Operation
-			commandId          *int64
-			commandName        *string
-			commandSystemName  *string
-			commandDefaultAddr *string
-			commandAddress     *string
+			id        int64
+			e         entity.Operation
+			opCommand operationCommandRow
 		)
 
 		if err = rows.Scan(
-			&e.ID, &e.CreatedAt, &e.UpdatedAt, &e.DeletedAt,
-			&e.Name, &e.Description, &e.AverageTime,
-			&commandId, &commandName, &commandSystemName, &commandDefaultAddr, &commandAddress,
+			&id, &e.CreatedAt, &e.UpdatedAt,
+			&e.Name, &e.Description, &e.AverageTime, &e.CatalogVersionID, &e.DeckProfileID,
+			&opCommand.id, &opCommand.commandId, &opCommand.name, &opCommand.systemName,
+			&opCommand.defaultAddress, &opCommand.address,
 		); err != nil {
 			return nil, fmt.Errorf("%s - row.Scan: %w", op, err)
 		}
 
-		if _, ok := items[e.ID]; !ok {
-			items[e.ID] = &entity.Operation{
-				Entity: entity.Entity{
-					ID:        e.ID,
-					CreatedAt: e.CreatedAt,
-					UpdatedAt: e.UpdatedAt,
-					DeletedAt: e.DeletedAt,
-				},
-				Name:        e.Name,
-				Description: e.Description,
-				AverageTime: e.AverageTime,
-				Commands:    make([]*entity.OperationCommand, 0),
-			}
+		operation, ok := byId[id]
+		if !ok {
+			e.ID = strconv.FormatInt(id, 10)
+			e.Commands = make([]*entity.OperationCommand, 0)
+			operation = &e
+			byId[id] = operation
+			items = append(items, operation)
 		}
 
-		operation := items[e.ID]
-
-		if commandId != nil {
-			operation.Commands = append(operation.Commands, &entity.OperationCommand{
-				ID: *commandId,
-				Command: entity.Command{
-					Entity: entity.Entity{
-						ID: *commandId,
-					},
-					Name:           *commandName,
-					SystemName:     *commandSystemName,
-					DefaultAddress: entity.Address(*commandDefaultAddr),
-				},
-				Address: entity.Address(*commandAddress),
-			})
+		if c := opCommand.toEntity(id); c != nil {
+			operation.Commands = append(operation.Commands, c)
 		}
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s - rows.Err: %w", op, err)
+	}
+
 	return items, nil
 }
 
@@ -130,15 +115,16 @@ func (r *OperationRepo) GetById(ctx context.Context, id int64) (*entity.Operatio
 
 	sql, args, err := r.Builder.
 		Select(
-			"op.id", "op.created_at", "op.updated_at", "op.deleted_at",
-			"op.name", "op.description", "op.average_time",
-			"c.id", "c.system_name", "c.default_address",
+			"op.created_at", "op.updated_at",
+			"op.name", "op.description", "op.average_time", "op.catalog_version_id", "op.deck_profile_id",
+			"opc.id", "opc.command_id", "c.name", "c.system_name", "c.default_address", "opc.address",
 		).
 		From("operations op").
 		LeftJoin("operation_commands opc ON opc.operation_id = op.id").
 		LeftJoin("commands c ON opc.command_id = c.id").
 		Where("op.deleted_at IS NULL").
 		Where(squirrel.Eq{"op.id": id}).
+		OrderBy("opc.id").
 		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
@@ -151,42 +137,26 @@ func (r *OperationRepo) GetById(ctx context.Context, id int64) (*entity.Operatio
 	}
 	defer rows.Close()
 
-	var e entity.Operation
-	e.Commands = []*entity.OperationCommand{}
+	var (
+		e     = entity.Operation{ID: strconv.FormatInt(id, 10)}
+		found bool
+	)
 
 	for rows.Next() {
-		var (
-			commandId                  *int64
-			systemName, defaultAddress *string
-		)
+		var opCommand operationCommandRow
 
 		if err := rows.Scan(
-			&e.ID, &e.CreatedAt, &e.UpdatedAt, &e.DeletedAt,
-			&e.Name, &e.Description, &e.AverageTime,
-			&commandId, &systemName, &defaultAddress,
+			&e.CreatedAt, &e.UpdatedAt,
+			&e.Name, &e.Description, &e.AverageTime, &e.CatalogVersionID, &e.DeckProfileID,
+			&opCommand.id, &opCommand.commandId, &opCommand.name, &opCommand.systemName,
+			&opCommand.defaultAddress, &opCommand.address,
 		); err != nil {
 			return nil, fmt.Errorf("%s - rows.Scan: %w", op, err)
 		}
+		found = true
 
-		if commandId != nil {
-			addr := entity.Address("")
-			if defaultAddress != nil {
-				addr = entity.Address(*defaultAddress)
-			}
-
-			sysName := ""
-			if systemName != nil {
-				sysName = *systemName
-			}
-
-			e.Commands = append(e.Commands, &entity.OperationCommand{
-				Command: entity.Command{
-					Entity:         entity.Entity{ID: *commandId},
-					SystemName:     sysName,
-					DefaultAddress: addr,
-				},
-				Address: addr,
-			})
+		if c := opCommand.toEntity(id); c != nil {
+			e.Commands = append(e.Commands, c)
 		}
 	}
 
@@ -194,22 +164,67 @@ func (r *OperationRepo) GetById(ctx context.Context, id int64) (*entity.Operatio
 		return nil, fmt.Errorf("%s - rows.Err: %w", op, err)
 	}
 
-	if len(e.Commands) == 0 {
-		e.Commands = nil
+	if !found {
+		return nil, entity.ErrOperationNotFound
 	}
 
 	return &e, nil
 }
 
-func (r *OperationRepo) Update(ctx context.Context, e *entity.Operation) error {
-	op := "OperationRepo - Update"
+// operationCommandRow - the left joined command columns, all of them are null
+// for an operation without commands.
+type operationCommandRow struct {
+	id, commandId                             *int64
+	name, systemName, defaultAddress, address *string
+}
+
+func (row operationCommandRow) toEntity(operationId int64) *entity.OperationCommand {
+	if row.id == nil {
+		return nil
+	}
+
+	deref := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+
+	c := &entity.OperationCommand{
+		ID:          *row.id,
+		OperationID: operationId,
+		Command: entity.Command{
+			Name:           deref(row.name),
+			SystemName:     deref(row.systemName),
+			DefaultAddress: entity.Address(deref(row.defaultAddress)),
+		},
+		Address: entity.Address(deref(row.address)),
+	}
+	if row.commandId != nil {
+		c.Command.ID = *row.commandId
+	}
+	return c
+}
+
+// Patch - updates only the columns carried by the patch,
+// the average time follows the commands and is taken from e, as is the deck.
+func (r *OperationRepo) Patch(ctx context.Context, id int64, e *entity.Operation, inp entity.UpdateOperationInput) error {
+	op := "OperationRepo - Patch"
 
 	sqlBuilder := r.Builder.
 		Update("operations").
-		Set("name", e.Name).
-		Set("description", e.Description).
-		Set("average_time", e.AverageTime).
-		Where(squirrel.Eq{"id": e.ID})
+		Set("updated_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"id": id})
+	sqlBuilder = setPatch(sqlBuilder, "name", inp.Name)
+	sqlBuilder = setPatch(sqlBuilder, "description", inp.Description)
+	if inp.Commands.Present {
+		sqlBuilder = sqlBuilder.
+			Set("average_time", e.AverageTime).
+			Set("catalog_version_id", e.CatalogVersionID)
+	}
+	if inp.DeckProfileID.Present {
+		sqlBuilder = sqlBuilder.Set("deck_profile_id", e.DeckProfileID)
+	}
 
 	sql, args, err := sqlBuilder.ToSql()
 	if err != nil {
@@ -0,0 +1,230 @@
+package persistent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Alice00021/test_common/pkg/postgres"
+	"test_go/internal/entity"
+)
+
+type OperationRunRepo struct {
+	*postgres.Postgres
+}
+
+func NewOperationRunRepo(pg *postgres.Postgres) *OperationRunRepo {
+	return &OperationRunRepo{pg}
+}
+
+func (r *OperationRunRepo) Create(ctx context.Context, e *entity.OperationRun) error {
+	op := "OperationRunRepo - Create"
+
+	steps, err := json.Marshal(e.Steps)
+	if err != nil {
+		return fmt.Errorf("%s - json.Marshal: %w", op, err)
+	}
+
+	sql, args, err := r.Builder.
+		Insert("operation_runs").
+		Columns("operation_id, operation_name, instrument_id, priority, queue_position, average_time, "+
+			"state, current_step, started_by, steps").
+		Values(e.OperationID, e.OperationName, e.InstrumentID, e.Priority, e.QueuePosition, e.AverageTime,
+			e.State, e.CurrentStep, e.StartedBy, string(steps)).
+		Suffix(`RETURNING id, created_at, updated_at`).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	if err = client.QueryRow(ctx, sql, args...).Scan(&e.ID, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		return fmt.Errorf("%s - client.QueryRow: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *OperationRunRepo) selectRuns() squirrel.SelectBuilder {
+	return r.Builder.
+		Select(
+			"id", "created_at", "updated_at", "operation_id", "operation_name", "instrument_id", "priority",
+			"queue_position", "average_time", "state", "current_step", "started_by", "started_at", "finished_at",
+			"error", "steps",
+		).
+		From("operation_runs")
+}
+
+func scanOperationRun(row pgx.Row) (*entity.OperationRun, error) {
+	var (
+		e     entity.OperationRun
+		steps []byte
+	)
+
+	if err := row.Scan(
+		&e.ID, &e.CreatedAt, &e.UpdatedAt, &e.OperationID, &e.OperationName, &e.InstrumentID, &e.Priority,
+		&e.QueuePosition, &e.AverageTime, &e.State, &e.CurrentStep, &e.StartedBy, &e.StartedAt, &e.FinishedAt,
+		&e.Error, &steps,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(steps, &e.Steps); err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+func (r *OperationRunRepo) getOne(ctx context.Context, op string, builder squirrel.SelectBuilder) (*entity.OperationRun, error) {
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	e, err := scanOperationRun(client.QueryRow(ctx, sql, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrOperationRunNotFound
+		}
+
+		return nil, fmt.Errorf("%s - row.Scan: %w", op, err)
+	}
+
+	return e, nil
+}
+
+func (r *OperationRunRepo) GetById(ctx context.Context, id int64) (*entity.OperationRun, error) {
+	return r.getOne(ctx, "OperationRunRepo - GetById", r.selectRuns().Where(squirrel.Eq{"id": id}))
+}
+
+func (r *OperationRunRepo) selectQueued(instrumentID int64) squirrel.SelectBuilder {
+	return r.selectRuns().
+		Where(squirrel.Eq{"instrument_id": instrumentID, "state": entity.OperationRunQueued}).
+		OrderBy("queue_position", "id")
+}
+
+// GetNextQueued - the first run in the queue of the instrument, ErrOperationRunNotFound when the queue is empty.
+func (r *OperationRunRepo) GetNextQueued(ctx context.Context, instrumentID int64) (*entity.OperationRun, error) {
+	return r.getOne(ctx, "OperationRunRepo - GetNextQueued", r.selectQueued(instrumentID).Limit(1))
+}
+
+// GetQueued - the queue of the instrument in order.
+func (r *OperationRunRepo) GetQueued(ctx context.Context, instrumentID int64) ([]*entity.OperationRun, error) {
+	return r.getMany(ctx, "OperationRunRepo - GetQueued", r.selectQueued(instrumentID))
+}
+
+// GetAll - the runs matching the filter, newest first.
+func (r *OperationRunRepo) GetAll(ctx context.Context, filter entity.OperationRunFilter) ([]*entity.OperationRun, error) {
+	sqlBuilder := r.selectRuns().OrderBy("id DESC")
+	if filter.OperationID != "" {
+		sqlBuilder = sqlBuilder.Where(squirrel.Eq{"operation_id": filter.OperationID})
+	}
+	if filter.InstrumentID != 0 {
+		sqlBuilder = sqlBuilder.Where(squirrel.Eq{"instrument_id": filter.InstrumentID})
+	}
+	if filter.State != "" {
+		sqlBuilder = sqlBuilder.Where(squirrel.Eq{"state": filter.State})
+	}
+
+	return r.getMany(ctx, "OperationRunRepo - GetAll", sqlBuilder)
+}
+
+func (r *OperationRunRepo) getMany(ctx context.Context, op string, builder squirrel.SelectBuilder) ([]*entity.OperationRun, error) {
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	rows, err := client.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s - client.Query: %w", op, err)
+	}
+	defer rows.Close()
+
+	items := make([]*entity.OperationRun, 0, 16)
+
+	for rows.Next() {
+		e, err := scanOperationRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s - rows.Scan: %w", op, err)
+		}
+
+		items = append(items, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s - rows.Err: %w", op, err)
+	}
+
+	return items, nil
+}
+
+// UpdateQueuePosition - moves the queued run within the queue of its instrument.
+func (r *OperationRunRepo) UpdateQueuePosition(ctx context.Context, id int64, position int) error {
+	op := "OperationRunRepo - UpdateQueuePosition"
+
+	sql, args, err := r.Builder.
+		Update("operation_runs").
+		Set("queue_position", position).
+		Set("updated_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	tag, err := client.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrOperationRunNotFound
+	}
+
+	return nil
+}
+
+// Update - saves the state of the run and of its steps.
+func (r *OperationRunRepo) Update(ctx context.Context, e *entity.OperationRun) error {
+	op := "OperationRunRepo - Update"
+
+	steps, err := json.Marshal(e.Steps)
+	if err != nil {
+		return fmt.Errorf("%s - json.Marshal: %w", op, err)
+	}
+
+	sql, args, err := r.Builder.
+		Update("operation_runs").
+		Set("state", e.State).
+		Set("current_step", e.CurrentStep).
+		Set("started_at", e.StartedAt).
+		Set("finished_at", e.FinishedAt).
+		Set("error", e.Error).
+		Set("steps", string(steps)).
+		Set("updated_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"id": e.ID}).
+		Suffix(`RETURNING updated_at`).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	if err = client.QueryRow(ctx, sql, args...).Scan(&e.UpdatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.ErrOperationRunNotFound
+		}
+
+		return fmt.Errorf("%s - client.QueryRow: %w", op, err)
+	}
+
+	return nil
+}
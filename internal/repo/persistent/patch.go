@@ -0,0 +1,14 @@
+package persistent
+
+import (
+	"github.com/Masterminds/squirrel"
+	"test_go/internal/entity"
+)
+
+// setPatch - adds the column to the update only when the patch carries the member.
+func setPatch[T any](b squirrel.UpdateBuilder, column string, f entity.PatchField[T]) squirrel.UpdateBuilder {
+	if !f.Present {
+		return b
+	}
+	return b.Set(column, f.Get())
+}
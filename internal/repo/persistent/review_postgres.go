@@ -0,0 +1,269 @@
+package persistent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Alice00021/test_common/pkg/postgres"
+	"test_go/internal/entity"
+)
+
+type ReviewRepo struct {
+	*postgres.Postgres
+}
+
+func NewReviewRepo(pg *postgres.Postgres) *ReviewRepo {
+	return &ReviewRepo{pg}
+}
+
+func (r *ReviewRepo) Create(ctx context.Context, e *entity.Review) (*entity.Review, error) {
+	op := "ReviewRepo - Create"
+
+	sql, args, err := r.Builder.
+		Insert("reviews").
+		Columns("book_id, user_id, rating, text, status").
+		Values(e.BookID, e.UserID, e.Rating, e.Text, e.Status).
+		Suffix(`RETURNING id`).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+
+	var id int64
+	if err = client.QueryRow(ctx, sql, args...).Scan(&id); err != nil {
+		if isUniqueViolation(err) {
+			return nil, entity.ErrReviewAlreadyExists
+		}
+		return nil, fmt.Errorf("%s - client.QueryRow: %w", op, err)
+	}
+
+	return r.GetById(ctx, id)
+}
+
+func (r *ReviewRepo) selectReviews() squirrel.SelectBuilder {
+	return r.Builder.
+		Select(
+			"id", "created_at", "updated_at", "deleted_at",
+			"book_id", "user_id", "rating", "text", "status", "flag_count",
+		).
+		From("reviews").
+		Where("deleted_at IS NULL")
+}
+
+func (r *ReviewRepo) getOne(ctx context.Context, builder squirrel.SelectBuilder) (*entity.Review, error) {
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("r.Builder: %w", err)
+	}
+
+	client := r.GetClient(ctx)
+	row := client.QueryRow(ctx, sql, args...)
+
+	var e entity.Review
+	if err = row.Scan(
+		&e.ID, &e.CreatedAt, &e.UpdatedAt, &e.DeletedAt,
+		&e.BookID, &e.UserID, &e.Rating, &e.Text, &e.Status, &e.FlagCount,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrReviewNotFound
+		}
+
+		return nil, fmt.Errorf("row.Scan: %w", err)
+	}
+
+	return &e, nil
+}
+
+func (r *ReviewRepo) GetById(ctx context.Context, id int64) (*entity.Review, error) {
+	e, err := r.getOne(ctx, r.selectReviews().Where(squirrel.Eq{"id": id}))
+	if err != nil {
+		return nil, fmt.Errorf("ReviewRepo - GetById - %w", err)
+	}
+
+	return e, nil
+}
+
+// GetByIdForUpdate - locks the review until the transaction ends, so that concurrent changes
+// of the review move the book rating from the state the other one left.
+func (r *ReviewRepo) GetByIdForUpdate(ctx context.Context, id int64) (*entity.Review, error) {
+	e, err := r.getOne(ctx, r.selectReviews().Where(squirrel.Eq{"id": id}).Suffix("FOR UPDATE"))
+	if err != nil {
+		return nil, fmt.Errorf("ReviewRepo - GetByIdForUpdate - %w", err)
+	}
+
+	return e, nil
+}
+
+func (r *ReviewRepo) GetByBookAndUser(ctx context.Context, bookID, userID int64) (*entity.Review, error) {
+	e, err := r.getOne(ctx, r.selectReviews().
+		Where(squirrel.Eq{"book_id": bookID}).
+		Where(squirrel.Eq{"user_id": userID}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ReviewRepo - GetByBookAndUser - %w", err)
+	}
+
+	return e, nil
+}
+
+// GetAll - a page of reviews and the total number of reviews matching the filter.
+func (r *ReviewRepo) GetAll(ctx context.Context, filter entity.FilterReviewInput) ([]*entity.Review, int64, error) {
+	op := "ReviewRepo - GetAll"
+
+	where := squirrel.And{squirrel.Expr("deleted_at IS NULL")}
+	if filter.BookID != nil {
+		where = append(where, squirrel.Eq{"book_id": *filter.BookID})
+	}
+	if len(filter.Statuses) > 0 {
+		where = append(where, squirrel.Eq{"status": filter.Statuses})
+	}
+
+	client := r.GetClient(ctx)
+
+	sql, args, err := r.Builder.
+		Select("COUNT(*)").
+		From("reviews").
+		Where(where).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	var total int64
+	if err = client.QueryRow(ctx, sql, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("%s - client.QueryRow: %w", op, err)
+	}
+
+	sql, args, err = r.Builder.
+		Select(
+			"id", "created_at", "updated_at", "deleted_at",
+			"book_id", "user_id", "rating", "text", "status", "flag_count",
+		).
+		From("reviews").
+		Where(where).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(filter.Limit)).
+		Offset(uint64(filter.Offset())).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	rows, err := client.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s - client.Query: %w", op, err)
+	}
+	defer rows.Close()
+
+	items := make([]*entity.Review, 0, filter.Limit)
+
+	for rows.Next() {
+		e := entity.Review{}
+
+		if err = rows.Scan(
+			&e.ID, &e.CreatedAt, &e.UpdatedAt, &e.DeletedAt,
+			&e.BookID, &e.UserID, &e.Rating, &e.Text, &e.Status, &e.FlagCount,
+		); err != nil {
+			return nil, 0, fmt.Errorf("%s - rows.Scan: %w", op, err)
+		}
+
+		items = append(items, &e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%s - rows.Err: %w", op, err)
+	}
+
+	return items, total, nil
+}
+
+func (r *ReviewRepo) Update(ctx context.Context, e *entity.Review) error {
+	op := "ReviewRepo - Update"
+
+	sql, args, err := r.Builder.
+		Update("reviews").
+		Set("rating", e.Rating).
+		Set("text", e.Text).
+		Set("status", e.Status).
+		Set("updated_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"id": e.ID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	if _, err = client.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *ReviewRepo) DeleteById(ctx context.Context, id int64) error {
+	op := "ReviewRepo - DeleteById"
+
+	sql, args, err := r.Builder.
+		Update("reviews").
+		Set("deleted_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"id": id}).
+		Where("deleted_at IS NULL").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	if _, err = client.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	return nil
+}
+
+// AddFlag - records the user's report and bumps the review flag counter, a user can flag a review once.
+func (r *ReviewRepo) AddFlag(ctx context.Context, inp entity.FlagReviewInput) error {
+	op := "ReviewRepo - AddFlag"
+
+	client := r.GetClient(ctx)
+
+	sql, args, err := r.Builder.
+		Insert("review_flags").
+		Columns("review_id, user_id, reason").
+		Values(inp.ReviewID, inp.UserID, inp.Reason).
+		Suffix("ON CONFLICT (review_id, user_id) DO NOTHING").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	tag, err := client.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.ErrReviewAlreadyFlagged
+	}
+
+	sql, args, err = r.Builder.
+		Update("reviews").
+		Set("flag_count", squirrel.Expr("flag_count + 1")).
+		Where(squirrel.Eq{"id": inp.ReviewID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	if _, err = client.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package persistent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Alice00021/test_common/pkg/postgres"
+	"test_go/internal/entity"
+)
+
+type RevisionRepo struct {
+	*postgres.Postgres
+}
+
+func NewRevisionRepo(pg *postgres.Postgres) *RevisionRepo {
+	return &RevisionRepo{pg}
+}
+
+func (r *RevisionRepo) Create(ctx context.Context, e *entity.Revision) error {
+	op := "RevisionRepo - Create"
+
+	diff, err := json.Marshal(e.Diff)
+	if err != nil {
+		return fmt.Errorf("%s - json.Marshal: %w", op, err)
+	}
+
+	sql, args, err := r.Builder.
+		Insert("revisions").
+		Columns("entity_type, entity_id, action, actor_id, before, after, diff").
+		Values(e.EntityType, e.EntityID, e.Action, e.ActorID, nullableJSON(e.Before), nullableJSON(e.After), diff).
+		Suffix(`RETURNING id, created_at`).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	if err = client.QueryRow(ctx, sql, args...).Scan(&e.ID, &e.CreatedAt); err != nil {
+		return fmt.Errorf("%s - client.QueryRow: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *RevisionRepo) selectRevisions() squirrel.SelectBuilder {
+	return r.Builder.
+		Select(
+			"id", "created_at", "entity_type", "entity_id",
+			"action", "actor_id", "before", "after", "diff",
+		).
+		From("revisions")
+}
+
+func scanRevision(row pgx.Row) (*entity.Revision, error) {
+	var (
+		e                   entity.Revision
+		before, after, diff []byte
+	)
+
+	if err := row.Scan(
+		&e.ID, &e.CreatedAt, &e.EntityType, &e.EntityID,
+		&e.Action, &e.ActorID, &before, &after, &diff,
+	); err != nil {
+		return nil, err
+	}
+
+	e.Before, e.After = before, after
+	if err := json.Unmarshal(diff, &e.Diff); err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+func (r *RevisionRepo) GetById(ctx context.Context, id int64) (*entity.Revision, error) {
+	op := "RevisionRepo - GetById"
+
+	sql, args, err := r.selectRevisions().
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+
+	e, err := scanRevision(client.QueryRow(ctx, sql, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrRevisionNotFound
+		}
+
+		return nil, fmt.Errorf("%s - row.Scan: %w", op, err)
+	}
+
+	return e, nil
+}
+
+// GetByEntity - the history of a record, newest revision first.
+func (r *RevisionRepo) GetByEntity(ctx context.Context, entityType entity.RevisionEntityType, entityID string) ([]*entity.Revision, error) {
+	op := "RevisionRepo - GetByEntity"
+
+	sql, args, err := r.selectRevisions().
+		Where(squirrel.Eq{"entity_type": entityType}).
+		Where(squirrel.Eq{"entity_id": entityID}).
+		OrderBy("id DESC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	rows, err := client.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s - client.Query: %w", op, err)
+	}
+	defer rows.Close()
+
+	items := make([]*entity.Revision, 0, 16)
+
+	for rows.Next() {
+		e, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s - rows.Scan: %w", op, err)
+		}
+
+		items = append(items, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s - rows.Err: %w", op, err)
+	}
+
+	return items, nil
+}
+
+// nullableJSON - an absent snapshot is stored as NULL rather than an empty jsonb value.
+func nullableJSON(data json.RawMessage) any {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
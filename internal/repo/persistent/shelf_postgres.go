@@ -0,0 +1,528 @@
+package persistent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Alice00021/test_common/pkg/postgres"
+	"test_go/internal/entity"
+)
+
+type ShelfRepo struct {
+	*postgres.Postgres
+}
+
+func NewShelfRepo(pg *postgres.Postgres) *ShelfRepo {
+	return &ShelfRepo{pg}
+}
+
+// EnsureDefaults - creates the reading status shelves the user does not have yet.
+func (r *ShelfRepo) EnsureDefaults(ctx context.Context, userID int64) error {
+	op := "ShelfRepo - EnsureDefaults"
+
+	builder := r.Builder.
+		Insert("shelves").
+		Columns("user_id, name, kind")
+
+	for _, kind := range []entity.ShelfKind{
+		entity.ShelfKindWantToRead, entity.ShelfKindReading, entity.ShelfKindFinished,
+	} {
+		builder = builder.Values(userID, entity.DefaultShelves[kind], kind)
+	}
+
+	sql, args, err := builder.
+		Suffix("ON CONFLICT DO NOTHING").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	if _, err = client.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *ShelfRepo) Create(ctx context.Context, e *entity.Shelf) (*entity.Shelf, error) {
+	op := "ShelfRepo - Create"
+
+	sql, args, err := r.Builder.
+		Insert("shelves").
+		Columns("user_id, name, kind").
+		Values(e.UserID, e.Name, e.Kind).
+		Suffix(`RETURNING id`).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+
+	var id int64
+	if err = client.QueryRow(ctx, sql, args...).Scan(&id); err != nil {
+		return nil, fmt.Errorf("%s - client.QueryRow: %w", op, err)
+	}
+
+	return r.GetById(ctx, id)
+}
+
+func (r *ShelfRepo) GetById(ctx context.Context, id int64) (*entity.Shelf, error) {
+	op := "ShelfRepo - GetById"
+
+	sql, args, err := r.Builder.
+		Select(
+			"id", "created_at", "updated_at", "deleted_at",
+			"user_id", "name", "kind",
+		).
+		From("shelves").
+		Where("deleted_at IS NULL").
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	row := client.QueryRow(ctx, sql, args...)
+
+	var e entity.Shelf
+	if err = row.Scan(
+		&e.ID, &e.CreatedAt, &e.UpdatedAt, &e.DeletedAt, &e.UserID, &e.Name, &e.Kind,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrShelfNotFound
+		}
+
+		return nil, fmt.Errorf("%s - row.Scan: %w", op, err)
+	}
+
+	return &e, nil
+}
+
+func (r *ShelfRepo) GetByName(ctx context.Context, userID int64, name string) (*entity.Shelf, error) {
+	op := "ShelfRepo - GetByName"
+
+	sql, args, err := r.Builder.
+		Select(
+			"id", "created_at", "updated_at", "deleted_at",
+			"user_id", "name", "kind",
+		).
+		From("shelves").
+		Where("deleted_at IS NULL").
+		Where(squirrel.Eq{"user_id": userID}).
+		Where("LOWER(name) = LOWER(?)", name).
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	row := client.QueryRow(ctx, sql, args...)
+
+	var e entity.Shelf
+	if err = row.Scan(
+		&e.ID, &e.CreatedAt, &e.UpdatedAt, &e.DeletedAt, &e.UserID, &e.Name, &e.Kind,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrShelfNotFound
+		}
+
+		return nil, fmt.Errorf("%s - row.Scan: %w", op, err)
+	}
+
+	return &e, nil
+}
+
+func (r *ShelfRepo) GetByUserId(ctx context.Context, userID int64) ([]*entity.Shelf, error) {
+	op := "ShelfRepo - GetByUserId"
+
+	sql, args, err := r.Builder.
+		Select(
+			"id", "created_at", "updated_at", "deleted_at",
+			"user_id", "name", "kind",
+		).
+		From("shelves").
+		Where("deleted_at IS NULL").
+		Where(squirrel.Eq{"user_id": userID}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	rows, err := client.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s - client.Query: %w", op, err)
+	}
+	defer rows.Close()
+
+	items := make([]*entity.Shelf, 0, 8)
+
+	for rows.Next() {
+		e := entity.Shelf{}
+
+		if err = rows.Scan(
+			&e.ID, &e.CreatedAt, &e.UpdatedAt, &e.DeletedAt, &e.UserID, &e.Name, &e.Kind,
+		); err != nil {
+			return nil, fmt.Errorf("%s - rows.Scan: %w", op, err)
+		}
+
+		items = append(items, &e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s - rows.Err: %w", op, err)
+	}
+
+	return items, nil
+}
+
+// DeleteById - soft-deletes the shelf together with its books.
+func (r *ShelfRepo) DeleteById(ctx context.Context, id int64) error {
+	op := "ShelfRepo - DeleteById"
+
+	client := r.GetClient(ctx)
+
+	sql, args, err := r.Builder.
+		Update("shelf_books").
+		Set("deleted_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"shelf_id": id}).
+		Where("deleted_at IS NULL").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	if _, err = client.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	sql, args, err = r.Builder.
+		Update("shelves").
+		Set("deleted_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"id": id}).
+		Where("deleted_at IS NULL").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	if _, err = client.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *ShelfRepo) selectBooks() squirrel.SelectBuilder {
+	return r.Builder.
+		Select(
+			"sb.id", "sb.created_at", "sb.updated_at", "sb.deleted_at",
+			"sb.shelf_id", "sb.book_id", "sb.progress", "sb.started_at", "sb.finished_at",
+			"b.title", "b.author_id", "b.created_by",
+			"b.rating_avg", "b.rating_count", "b.cover_hash", "b.cover_type",
+			"a.name", "a.gender",
+		).
+		From("shelf_books sb").
+		InnerJoin("books b ON sb.book_id = b.id AND b.deleted_at IS NULL").
+		LeftJoin("authors a ON b.author_id = a.id").
+		Where("sb.deleted_at IS NULL")
+}
+
+func scanShelfBook(row pgx.Row) (*entity.ShelfBook, error) {
+	e := entity.ShelfBook{Book: &entity.Book{}}
+
+	if err := row.Scan(
+		&e.ID, &e.CreatedAt, &e.UpdatedAt, &e.DeletedAt,
+		&e.ShelfID, &e.BookID, &e.Progress, &e.StartedAt, &e.FinishedAt,
+		&e.Book.Title, &e.Book.AuthorId, &e.Book.CreatedBy,
+		&e.Book.RatingAvg, &e.Book.RatingCount, &e.Book.CoverHash, &e.Book.CoverType,
+		&e.Book.Author.Name, &e.Book.Author.Gender,
+	); err != nil {
+		return nil, err
+	}
+
+	e.Book.ID = e.BookID
+	e.Book.Author.ID = e.Book.AuthorId
+	e.Book.FillCoverURL()
+	return &e, nil
+}
+
+func (r *ShelfRepo) queryBooks(ctx context.Context, builder squirrel.SelectBuilder) ([]*entity.ShelfBook, error) {
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("r.Builder: %w", err)
+	}
+
+	client := r.GetClient(ctx)
+	rows, err := client.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("client.Query: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]*entity.ShelfBook, 0, 64)
+
+	for rows.Next() {
+		e, err := scanShelfBook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		items = append(items, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return items, nil
+}
+
+func (r *ShelfRepo) GetBooks(ctx context.Context, shelfIds []int64) ([]*entity.ShelfBook, error) {
+	items, err := r.queryBooks(ctx, r.selectBooks().
+		Where(squirrel.Eq{"sb.shelf_id": shelfIds}).
+		OrderBy("sb.updated_at DESC"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ShelfRepo - GetBooks - %w", err)
+	}
+
+	return items, nil
+}
+
+func (r *ShelfRepo) GetBook(ctx context.Context, shelfID, bookID int64) (*entity.ShelfBook, error) {
+	op := "ShelfRepo - GetBook"
+
+	sql, args, err := r.selectBooks().
+		Where(squirrel.Eq{"sb.shelf_id": shelfID}).
+		Where(squirrel.Eq{"sb.book_id": bookID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+
+	e, err := scanShelfBook(client.QueryRow(ctx, sql, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrShelfBookNotFound
+		}
+
+		return nil, fmt.Errorf("%s - row.Scan: %w", op, err)
+	}
+
+	return e, nil
+}
+
+// GetStatusBook - the book entry on whichever reading status shelf of the user it is on.
+func (r *ShelfRepo) GetStatusBook(ctx context.Context, userID, bookID int64) (*entity.ShelfBook, error) {
+	op := "ShelfRepo - GetStatusBook"
+
+	sql, args, err := r.selectBooks().
+		InnerJoin("shelves s ON sb.shelf_id = s.id AND s.deleted_at IS NULL").
+		Where(squirrel.Eq{"s.user_id": userID}).
+		Where(squirrel.NotEq{"s.kind": entity.ShelfKindCustom}).
+		Where(squirrel.Eq{"sb.book_id": bookID}).
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+
+	e, err := scanShelfBook(client.QueryRow(ctx, sql, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrShelfBookNotFound
+		}
+
+		return nil, fmt.Errorf("%s - row.Scan: %w", op, err)
+	}
+
+	return e, nil
+}
+
+// GetFinished - books the user finished in [from, to).
+func (r *ShelfRepo) GetFinished(ctx context.Context, userID int64, from, to time.Time) ([]*entity.ShelfBook, error) {
+	items, err := r.queryBooks(ctx, r.selectBooks().
+		InnerJoin("shelves s ON sb.shelf_id = s.id AND s.deleted_at IS NULL").
+		Where(squirrel.Eq{"s.user_id": userID}).
+		Where(squirrel.Eq{"s.kind": entity.ShelfKindFinished}).
+		Where(squirrel.GtOrEq{"sb.finished_at": from}).
+		Where(squirrel.Lt{"sb.finished_at": to}).
+		OrderBy("sb.finished_at"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ShelfRepo - GetFinished - %w", err)
+	}
+
+	return items, nil
+}
+
+func (r *ShelfRepo) AddBook(ctx context.Context, e *entity.ShelfBook) (*entity.ShelfBook, error) {
+	op := "ShelfRepo - AddBook"
+
+	sql, args, err := r.Builder.
+		Insert("shelf_books").
+		Columns("shelf_id, book_id, progress, started_at, finished_at").
+		Values(e.ShelfID, e.BookID, e.Progress, e.StartedAt, e.FinishedAt).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	if _, err = client.Exec(ctx, sql, args...); err != nil {
+		return nil, fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	return r.GetBook(ctx, e.ShelfID, e.BookID)
+}
+
+func (r *ShelfRepo) UpdateBook(ctx context.Context, e *entity.ShelfBook) error {
+	op := "ShelfRepo - UpdateBook"
+
+	sql, args, err := r.Builder.
+		Update("shelf_books").
+		Set("progress", e.Progress).
+		Set("started_at", e.StartedAt).
+		Set("finished_at", e.FinishedAt).
+		Set("updated_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"id": e.ID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	if _, err = client.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *ShelfRepo) RemoveBook(ctx context.Context, shelfID, bookID int64) error {
+	op := "ShelfRepo - RemoveBook"
+
+	sql, args, err := r.Builder.
+		Update("shelf_books").
+		Set("deleted_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"shelf_id": shelfID}).
+		Where(squirrel.Eq{"book_id": bookID}).
+		Where("deleted_at IS NULL").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	tag, err := client.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.ErrShelfBookNotFound
+	}
+	return nil
+}
+
+// DeleteByBookId - soft-removes a deleted book from every shelf.
+func (r *ShelfRepo) DeleteByBookId(ctx context.Context, bookID int64) error {
+	op := "ShelfRepo - DeleteByBookId"
+
+	sql, args, err := r.Builder.
+		Update("shelf_books").
+		Set("deleted_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"book_id": bookID}).
+		Where("deleted_at IS NULL").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	if _, err = client.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteByAuthorId - soft-removes the books of a cascade-deleted author from every shelf.
+func (r *ShelfRepo) DeleteByAuthorId(ctx context.Context, authorID int64) error {
+	op := "ShelfRepo - DeleteByAuthorId"
+
+	sql, args, err := r.Builder.
+		Update("shelf_books").
+		Set("deleted_at", squirrel.Expr("NOW()")).
+		Where("book_id IN (SELECT id FROM books WHERE author_id = ?)", authorID).
+		Where("deleted_at IS NULL").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	if _, err = client.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	return nil
+}
+
+// RestoreByBookId - puts a restored book back on the shelves it was removed from when deleted.
+func (r *ShelfRepo) RestoreByBookId(ctx context.Context, bookID int64, deletedAt time.Time) error {
+	op := "ShelfRepo - RestoreByBookId"
+
+	sql, args, err := r.Builder.
+		Update("shelf_books").
+		Set("deleted_at", nil).
+		Where(squirrel.Eq{"book_id": bookID}).
+		Where(squirrel.Eq{"deleted_at": deletedAt}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	if _, err = client.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *ShelfRepo) RestoreByAuthorId(ctx context.Context, authorID int64, deletedAt time.Time) error {
+	op := "ShelfRepo - RestoreByAuthorId"
+
+	sql, args, err := r.Builder.
+		Update("shelf_books").
+		Set("deleted_at", nil).
+		Where("book_id IN (SELECT id FROM books WHERE author_id = ?)", authorID).
+		Where(squirrel.Eq{"deleted_at": deletedAt}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	if _, err = client.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	return nil
+}
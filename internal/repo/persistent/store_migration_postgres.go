@@ -0,0 +1,331 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/Alice00021/test_common/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"test_go/internal/entity"
+)
+
+// StoreMigrationRepo - the postgres side of a store migration.
+type StoreMigrationRepo struct {
+	*postgres.Postgres
+	commands *CommandRepo
+}
+
+func NewStoreMigrationRepo(pg *postgres.Postgres) *StoreMigrationRepo {
+	return &StoreMigrationRepo{pg, NewCommandRepo(pg)}
+}
+
+func (r *StoreMigrationRepo) Counts(ctx context.Context) (entity.StoreCounts, error) {
+	op := "StoreMigrationRepo - Counts"
+
+	var counts entity.StoreCounts
+	client := r.GetClient(ctx)
+	if err := client.QueryRow(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM commands WHERE deleted_at IS NULL),
+			(SELECT COUNT(*) FROM operations WHERE deleted_at IS NULL),
+			(SELECT COUNT(*) FROM operation_commands opc
+				JOIN operations op ON op.id = opc.operation_id
+				WHERE op.deleted_at IS NULL)`,
+	).Scan(&counts.Commands, &counts.Operations, &counts.OperationCommands); err != nil {
+		return counts, fmt.Errorf("%s - client.QueryRow: %w", op, err)
+	}
+
+	return counts, nil
+}
+
+// StreamCommands - the catalog is small enough to be read at once, it is passed on ordered by system name.
+func (r *StoreMigrationRepo) StreamCommands(ctx context.Context, fn func(*entity.Command) error) error {
+	catalog, err := r.commands.GetBySystemNames(ctx)
+	if err != nil {
+		return fmt.Errorf("StoreMigrationRepo - StreamCommands - r.commands.GetBySystemNames: %w", err)
+	}
+
+	systemNames := make([]string, 0, len(catalog))
+	for systemName := range catalog {
+		systemNames = append(systemNames, systemName)
+	}
+	sort.Strings(systemNames)
+
+	for _, systemName := range systemNames {
+		c := catalog[systemName]
+		if err := fn(&c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *StoreMigrationRepo) StreamOperations(ctx context.Context, fn func(*entity.Operation) error) error {
+	if err := r.streamOperations(ctx, squirrel.Expr("TRUE"), fn); err != nil {
+		return fmt.Errorf("StoreMigrationRepo - StreamOperations - %w", err)
+	}
+	return nil
+}
+
+// streamOperations - reads the operations with their commands row by row,
+// an operation is passed on once all of its rows are read.
+func (r *StoreMigrationRepo) streamOperations(ctx context.Context, pred squirrel.Sqlizer, fn func(*entity.Operation) error) error {
+	sql, args, err := r.Builder.
+		Select(
+			"op.id", "op.created_at", "op.updated_at", "op.name", "op.description",
+			"op.average_time", "op.catalog_version_id", "op.deck_profile_id", "op.source_id",
+			"opc.id", "c.system_name", "opc.address",
+		).
+		From("operations op").
+		LeftJoin("operation_commands opc ON opc.operation_id = op.id").
+		LeftJoin("commands c ON opc.command_id = c.id").
+		Where("op.deleted_at IS NULL").
+		Where(pred).
+		OrderBy("op.id", "opc.id").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("r.Builder: %w", err)
+	}
+
+	client := r.GetClient(ctx)
+	rows, err := client.Query(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("client.Query: %w", err)
+	}
+	defer rows.Close()
+
+	var current *entity.Operation
+	for rows.Next() {
+		var (
+			id                  int64
+			e                   entity.Operation
+			sourceId            *string
+			opCommandId         *int64
+			systemName, address *string
+		)
+
+		if err := rows.Scan(
+			&id, &e.CreatedAt, &e.UpdatedAt, &e.Name, &e.Description,
+			&e.AverageTime, &e.CatalogVersionID, &e.DeckProfileID, &sourceId,
+			&opCommandId, &systemName, &address,
+		); err != nil {
+			return fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		e.ID = strconv.FormatInt(id, 10)
+		if current == nil || current.ID != e.ID {
+			if current != nil {
+				if err := fn(current); err != nil {
+					return err
+				}
+			}
+			if sourceId != nil {
+				e.SourceID = *sourceId
+			}
+			current = &e
+		}
+
+		if opCommandId != nil && systemName != nil {
+			c := &entity.OperationCommand{ID: *opCommandId, OperationID: id}
+			c.SystemName = *systemName
+			if address != nil {
+				c.Address = entity.Address(*address)
+			}
+			current.Commands = append(current.Commands, c)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows.Err: %w", err)
+	}
+
+	if current != nil {
+		return fn(current)
+	}
+	return nil
+}
+
+func (r *StoreMigrationRepo) GetCommand(ctx context.Context, systemName string) (*entity.Command, error) {
+	return r.commands.GetBySystemName(ctx, systemName)
+}
+
+// SaveCommand - inserts the command or overwrites the one with the same system name,
+// a soft-deleted command is brought back.
+func (r *StoreMigrationRepo) SaveCommand(ctx context.Context, e *entity.Command) error {
+	op := "StoreMigrationRepo - SaveCommand"
+
+	sql, args, err := r.Builder.
+		Insert("commands").
+		Columns(
+			"name, system_name, reagent, average_time, volume_waste, volume_drive_fluid, "+
+				"volume_container, default_address, is_disabled").
+		Values(
+			e.Name, e.SystemName, e.Reagent, e.AverageTime, e.VolumeWaste, e.VolumeDriveFluid,
+			e.VolumeContainer, e.DefaultAddress, e.Disabled).
+		Suffix(`ON CONFLICT (system_name) DO UPDATE SET
+			name = EXCLUDED.name,
+			reagent = EXCLUDED.reagent,
+			average_time = EXCLUDED.average_time,
+			volume_waste = EXCLUDED.volume_waste,
+			volume_drive_fluid = EXCLUDED.volume_drive_fluid,
+			volume_container = EXCLUDED.volume_container,
+			default_address = EXCLUDED.default_address,
+			is_disabled = EXCLUDED.is_disabled,
+			updated_at = NOW(),
+			deleted_at = NULL
+		RETURNING id`).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+
+	var id int64
+	if err := client.QueryRow(ctx, sql, args...).Scan(&id); err != nil {
+		return fmt.Errorf("%s - client.QueryRow: %w", op, err)
+	}
+
+	if err := r.commands.saveNames(ctx, id, e.Names); err != nil {
+		return fmt.Errorf("%s - %w", op, err)
+	}
+
+	return nil
+}
+
+// GetOperationByOrigin - the operation migrated from the origin id,
+// or the one created here with that id.
+func (r *StoreMigrationRepo) GetOperationByOrigin(ctx context.Context, origin string) (*entity.Operation, error) {
+	pred := squirrel.Or{squirrel.Eq{"op.source_id": origin}}
+	if id, err := strconv.ParseInt(origin, 10, 64); err == nil {
+		pred = append(pred, squirrel.And{squirrel.Eq{"op.source_id": nil}, squirrel.Eq{"op.id": id}})
+	}
+
+	var res *entity.Operation
+	if err := r.streamOperations(ctx, pred, func(e *entity.Operation) error {
+		res = e
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("StoreMigrationRepo - GetOperationByOrigin - %w", err)
+	}
+
+	if res == nil {
+		return nil, entity.ErrOperationNotFound
+	}
+	return res, nil
+}
+
+// CreateOperation - keeps the origin of the operation in source_id, it has to run inside a transaction.
+func (r *StoreMigrationRepo) CreateOperation(ctx context.Context, e *entity.Operation) error {
+	op := "StoreMigrationRepo - CreateOperation"
+
+	sql, args, err := r.Builder.
+		Insert("operations").
+		Columns(
+			"created_at, updated_at, name, description, average_time, catalog_version_id, deck_profile_id, source_id").
+		Values(
+			e.CreatedAt, e.UpdatedAt, e.Name, e.Description, e.AverageTime, e.CatalogVersionID, e.DeckProfileID, e.Origin()).
+		Suffix(`RETURNING id`).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+
+	var id int64
+	if err := client.QueryRow(ctx, sql, args...).Scan(&id); err != nil {
+		return fmt.Errorf("%s - client.QueryRow: %w", op, err)
+	}
+
+	if err := r.insertCommands(ctx, id, e.Commands); err != nil {
+		return fmt.Errorf("%s - %w", op, err)
+	}
+
+	return nil
+}
+
+// UpdateOperation - overwrites the operation with the id of e and replaces its commands,
+// it has to run inside a transaction.
+func (r *StoreMigrationRepo) UpdateOperation(ctx context.Context, e *entity.Operation) error {
+	op := "StoreMigrationRepo - UpdateOperation"
+
+	id, err := strconv.ParseInt(e.ID, 10, 64)
+	if err != nil {
+		return entity.ErrOperationNotFound
+	}
+
+	sql, args, err := r.Builder.
+		Update("operations").
+		Set("name", e.Name).
+		Set("description", e.Description).
+		Set("average_time", e.AverageTime).
+		Set("catalog_version_id", e.CatalogVersionID).
+		Set("deck_profile_id", e.DeckProfileID).
+		Set("updated_at", e.UpdatedAt).
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	if _, err := client.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	sql, args, err = r.Builder.
+		Delete("operation_commands").
+		Where(squirrel.Eq{"operation_id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	if _, err := client.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	if err := r.insertCommands(ctx, id, e.Commands); err != nil {
+		return fmt.Errorf("%s - %w", op, err)
+	}
+
+	return nil
+}
+
+// insertCommands - links the commands by system name in their order,
+// every command has to be migrated before the operations that use it.
+func (r *StoreMigrationRepo) insertCommands(ctx context.Context, operationId int64, commands []*entity.OperationCommand) error {
+	client := r.GetClient(ctx)
+
+	for _, c := range commands {
+		sql, args, err := r.Builder.
+			Insert("operation_commands").
+			Columns("operation_id", "command_id", "address").
+			Select(r.Builder.
+				Select().
+				Column("?::integer", operationId).
+				Column("id").
+				Column("?::varchar", c.Address).
+				From("commands").
+				Where("deleted_at IS NULL").
+				Where(squirrel.Eq{"system_name": c.SystemName})).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("r.Builder: %w", err)
+		}
+
+		tag, err := client.Exec(ctx, sql, args...)
+		if err != nil {
+			return fmt.Errorf("client.Exec: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("%w: %s", entity.ErrCommandNotFound, c.SystemName)
+		}
+	}
+
+	return nil
+}
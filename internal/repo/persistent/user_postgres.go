@@ -55,7 +55,7 @@ func (r *UserRepo) GetById(ctx context.Context, id int64) (*entity.User, error)
 		Select(
 			"id", "created_at", "updated_at", "deleted_at", "name",
 			"surname", "username", "password", "file_path", "email",
-			"verify_token", "is_verified", "rating", "role",
+			"verify_token", "is_verified", "rating", "role", "locale",
 		).
 		From("users").
 		Where("deleted_at IS NULL").
@@ -110,6 +110,32 @@ func (r *UserRepo) Update(ctx context.Context, e *entity.User) error {
 	return nil
 }
 
+// Patch - updates only the columns carried by the patch.
+func (r *UserRepo) Patch(ctx context.Context, inp entity.UpdateUserInput) error {
+	op := "UserRepo - Patch"
+
+	sqlBuilder := r.Builder.
+		Update("users").
+		Set("updated_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"id": inp.ID})
+	sqlBuilder = setPatch(sqlBuilder, "name", inp.Name)
+	sqlBuilder = setPatch(sqlBuilder, "surname", inp.Surname)
+	sqlBuilder = setPatch(sqlBuilder, "username", inp.Username)
+	sqlBuilder = setPatch(sqlBuilder, "locale", inp.Locale)
+
+	sql, args, err := sqlBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%s - r.Builder: %w", op, err)
+	}
+
+	client := r.GetClient(ctx)
+	if _, err = client.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("%s - client.Exec: %w", op, err)
+	}
+
+	return nil
+}
+
 func (r *UserRepo) GetByUserName(ctx context.Context, username string) (*entity.User, error) {
 	op := "UserRepo - GetById"
 
@@ -117,7 +143,7 @@ func (r *UserRepo) GetByUserName(ctx context.Context, username string) (*entity.
 		Select(
 			"id", "created_at", "updated_at", "deleted_at", "name",
 			"surname", "username", "password", "file_path", "email",
-			"verify_token", "is_verified", "rating", "role",
+			"verify_token", "is_verified", "rating", "role", "locale",
 		).
 		From("users").
 		Where("deleted_at IS NULL").
@@ -151,7 +177,7 @@ func (r *UserRepo) GetAll(ctx context.Context, filter entity.FilterUserInput) ([
 		Select(
 			"id", "created_at", "updated_at", "deleted_at", "name",
 			"surname", "username", "password", "file_path", "email",
-			"verify_token", "is_verified", "rating", "role",
+			"verify_token", "is_verified", "rating", "role", "locale",
 		).
 		From("users").
 		Where("deleted_at IS NULL")
@@ -182,7 +208,7 @@ func (r *UserRepo) GetAll(ctx context.Context, filter entity.FilterUserInput) ([
 		if err = rows.Scan(
 			&e.ID, &e.CreatedAt, &e.UpdatedAt, &e.DeletedAt, &e.Name,
 			&e.Surname, &e.Username, &e.Password, &e.FilePath, &e.Email,
-			&e.VerifyToken, &e.IsVerified, &e.Rating, &e.Role,
+			&e.VerifyToken, &e.IsVerified, &e.Rating, &e.Role, &e.Locale,
 		); err != nil {
 			return nil, fmt.Errorf("%s - row.Scan: %w", op, err)
 		}
@@ -204,7 +230,7 @@ func (r *UserRepo) GetByEmail(ctx context.Context, email string) (*entity.User,
 		Select(
 			"id", "created_at", "updated_at", "deleted_at", "name",
 			"surname", "username", "password", "file_path", "email",
-			"verify_token", "is_verified", "rating", "role",
+			"verify_token", "is_verified", "rating", "role", "locale",
 		).
 		From("users").
 		Where("deleted_at IS NULL").
@@ -238,7 +264,7 @@ func (r *UserRepo) GetByVerifyToken(ctx context.Context, verifyToken string) (*e
 		Select(
 			"id", "created_at", "updated_at", "deleted_at", "name",
 			"surname", "username", "password", "file_path", "email",
-			"verify_token", "is_verified", "rating", "role",
+			"verify_token", "is_verified", "rating", "role", "locale",
 		).
 		From("users").
 		Where("deleted_at IS NULL").
@@ -136,8 +136,9 @@ func (uc *useCase) Login(ctx context.Context, username string, password string)
 	}
 
 	userInfo := &entity.UserInfoToken{
-		ID:   user.ID,
-		Role: user.Role,
+		ID:     user.ID,
+		Role:   user.Role,
+		Locale: user.Locale,
 	}
 
 	tokenPair, err := uc.generateTokens(userInfo)
@@ -190,8 +191,9 @@ func (uc *useCase) RefreshTokens(ctx context.Context, refreshToken string) (*ent
 	}
 
 	userInfo := &entity.UserInfoToken{
-		ID:   user.ID,
-		Role: user.Role,
+		ID:     user.ID,
+		Role:   user.Role,
+		Locale: user.Locale,
 	}
 
 	tokenPair, err := uc.generateTokens(userInfo)
@@ -211,10 +213,13 @@ func (s *useCase) ValidateToken(ctx context.Context, token string) (*entity.User
 	if data, ok := claims["data"].(map[string]interface{}); ok {
 		id := data["id"]
 		role := data["role"]
+		// tokens issued before the locale was added to the claims have none
+		locale, _ := data["locale"].(string)
 
 		return &entity.UserInfoToken{
-			ID:   int64(id.(float64)),
-			Role: entity.UserRole(role.(string)),
+			ID:     int64(id.(float64)),
+			Role:   entity.UserRole(role.(string)),
+			Locale: locale,
 		}, nil
 	}
 
@@ -226,6 +231,9 @@ func (s *useCase) generateTokens(user *entity.UserInfoToken) (*entity.TokenPair,
 	data := make(map[string]interface{})
 	data["id"] = user.ID
 	data["role"] = user.Role
+	if user.Locale != "" {
+		data["locale"] = user.Locale
+	}
 
 	accessToken, err := jwt.GenerateToken(s.cfg.AccessTokenExpiresIn, data, s.PrivateKey, "")
 	if err != nil {
@@ -8,26 +8,42 @@ import (
 
 	"test_go/internal/entity"
 	"test_go/internal/repo"
+	"test_go/internal/usecase"
 )
 
 type useCase struct {
 	transactional.Transactional
-	repo repo.AuthorRepo
-	l    logger.Interface
+	repo         repo.AuthorRepo
+	bookRepo     repo.BookRepo
+	shelfRepo    repo.ShelfRepo
+	revisionUc   usecase.Revision
+	deletePolicy entity.AuthorDeletePolicy
+	statusRule   entity.AuthorStatusRule
+	l            logger.Interface
 }
 
 func New(t transactional.Transactional,
 	repo repo.AuthorRepo,
+	bookRepo repo.BookRepo,
+	shelfRepo repo.ShelfRepo,
+	revisionUc usecase.Revision,
+	deletePolicy entity.AuthorDeletePolicy,
+	statusRule entity.AuthorStatusRule,
 	l logger.Interface,
 ) *useCase {
 	return &useCase{
 		Transactional: t,
 		repo:          repo,
+		bookRepo:      bookRepo,
+		shelfRepo:     shelfRepo,
+		revisionUc:    revisionUc,
+		deletePolicy:  deletePolicy,
+		statusRule:    statusRule,
 		l:             l,
 	}
 }
 
-func (uc *useCase) CreateAuthor(ctx context.Context, inp entity.CreateAuthorInput) (*entity.Author, error) {
+func (uc *useCase) CreateAuthor(ctx context.Context, user *entity.UserInfoToken, inp entity.CreateAuthorInput) (*entity.Author, error) {
 	op := "AuthorUseCase - CreateAuthor"
 
 	var author entity.Author
@@ -40,6 +56,10 @@ func (uc *useCase) CreateAuthor(ctx context.Context, inp entity.CreateAuthorInpu
 			return fmt.Errorf("uc.repo.Create: %w", err)
 		}
 
+		if err := uc.recordRevision(txCtx, user, res.ID, entity.RevisionActionCreate, nil, res); err != nil {
+			return err
+		}
+
 		author = *res
 
 		return nil
@@ -50,18 +70,26 @@ func (uc *useCase) CreateAuthor(ctx context.Context, inp entity.CreateAuthorInpu
 	return &author, nil
 }
 
-func (uc *useCase) UpdateAuthor(ctx context.Context, inp entity.UpdateAuthorInput) error {
+func (uc *useCase) UpdateAuthor(ctx context.Context, user *entity.UserInfoToken, inp entity.UpdateAuthorInput) error {
+	op := "AuthorUseCase - UpdateAuthor"
+
+	if err := inp.Validate(); err != nil {
+		return fmt.Errorf("%s - inp.Validate: %w", op, err)
+	}
+
 	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
-		e := &entity.Author{
-			Entity: entity.Entity{ID: inp.ID},
-			Name:   inp.Name,
+		prev, err := uc.repo.GetById(txCtx, inp.ID)
+		if err != nil {
+			return fmt.Errorf("uc.repo.GetById: %w", err)
 		}
-		if err := uc.repo.Update(txCtx, e); err != nil {
-			return fmt.Errorf("uc.repo.Update: %w", err)
+
+		if err := uc.repo.Patch(txCtx, inp); err != nil {
+			return fmt.Errorf("uc.repo.Patch: %w", err)
 		}
-		return nil
+
+		return uc.recordRevision(txCtx, user, inp.ID, entity.RevisionActionUpdate, prev, inp.Apply(*prev))
 	}); err != nil {
-		return fmt.Errorf("%s - uc.RunInTransaction: %w", err)
+		return fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
 	}
 
 	return nil
@@ -82,20 +110,128 @@ func (uc *useCase) GetAuthors(ctx context.Context) ([]*entity.Author, error) {
 		return nil, fmt.Errorf("AuthorUseCase - GetAuthors - uc.repo.GetAll: %w", err)
 	}
 
+	stats, err := uc.bookRepo.GetStatsByAuthorIds(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("AuthorUseCase - GetAuthors - uc.bookRepo.GetStatsByAuthorIds: %w", err)
+	}
+
+	for _, author := range authors {
+		author.Stats = uc.withStatus(stats[author.ID])
+	}
+
 	return authors, nil
 }
 
-func (uc *useCase) DeleteAuthor(ctx context.Context, id int64) error {
+func (uc *useCase) GetAuthorStats(ctx context.Context, id int64) (*entity.AuthorStats, error) {
+	op := "AuthorUseCase - GetAuthorStats"
+
+	if _, err := uc.repo.GetById(ctx, id); err != nil {
+		return nil, fmt.Errorf("%s - uc.repo.GetById: %w", op, err)
+	}
+
+	stats, err := uc.bookRepo.GetStatsByAuthorIds(ctx, []int64{id})
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.bookRepo.GetStatsByAuthorIds: %w", op, err)
+	}
+
+	return uc.withStatus(stats[id]), nil
+}
+
+// withStatus - applies the status rule, an author without books gets empty stats.
+func (uc *useCase) withStatus(stats *entity.AuthorStats) *entity.AuthorStats {
+	if stats == nil {
+		stats = &entity.AuthorStats{}
+	}
+	stats.Status = uc.statusRule.Status(stats.BookCount)
+	return stats
+}
+
+func (uc *useCase) DeleteAuthor(ctx context.Context, user *entity.UserInfoToken, inp entity.DeleteAuthorInput) error {
 	op := "AuthorUseCase - DeleteAuthor"
 
+	policy := uc.deletePolicy
+	if inp.Policy != nil {
+		policy = *inp.Policy
+	}
+	if !policy.IsValid() {
+		return fmt.Errorf("%s: %w", op, entity.ErrInvalidDeletePolicy)
+	}
+
 	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
-		if err := uc.repo.DeleteById(txCtx, id); err != nil {
+		author, err := uc.repo.GetById(txCtx, inp.ID)
+		if err != nil {
+			return fmt.Errorf("uc.repo.GetById: %w", err)
+		}
+
+		count, err := uc.bookRepo.CountByAuthorId(txCtx, inp.ID)
+		if err != nil {
+			return fmt.Errorf("uc.bookRepo.CountByAuthorId: %w", err)
+		}
+
+		if count > 0 {
+			if err := uc.applyDeletePolicy(txCtx, user, policy, inp); err != nil {
+				return err
+			}
+		}
+
+		if err := uc.repo.DeleteById(txCtx, inp.ID); err != nil {
 			return fmt.Errorf("uc.repo.DeleteById: %w", err)
 		}
-		return nil
+
+		return uc.recordRevision(txCtx, user, inp.ID, entity.RevisionActionDelete, author, nil)
 	}); err != nil {
 		return fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
 	}
 
 	return nil
 }
+
+// applyDeletePolicy - deletes the books together with the author or moves them to another one,
+// every book gets a revision of its own.
+func (uc *useCase) applyDeletePolicy(ctx context.Context, user *entity.UserInfoToken,
+	policy entity.AuthorDeletePolicy, inp entity.DeleteAuthorInput,
+) error {
+	if policy != entity.AuthorDeletePolicyCascade && policy != entity.AuthorDeletePolicyReassign {
+		return entity.ErrAuthorHasBooks
+	}
+
+	books, err := uc.bookRepo.GetByAuthorId(ctx, inp.ID)
+	if err != nil {
+		return fmt.Errorf("uc.bookRepo.GetByAuthorId: %w", err)
+	}
+
+	if policy == entity.AuthorDeletePolicyCascade {
+		if _, err := uc.bookRepo.DeleteByAuthorId(ctx, inp.ID); err != nil {
+			return fmt.Errorf("uc.bookRepo.DeleteByAuthorId: %w", err)
+		}
+		if err := uc.shelfRepo.DeleteByAuthorId(ctx, inp.ID); err != nil {
+			return fmt.Errorf("uc.shelfRepo.DeleteByAuthorId: %w", err)
+		}
+
+		for _, book := range books {
+			if err := uc.recordBookRevision(ctx, user, entity.RevisionActionDelete, book, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if inp.ReassignTo == nil || *inp.ReassignTo == inp.ID {
+		return entity.ErrReassignAuthorRequired
+	}
+	if _, err := uc.repo.GetById(ctx, *inp.ReassignTo); err != nil {
+		return fmt.Errorf("uc.repo.GetById: %w", err)
+	}
+	if err := uc.bookRepo.ReassignAuthor(ctx, inp.ID, *inp.ReassignTo); err != nil {
+		return fmt.Errorf("uc.bookRepo.ReassignAuthor: %w", err)
+	}
+
+	for _, book := range books {
+		moved := *book
+		moved.AuthorId = *inp.ReassignTo
+		if err := uc.recordBookRevision(ctx, user, entity.RevisionActionUpdate, book, &moved); err != nil {
+			return err
+		}
+	}
+	return nil
+}
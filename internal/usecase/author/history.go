@@ -0,0 +1,75 @@
+package author
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"test_go/internal/entity"
+)
+
+func (uc *useCase) GetAuthorHistory(ctx context.Context, id int64) ([]*entity.Revision, error) {
+	items, err := uc.revisionUc.GetHistory(ctx, entity.RevisionEntityAuthor, strconv.FormatInt(id, 10))
+	if err != nil {
+		return nil, fmt.Errorf("AuthorUseCase - GetAuthorHistory - uc.revisionUc.GetHistory: %w", err)
+	}
+
+	return items, nil
+}
+
+// RestoreAuthorRevision - brings the author back to the state recorded by the revision.
+func (uc *useCase) RestoreAuthorRevision(ctx context.Context, user *entity.UserInfoToken, id, revisionId int64) (*entity.Author, error) {
+	op := "AuthorUseCase - RestoreAuthorRevision"
+
+	var author entity.Author
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		rev, err := uc.revisionUc.GetRevision(txCtx, entity.RevisionEntityAuthor, strconv.FormatInt(id, 10), revisionId)
+		if err != nil {
+			return fmt.Errorf("uc.revisionUc.GetRevision: %w", err)
+		}
+
+		var snapshot entity.AuthorSnapshot
+		if err := rev.RestoreInto(&snapshot); err != nil {
+			return fmt.Errorf("rev.RestoreInto: %w", err)
+		}
+
+		prev, err := uc.repo.GetById(txCtx, id)
+		if err != nil {
+			return fmt.Errorf("uc.repo.GetById: %w", err)
+		}
+
+		author = *prev
+		author.Name = snapshot.Name
+		author.Gender = snapshot.Gender
+		if err := uc.repo.Update(txCtx, &author); err != nil {
+			return fmt.Errorf("uc.repo.Update: %w", err)
+		}
+
+		return uc.recordRevision(txCtx, user, id, entity.RevisionActionRestore, prev, &author)
+	}); err != nil {
+		return nil, fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	return &author, nil
+}
+
+func (uc *useCase) recordRevision(ctx context.Context, user *entity.UserInfoToken, id int64,
+	action entity.RevisionAction, before, after *entity.Author,
+) error {
+	if err := uc.revisionUc.Record(ctx, entity.AuthorRevision(user, id, action, before, after)); err != nil {
+		return fmt.Errorf("uc.revisionUc.Record: %w", err)
+	}
+	return nil
+}
+
+func (uc *useCase) recordBookRevision(ctx context.Context, user *entity.UserInfoToken,
+	action entity.RevisionAction, before, after *entity.Book,
+) error {
+	id := before.ID
+	if after != nil {
+		id = after.ID
+	}
+	if err := uc.revisionUc.Record(ctx, entity.BookRevision(user, id, action, before, after)); err != nil {
+		return fmt.Errorf("uc.revisionUc.Record: %w", err)
+	}
+	return nil
+}
@@ -7,21 +7,34 @@ import (
 	"github.com/Alice00021/test_common/pkg/transactional"
 	"test_go/internal/entity"
 	"test_go/internal/repo"
+	"test_go/internal/usecase"
 )
 
 type useCase struct {
 	transactional.Transactional
-	repo repo.BookRepo
-	l    logger.Interface
+	repo          repo.BookRepo
+	shelfRepo     repo.ShelfRepo
+	coverStorage  repo.CoverStorage
+	coverSettings entity.CoverSettings
+	revisionUc    usecase.Revision
+	l             logger.Interface
 }
 
 func New(t transactional.Transactional,
 	repo repo.BookRepo,
+	shelfRepo repo.ShelfRepo,
+	coverStorage repo.CoverStorage,
+	coverSettings entity.CoverSettings,
+	revisionUc usecase.Revision,
 	l logger.Interface,
 ) *useCase {
 	return &useCase{
 		Transactional: t,
 		repo:          repo,
+		shelfRepo:     shelfRepo,
+		coverStorage:  coverStorage,
+		coverSettings: coverSettings,
+		revisionUc:    revisionUc,
 		l:             l,
 	}
 }
@@ -32,13 +45,18 @@ func (uc *useCase) CreateBook(ctx context.Context, inp entity.CreateBookInput) (
 	var book entity.Book
 	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
 		e := entity.NewBook(
-			inp.Title, inp.AuthorId,
+			inp.Title, inp.AuthorId, inp.CreatedBy,
 		)
 		res, err := uc.repo.Create(txCtx, e)
 		if err != nil {
 			return fmt.Errorf("uc.repo.Create: %w", err)
 		}
 
+		actor := &entity.UserInfoToken{ID: inp.CreatedBy}
+		if err := uc.recordRevision(txCtx, actor, res.ID, entity.RevisionActionCreate, nil, res); err != nil {
+			return err
+		}
+
 		book = *res
 
 		return nil
@@ -49,19 +67,26 @@ func (uc *useCase) CreateBook(ctx context.Context, inp entity.CreateBookInput) (
 	return &book, nil
 }
 
-func (uc *useCase) UpdateBook(ctx context.Context, inp entity.UpdateBookInput) error {
+func (uc *useCase) UpdateBook(ctx context.Context, user *entity.UserInfoToken, inp entity.UpdateBookInput) error {
+	op := "BookUseCase - UpdateBook"
+
+	if err := inp.Validate(); err != nil {
+		return fmt.Errorf("%s - inp.Validate: %w", op, err)
+	}
+
 	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
-		e := &entity.Book{
-			Entity:   entity.Entity{ID: inp.ID},
-			Title:    inp.Title,
-			AuthorId: inp.AuthorId,
+		prev, err := uc.checkAccess(txCtx, user, inp.ID)
+		if err != nil {
+			return err
 		}
-		if err := uc.repo.Update(txCtx, e); err != nil {
-			return fmt.Errorf("uc.repo.Update: %w", err)
+
+		if err := uc.repo.Patch(txCtx, inp); err != nil {
+			return fmt.Errorf("uc.repo.Patch: %w", err)
 		}
-		return nil
+
+		return uc.recordRevision(txCtx, user, inp.ID, entity.RevisionActionUpdate, prev, inp.Apply(*prev))
 	}); err != nil {
-		return fmt.Errorf("%s - uc.RunInTransaction: %w", err)
+		return fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
 	}
 
 	return nil
@@ -85,17 +110,41 @@ func (uc *useCase) GetBooks(ctx context.Context) ([]*entity.Book, error) {
 	return books, nil
 }
 
-func (uc *useCase) DeleteBook(ctx context.Context, id int64) error {
+func (uc *useCase) DeleteBook(ctx context.Context, user *entity.UserInfoToken, id int64) error {
 	op := "BookUseCase - DeleteBook"
 
 	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		book, err := uc.checkAccess(txCtx, user, id)
+		if err != nil {
+			return err
+		}
+
 		if err := uc.repo.DeleteById(txCtx, id); err != nil {
 			return fmt.Errorf("uc.repo.DeleteById: %w", err)
 		}
-		return nil
+
+		if err := uc.shelfRepo.DeleteByBookId(txCtx, id); err != nil {
+			return fmt.Errorf("uc.shelfRepo.DeleteByBookId: %w", err)
+		}
+
+		return uc.recordRevision(txCtx, user, id, entity.RevisionActionDelete, book, nil)
 	}); err != nil {
 		return fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
 	}
 
 	return nil
 }
+
+// checkAccess - the book if the user is allowed to change it.
+func (uc *useCase) checkAccess(ctx context.Context, user *entity.UserInfoToken, id int64) (*entity.Book, error) {
+	book, err := uc.repo.GetById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("uc.repo.GetById: %w", err)
+	}
+
+	if !book.CanBeModifiedBy(user) {
+		return nil, entity.ErrAccessDenied
+	}
+
+	return book, nil
+}
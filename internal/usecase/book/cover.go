@@ -0,0 +1,224 @@
+package book
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"slices"
+	"test_go/internal/entity"
+)
+
+// SetCover - the files are written once the book refers to the new cover for sure,
+// a failed write leaves the book without a cover rather than with one that has no files.
+func (uc *useCase) SetCover(ctx context.Context, user *entity.UserInfoToken, id int64, file *multipart.FileHeader) (*entity.Book, error) {
+	op := "BookUseCase - SetCover"
+
+	cover, err := uc.processCover(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.processCover: %w", op, err)
+	}
+
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		if _, err := uc.checkAccess(txCtx, user, id); err != nil {
+			return err
+		}
+
+		if err := uc.repo.UpdateCover(txCtx, id, &cover.Hash, &cover.ContentType); err != nil {
+			return fmt.Errorf("uc.repo.UpdateCover: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	if err := uc.coverStorage.Save(ctx, id, cover); err != nil {
+		if err := uc.repo.UpdateCover(ctx, id, nil, nil); err != nil {
+			uc.l.Error(fmt.Errorf("%s - uc.repo.UpdateCover: %w", op, err))
+		}
+		return nil, fmt.Errorf("%s - uc.coverStorage.Save: %w", op, err)
+	}
+
+	return uc.GetBook(ctx, id)
+}
+
+// DeleteCover - the files are removed once the book no longer refers to them,
+// files left behind by a failed removal are only logged since nothing serves them.
+func (uc *useCase) DeleteCover(ctx context.Context, user *entity.UserInfoToken, id int64) error {
+	op := "BookUseCase - DeleteCover"
+
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		if _, err := uc.checkAccess(txCtx, user, id); err != nil {
+			return err
+		}
+
+		if err := uc.repo.UpdateCover(txCtx, id, nil, nil); err != nil {
+			return fmt.Errorf("uc.repo.UpdateCover: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	if err := uc.coverStorage.Delete(ctx, id); err != nil {
+		uc.l.Error(fmt.Errorf("%s - uc.coverStorage.Delete: %w", op, err))
+	}
+
+	return nil
+}
+
+// GetCover - the cover file of the requested size, the original is returned when the image
+// was too small to get a thumbnail of that size.
+func (uc *useCase) GetCover(ctx context.Context, id int64, size int) (*entity.CoverFile, error) {
+	op := "BookUseCase - GetCover"
+
+	if size > 0 && !slices.Contains(uc.coverSettings.ThumbnailSizes, size) {
+		return nil, fmt.Errorf("%s: %w", op, entity.ErrCoverNotFound)
+	}
+
+	book, err := uc.repo.GetById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.repo.GetById: %w", op, err)
+	}
+
+	if book.CoverHash == nil || book.CoverType == nil {
+		return nil, fmt.Errorf("%s: %w", op, entity.ErrCoverNotFound)
+	}
+
+	variant := entity.CoverVariant(size)
+
+	file, err := uc.coverStorage.Stat(ctx, id, variant, *book.CoverType)
+	if err != nil && variant != entity.CoverVariantOriginal {
+		variant = entity.CoverVariantOriginal
+		file, err = uc.coverStorage.Stat(ctx, id, variant, *book.CoverType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.coverStorage.Stat: %w", op, err)
+	}
+
+	file.ETag = fmt.Sprintf(`"%s-%s"`, *book.CoverHash, variant)
+	return file, nil
+}
+
+// processCover - checks the upload by its content rather than by the file name
+// and renders the thumbnails in the format of the original. The dimensions are checked
+// from the header before the image is decoded.
+func (uc *useCase) processCover(file *multipart.FileHeader) (*entity.CoverImage, error) {
+	if file.Size > uc.coverSettings.MaxSize {
+		return nil, entity.ErrCoverTooLarge
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, entity.ErrOpenFile
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, uc.coverSettings.MaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("io.ReadAll: %w", err)
+	}
+	if int64(len(data)) > uc.coverSettings.MaxSize {
+		return nil, entity.ErrCoverTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	if _, ok := entity.CoverContentTypes[contentType]; !ok {
+		return nil, entity.ErrUnsupportedCoverType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, entity.ErrUnsupportedCoverType
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, entity.ErrUnsupportedCoverType
+	}
+	if config.Width > uc.coverSettings.MaxPixels/config.Height {
+		return nil, entity.ErrCoverTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, entity.ErrUnsupportedCoverType
+	}
+
+	hash := sha256.Sum256(data)
+	cover := &entity.CoverImage{
+		Hash:        hex.EncodeToString(hash[:]),
+		ContentType: contentType,
+		Files:       map[string][]byte{entity.CoverVariantOriginal: data},
+	}
+
+	bounds := img.Bounds()
+	longest := max(bounds.Dx(), bounds.Dy())
+
+	for _, size := range uc.coverSettings.ThumbnailSizes {
+		if size <= 0 || size >= longest {
+			continue
+		}
+
+		var buf bytes.Buffer
+		thumb := scaleDown(img, size)
+		if contentType == "image/png" {
+			err = png.Encode(&buf, thumb)
+		} else {
+			err = jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: 85})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("encode thumbnail: %w", err)
+		}
+
+		cover.Files[entity.CoverVariant(size)] = buf.Bytes()
+	}
+
+	return cover, nil
+}
+
+// scaleDown - box filter resize keeping the aspect ratio, longest is the size of the longest side.
+func scaleDown(src image.Image, longest int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	dw, dh := longest, h*longest/w
+	if h > w {
+		dw, dh = w*longest/h, longest
+	}
+	dw, dh = max(dw, 1), max(dh, 1)
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := b.Min.Y+y*h/dh, b.Min.Y+max((y+1)*h/dh, y*h/dh+1)
+		for x := 0; x < dw; x++ {
+			x0, x1 := b.Min.X+x*w/dw, b.Min.X+max((x+1)*w/dw, x*w/dw+1)
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			if a == 0 {
+				continue
+			}
+			// At returns alpha-premultiplied colors, NRGBA stores them straight
+			dst.Pix[i+0] = uint8(r * 0xff / a)
+			dst.Pix[i+1] = uint8(g * 0xff / a)
+			dst.Pix[i+2] = uint8(bl * 0xff / a)
+			dst.Pix[i+3] = uint8(a / n >> 8)
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,62 @@
+package book
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"test_go/internal/entity"
+)
+
+func (uc *useCase) GetBookHistory(ctx context.Context, id int64) ([]*entity.Revision, error) {
+	items, err := uc.revisionUc.GetHistory(ctx, entity.RevisionEntityBook, strconv.FormatInt(id, 10))
+	if err != nil {
+		return nil, fmt.Errorf("BookUseCase - GetBookHistory - uc.revisionUc.GetHistory: %w", err)
+	}
+
+	return items, nil
+}
+
+// RestoreBookRevision - brings the book back to the state recorded by the revision,
+// the same users who may edit the book may restore it.
+func (uc *useCase) RestoreBookRevision(ctx context.Context, user *entity.UserInfoToken, id, revisionId int64) (*entity.Book, error) {
+	op := "BookUseCase - RestoreBookRevision"
+
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		prev, err := uc.checkAccess(txCtx, user, id)
+		if err != nil {
+			return err
+		}
+
+		rev, err := uc.revisionUc.GetRevision(txCtx, entity.RevisionEntityBook, strconv.FormatInt(id, 10), revisionId)
+		if err != nil {
+			return fmt.Errorf("uc.revisionUc.GetRevision: %w", err)
+		}
+
+		var snapshot entity.BookSnapshot
+		if err := rev.RestoreInto(&snapshot); err != nil {
+			return fmt.Errorf("rev.RestoreInto: %w", err)
+		}
+
+		e := *prev
+		e.Title = snapshot.Title
+		e.AuthorId = snapshot.AuthorId
+		if err := uc.repo.Update(txCtx, &e); err != nil {
+			return fmt.Errorf("uc.repo.Update: %w", err)
+		}
+
+		return uc.recordRevision(txCtx, user, id, entity.RevisionActionRestore, prev, &e)
+	}); err != nil {
+		return nil, fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	return uc.GetBook(ctx, id)
+}
+
+func (uc *useCase) recordRevision(ctx context.Context, user *entity.UserInfoToken, id int64,
+	action entity.RevisionAction, before, after *entity.Book,
+) error {
+	if err := uc.revisionUc.Record(ctx, entity.BookRevision(user, id, action, before, after)); err != nil {
+		return fmt.Errorf("uc.revisionUc.Record: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"test_go/internal/entity"
+	"test_go/internal/repo"
+)
+
+// catalogVersions - the catalog history shared by both command use cases,
+// versions are kept in postgres whichever storage holds the commands.
+type catalogVersions struct {
+	catalogRepo repo.CommandCatalogRepo
+}
+
+func (uc *catalogVersions) GetCatalogVersions(ctx context.Context) ([]*entity.CommandCatalogVersion, error) {
+	versions, err := uc.catalogRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("CommandUseCase - GetCatalogVersions - uc.catalogRepo.GetAll: %w", err)
+	}
+
+	return versions, nil
+}
+
+func (uc *catalogVersions) GetCatalogVersion(ctx context.Context, id int64) (*entity.CommandCatalogVersion, error) {
+	version, err := uc.catalogRepo.GetById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("CommandUseCase - GetCatalogVersion - uc.catalogRepo.GetById: %w", err)
+	}
+
+	return version, nil
+}
+
+// CompareCatalogVersions - the changes between two versions as if the second one was imported over the first.
+func (uc *catalogVersions) CompareCatalogVersions(ctx context.Context, fromID, toID int64) (*entity.CommandCatalogDiff, error) {
+	op := "CommandUseCase - CompareCatalogVersions"
+
+	from, err := uc.catalogRepo.GetById(ctx, fromID)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.catalogRepo.GetById: %w", op, err)
+	}
+
+	to, err := uc.catalogRepo.GetById(ctx, toID)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.catalogRepo.GetById: %w", op, err)
+	}
+
+	diff := entity.CompareCommandCatalogVersions(from, to)
+	diff.DryRun = true
+	diff.Version = to.Summary()
+
+	return diff, nil
+}
+
+// recordVersion - snapshots the stored commands after a change and makes the snapshot the active version.
+func (uc *catalogVersions) recordVersion(ctx context.Context, stored map[string]entity.Command, user *entity.UserInfoToken) (*entity.CommandCatalogVersion, error) {
+	version, err := entity.NewCommandCatalogVersion(stored, entity.ActorOf(user))
+	if err != nil {
+		return nil, fmt.Errorf("entity.NewCommandCatalogVersion: %w", err)
+	}
+
+	return uc.activateVersion(ctx, version)
+}
+
+// activateVersion - stores the version and makes it active, a change that leaves the commands
+// as they are in the active version keeps it instead of adding a copy.
+func (uc *catalogVersions) activateVersion(ctx context.Context, version *entity.CommandCatalogVersion) (*entity.CommandCatalogVersion, error) {
+	active, err := uc.catalogRepo.GetActive(ctx)
+	switch {
+	case err == nil && active.Checksum == version.Checksum:
+		return active.Summary(), nil
+	case err != nil && !errors.Is(err, entity.ErrCommandCatalogVersionNotFound):
+		return nil, fmt.Errorf("uc.catalogRepo.GetActive: %w", err)
+	}
+
+	if err := uc.catalogRepo.Create(ctx, version); err != nil {
+		return nil, fmt.Errorf("uc.catalogRepo.Create: %w", err)
+	}
+
+	if err := uc.catalogRepo.Activate(ctx, version.ID); err != nil {
+		return nil, fmt.Errorf("uc.catalogRepo.Activate: %w", err)
+	}
+	version.Active = true
+
+	return version.Summary(), nil
+}
@@ -2,7 +2,6 @@ package command
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"github.com/Alice00021/test_common/pkg/logger"
 	"github.com/Alice00021/test_common/pkg/transactional"
@@ -14,6 +13,7 @@ import (
 
 type useCase struct {
 	transactional.Transactional
+	catalogVersions
 	repo        repo.CommandRepo
 	jsonStorage config.LocalFileStorage
 	l           logger.Interface
@@ -21,14 +21,16 @@ type useCase struct {
 
 func New(t transactional.Transactional,
 	repo repo.CommandRepo,
+	catalogRepo repo.CommandCatalogRepo,
 	jsonStorage config.LocalFileStorage,
 	l logger.Interface,
 ) *useCase {
 	return &useCase{
-		Transactional: t,
-		repo:          repo,
-		jsonStorage:   jsonStorage,
-		l:             l,
+		Transactional:   t,
+		catalogVersions: catalogVersions{catalogRepo: catalogRepo},
+		repo:            repo,
+		jsonStorage:     jsonStorage,
+		l:               l,
 	}
 }
 
@@ -41,42 +43,199 @@ func (uc *useCase) UpdateCommands(ctx context.Context) error {
 	}
 	defer file.Close()
 
-	var commands []entity.Command
-	if err := json.NewDecoder(file).Decode(&commands); err != nil {
-		return fmt.Errorf("%s - json.Decode: %w", op, err)
+	if _, err := uc.ImportCommands(ctx, entity.ImportCommandsInput{File: file}); err != nil {
+		return fmt.Errorf("%s - uc.ImportCommands: %w", op, err)
+	}
+	return nil
+}
+
+// ImportCommands - diffs the catalog against the stored commands and,
+// unless it is a dry run, creates the added commands, updates the changed ones,
+// disables the missing ones and records the result as the active version.
+func (uc *useCase) ImportCommands(ctx context.Context, inp entity.ImportCommandsInput) (*entity.CommandCatalogDiff, error) {
+	op := "CommandUseCase - ImportCommands"
+
+	commands, err := entity.ParseCommandCatalog(inp.File)
+	if err != nil {
+		return nil, fmt.Errorf("%s - entity.ParseCommandCatalog: %w", op, err)
 	}
 
+	var diff *entity.CommandCatalogDiff
 	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
 		mapCommands, err := uc.repo.GetBySystemNames(txCtx)
 		if err != nil {
-			return fmt.Errorf("%s - uc.repo.GetBySystemNames: %w", op, err)
-		}
-
-		for i := range commands {
-			cmd := &commands[i]
-			if existingCmd, exists := mapCommands[cmd.SystemName]; exists {
-				cmd.ID = existingCmd.ID
-				if err := uc.repo.Update(txCtx, cmd); err != nil {
-					return fmt.Errorf("%s - uc.repo.Update: %w", op, err)
-				}
-			} else {
-				if _, err := uc.repo.Create(txCtx, cmd); err != nil {
-					return fmt.Errorf("%s - uc.repo.Create: %w", op, err)
-				}
+			return fmt.Errorf("uc.repo.GetBySystemNames: %w", err)
+		}
+
+		diff = entity.DiffCommandCatalog(mapCommands, commands)
+		diff.DryRun = inp.DryRun
+		if inp.DryRun {
+			return nil
+		}
+
+		if err := uc.applyDiff(txCtx, diff); err != nil {
+			return err
+		}
+
+		for _, systemName := range diff.MissingEnabled() {
+			if err := uc.repo.Disable(txCtx, systemName, true); err != nil {
+				return fmt.Errorf("uc.repo.Disable: %w", err)
 			}
 		}
+
+		if diff.Version, err = uc.recordStored(txCtx, inp.User); err != nil {
+			return err
+		}
+
+		diff.Applied = true
 		return nil
 	}); err != nil {
-		return fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+		return nil, fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	return diff, nil
+}
+
+// RollbackCatalog - syncs the commands and their disabled flags back to a stored version,
+// disables the ones the version does not have and makes it the active one.
+func (uc *useCase) RollbackCatalog(ctx context.Context, id int64) (*entity.CommandCatalogDiff, error) {
+	op := "CommandUseCase - RollbackCatalog"
+
+	var diff *entity.CommandCatalogDiff
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		version, err := uc.catalogRepo.GetById(txCtx, id)
+		if err != nil {
+			return fmt.Errorf("uc.catalogRepo.GetById: %w", err)
+		}
+
+		mapCommands, err := uc.repo.GetBySystemNames(txCtx)
+		if err != nil {
+			return fmt.Errorf("uc.repo.GetBySystemNames: %w", err)
+		}
+
+		diff = entity.DiffCommandCatalog(mapCommands, version.CatalogCommands())
+		if err := uc.applyDiff(txCtx, diff); err != nil {
+			return err
+		}
+
+		for systemName, disabled := range version.DisabledChanges(mapCommands) {
+			if err := uc.repo.Disable(txCtx, systemName, disabled); err != nil {
+				return fmt.Errorf("uc.repo.Disable: %w", err)
+			}
+		}
+
+		if err := uc.catalogRepo.Activate(txCtx, version.ID); err != nil {
+			return fmt.Errorf("uc.catalogRepo.Activate: %w", err)
+		}
+		version.Active = true
+
+		diff.Version = version.Summary()
+		diff.Applied = true
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	return diff, nil
+}
+
+func (uc *useCase) applyDiff(ctx context.Context, diff *entity.CommandCatalogDiff) error {
+	for i := range diff.Added {
+		if _, err := uc.repo.Create(ctx, &diff.Added[i]); err != nil {
+			return fmt.Errorf("uc.repo.Create: %w", err)
+		}
 	}
+
+	for _, change := range diff.Changed {
+		if err := uc.repo.Update(ctx, &change.Command); err != nil {
+			return fmt.Errorf("uc.repo.Update: %w", err)
+		}
+	}
+
 	return nil
 }
 
-func (uc *useCase) GetCommands(ctx context.Context) (map[string]entity.Command, error) {
+// recordStored - records the stored commands as the active catalog version.
+func (uc *useCase) recordStored(ctx context.Context, user *entity.UserInfoToken) (*entity.CommandCatalogVersion, error) {
+	stored, err := uc.repo.GetBySystemNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("uc.repo.GetBySystemNames: %w", err)
+	}
+
+	return uc.recordVersion(ctx, stored, user)
+}
+
+func (uc *useCase) GetCommands(ctx context.Context, locale string) (map[string]entity.Command, error) {
 	commands, err := uc.repo.GetBySystemNames(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("CommandUseCase - GetCommands - uc.repo.GetBySystemNames: %w", err)
 	}
 
+	for systemName, cmd := range commands {
+		cmd.Localize(locale)
+		commands[systemName] = cmd
+	}
+
 	return commands, nil
 }
+
+func (uc *useCase) GetCommand(ctx context.Context, systemName, locale string) (*entity.Command, error) {
+	command, err := uc.repo.GetBySystemName(ctx, systemName)
+	if err != nil {
+		return nil, fmt.Errorf("CommandUseCase - GetCommand - uc.repo.GetBySystemName: %w", err)
+	}
+	command.Localize(locale)
+
+	return command, nil
+}
+
+// PatchCommand - adjusts the timing and volumes of a single command outside of a catalog import,
+// the result is recorded as a new catalog version.
+func (uc *useCase) PatchCommand(ctx context.Context, inp entity.PatchCommandInput) (*entity.Command, error) {
+	op := "CommandUseCase - PatchCommand"
+
+	if err := inp.Validate(); err != nil {
+		return nil, fmt.Errorf("%s - inp.Validate: %w", op, err)
+	}
+
+	var command *entity.Command
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		stored, err := uc.repo.GetBySystemName(txCtx, inp.SystemName)
+		if err != nil {
+			return fmt.Errorf("uc.repo.GetBySystemName: %w", err)
+		}
+
+		if command, err = inp.Apply(*stored); err != nil {
+			return fmt.Errorf("inp.Apply: %w", err)
+		}
+
+		if err := uc.repo.Update(txCtx, command); err != nil {
+			return fmt.Errorf("uc.repo.Update: %w", err)
+		}
+
+		_, err = uc.recordStored(txCtx, inp.User)
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	return command, nil
+}
+
+// DisableCommand - disables or enables the command, the result is recorded as a new catalog version.
+func (uc *useCase) DisableCommand(ctx context.Context, user *entity.UserInfoToken, systemName string, disabled bool) error {
+	op := "CommandUseCase - DisableCommand"
+
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		if err := uc.repo.Disable(txCtx, systemName, disabled); err != nil {
+			return fmt.Errorf("uc.repo.Disable: %w", err)
+		}
+
+		_, err := uc.recordStored(txCtx, user)
+		return err
+	}); err != nil {
+		return fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	return nil
+}
@@ -2,7 +2,6 @@ package command
 
 import (
 	"context"
-	"encoding/json"
 	"os"
 	"test_go/config"
 
@@ -13,6 +12,7 @@ import (
 )
 
 type useCaseMongo struct {
+	catalogVersions
 	repo        repo.CommandMongoRepo
 	jsonStorage config.LocalFileStorage
 	l           logger.Interface
@@ -20,13 +20,15 @@ type useCaseMongo struct {
 
 func NewMongo(
 	repo repo.CommandMongoRepo,
+	catalogRepo repo.CommandCatalogRepo,
 	jsonStorage config.LocalFileStorage,
 	l logger.Interface,
 ) *useCaseMongo {
 	return &useCaseMongo{
-		repo:        repo,
-		jsonStorage: jsonStorage,
-		l:           l,
+		catalogVersions: catalogVersions{catalogRepo: catalogRepo},
+		repo:            repo,
+		jsonStorage:     jsonStorage,
+		l:               l,
 	}
 }
 
@@ -39,40 +41,115 @@ func (uc *useCaseMongo) UpdateCommands(ctx context.Context) error {
 	}
 	defer file.Close()
 
-	var commands []entity.Command
-	if err := json.NewDecoder(file).Decode(&commands); err != nil {
-		return fmt.Errorf("%s - json.Decode: %w", op, err)
+	if _, err := uc.ImportCommands(ctx, entity.ImportCommandsInput{File: file}); err != nil {
+		return fmt.Errorf("%s - uc.ImportCommands: %w", op, err)
 	}
+	return nil
+}
+
+// ImportCommands - diffs the catalog against the stored commands and,
+// unless it is a dry run, creates the added commands, updates the changed ones,
+// disables the missing ones and records the result as the active version.
+func (uc *useCaseMongo) ImportCommands(ctx context.Context, inp entity.ImportCommandsInput) (*entity.CommandCatalogDiff, error) {
+	op := "CommandUseCase - ImportCommands"
 
-	existing, err := uc.repo.GetAll(ctx)
+	commands, err := entity.ParseCommandCatalog(inp.File)
 	if err != nil {
-		return fmt.Errorf("%s - repo.GetAll: %w", op, err)
+		return nil, fmt.Errorf("%s - entity.ParseCommandCatalog: %w", op, err)
 	}
 
-	existingMap := make(map[string]entity.Command)
-	for _, cmd := range existing {
-		existingMap[cmd.SystemName] = cmd
+	existing, err := uc.repo.GetBySystemNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s - repo.GetBySystemNames: %w", op, err)
 	}
 
-	for i := range commands {
-		cmd := &commands[i]
+	diff := entity.DiffCommandCatalog(existing, commands)
+	diff.DryRun = inp.DryRun
+	if inp.DryRun {
+		return diff, nil
+	}
+
+	if err := uc.applyDiff(ctx, diff); err != nil {
+		return nil, fmt.Errorf("%s - %w", op, err)
+	}
+
+	for _, systemName := range diff.MissingEnabled() {
+		if err := uc.repo.Disable(ctx, systemName, true); err != nil {
+			return nil, fmt.Errorf("%s - repo.Disable: %w", op, err)
+		}
+	}
+
+	if diff.Version, err = uc.recordStored(ctx, inp.User); err != nil {
+		return nil, fmt.Errorf("%s - %w", op, err)
+	}
+
+	diff.Applied = true
+	return diff, nil
+}
+
+// RollbackCatalog - syncs the commands and their disabled flags back to a stored version,
+// disables the ones the version does not have and makes it the active one.
+func (uc *useCaseMongo) RollbackCatalog(ctx context.Context, id int64) (*entity.CommandCatalogDiff, error) {
+	op := "CommandUseCase - RollbackCatalog"
+
+	version, err := uc.catalogRepo.GetById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.catalogRepo.GetById: %w", op, err)
+	}
+
+	existing, err := uc.repo.GetBySystemNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s - repo.GetBySystemNames: %w", op, err)
+	}
+
+	diff := entity.DiffCommandCatalog(existing, version.CatalogCommands())
+	if err := uc.applyDiff(ctx, diff); err != nil {
+		return nil, fmt.Errorf("%s - %w", op, err)
+	}
+
+	for systemName, disabled := range version.DisabledChanges(existing) {
+		if err := uc.repo.Disable(ctx, systemName, disabled); err != nil {
+			return nil, fmt.Errorf("%s - repo.Disable: %w", op, err)
+		}
+	}
+
+	if err := uc.catalogRepo.Activate(ctx, version.ID); err != nil {
+		return nil, fmt.Errorf("%s - uc.catalogRepo.Activate: %w", op, err)
+	}
+	version.Active = true
+
+	diff.Version = version.Summary()
+	diff.Applied = true
+	return diff, nil
+}
+
+func (uc *useCaseMongo) applyDiff(ctx context.Context, diff *entity.CommandCatalogDiff) error {
+	for i := range diff.Added {
+		if _, err := uc.repo.Create(ctx, &diff.Added[i]); err != nil {
+			return fmt.Errorf("repo.Create: %w", err)
+		}
+	}
 
-		if ex, ok := existingMap[cmd.SystemName]; ok {
-			cmd.ID = ex.ID
-			if err := uc.repo.Update(ctx, cmd.SystemName, cmd); err != nil {
-				return fmt.Errorf("%s - repo.Update: %w", op, err)
-			}
-		} else {
-			if _, err := uc.repo.Create(ctx, cmd); err != nil {
-				return fmt.Errorf("%s - repo.Create: %w", op, err)
-			}
+	for _, change := range diff.Changed {
+		if err := uc.repo.Update(ctx, change.SystemName, &change.Command); err != nil {
+			return fmt.Errorf("repo.Update: %w", err)
 		}
 	}
 
 	return nil
 }
 
-func (uc *useCaseMongo) GetCommands(ctx context.Context) (map[string]entity.Command, error) {
+// recordStored - records the stored commands as the active catalog version.
+func (uc *useCaseMongo) recordStored(ctx context.Context, user *entity.UserInfoToken) (*entity.CommandCatalogVersion, error) {
+	stored, err := uc.repo.GetBySystemNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repo.GetBySystemNames: %w", err)
+	}
+
+	return uc.recordVersion(ctx, stored, user)
+}
+
+func (uc *useCaseMongo) GetCommands(ctx context.Context, locale string) (map[string]entity.Command, error) {
 	commands, err := uc.repo.GetAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("CommandUseCase - GetCommands - repo.GetAll: %w", err)
@@ -80,8 +157,64 @@ func (uc *useCaseMongo) GetCommands(ctx context.Context) (map[string]entity.Comm
 
 	result := make(map[string]entity.Command)
 	for _, cmd := range commands {
+		cmd.Localize(locale)
 		result[cmd.SystemName] = cmd
 	}
 
 	return result, nil
 }
+
+func (uc *useCaseMongo) GetCommand(ctx context.Context, systemName, locale string) (*entity.Command, error) {
+	command, err := uc.repo.GetBySystemName(ctx, systemName)
+	if err != nil {
+		return nil, fmt.Errorf("CommandUseCase - GetCommand - repo.GetBySystemName: %w", err)
+	}
+	command.Localize(locale)
+
+	return command, nil
+}
+
+// PatchCommand - adjusts the timing and volumes of a single command outside of a catalog import,
+// the result is recorded as a new catalog version.
+func (uc *useCaseMongo) PatchCommand(ctx context.Context, inp entity.PatchCommandInput) (*entity.Command, error) {
+	op := "CommandUseCase - PatchCommand"
+
+	if err := inp.Validate(); err != nil {
+		return nil, fmt.Errorf("%s - inp.Validate: %w", op, err)
+	}
+
+	stored, err := uc.repo.GetBySystemName(ctx, inp.SystemName)
+	if err != nil {
+		return nil, fmt.Errorf("%s - repo.GetBySystemName: %w", op, err)
+	}
+
+	command, err := inp.Apply(*stored)
+	if err != nil {
+		return nil, fmt.Errorf("%s - inp.Apply: %w", op, err)
+	}
+
+	if err := uc.repo.Update(ctx, inp.SystemName, command); err != nil {
+		return nil, fmt.Errorf("%s - repo.Update: %w", op, err)
+	}
+
+	if _, err := uc.recordStored(ctx, inp.User); err != nil {
+		return nil, fmt.Errorf("%s - %w", op, err)
+	}
+
+	return command, nil
+}
+
+// DisableCommand - disables or enables the command, the result is recorded as a new catalog version.
+func (uc *useCaseMongo) DisableCommand(ctx context.Context, user *entity.UserInfoToken, systemName string, disabled bool) error {
+	op := "CommandUseCase - DisableCommand"
+
+	if err := uc.repo.Disable(ctx, systemName, disabled); err != nil {
+		return fmt.Errorf("%s - repo.Disable: %w", op, err)
+	}
+
+	if _, err := uc.recordStored(ctx, user); err != nil {
+		return fmt.Errorf("%s - %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/Alice00021/test_common/pkg/logger"
+	"os"
+	"sync"
+	"test_go/config"
+	"test_go/internal/entity"
+	"test_go/internal/usecase"
+	"time"
+)
+
+// watcher - reloads the catalog file when its content changes.
+type watcher struct {
+	cUc         usecase.Command
+	jsonStorage config.LocalFileStorage
+	l           logger.Interface
+
+	mu     sync.Mutex
+	status entity.CommandCatalogWatchStatus
+	// rejected - the checksum of a file that failed validation, it is not retried until the content changes.
+	rejected string
+}
+
+func NewWatcher(
+	cUc usecase.Command,
+	jsonStorage config.LocalFileStorage,
+	enabled bool,
+	l logger.Interface,
+) *watcher {
+	w := &watcher{
+		cUc:         cUc,
+		jsonStorage: jsonStorage,
+		l:           l,
+		status: entity.CommandCatalogWatchStatus{
+			Enabled: enabled,
+			Path:    jsonStorage.JsonPath,
+		},
+	}
+
+	// the file found at startup counts as applied, importing it again would undo the commands
+	// patched, disabled or uploaded since; a missing file is imported once it shows up
+	if data, err := os.ReadFile(jsonStorage.JsonPath); err == nil {
+		w.status.Checksum = checksumOf(data)
+	}
+
+	return w
+}
+
+// Check - imports the catalog file when its checksum differs from the applied one.
+// A failed reload leaves the stored catalog as it was and is reported by Status.
+func (w *watcher) Check(ctx context.Context) error {
+	op := "CommandWatcher - Check"
+
+	now := time.Now()
+	w.mu.Lock()
+	w.status.CheckedAt = &now
+	applied, rejected := w.status.Checksum, w.rejected
+	w.mu.Unlock()
+
+	data, err := os.ReadFile(w.jsonStorage.JsonPath)
+	if err != nil {
+		w.fail("", err)
+		return fmt.Errorf("%s - os.ReadFile: %w", op, err)
+	}
+
+	checksum := checksumOf(data)
+	if checksum == applied || checksum == rejected {
+		return nil
+	}
+
+	// the bytes that were read are applied, so a file replaced in between is picked up by the next check
+	if _, err := w.cUc.ImportCommands(ctx, entity.ImportCommandsInput{File: bytes.NewReader(data)}); err != nil {
+		w.fail(checksum, err)
+		if errors.Is(err, entity.ErrInvalidCommandCatalog) || errors.Is(err, entity.ErrCommandCatalogTooLarge) {
+			w.mu.Lock()
+			w.rejected = checksum
+			w.mu.Unlock()
+		}
+		return fmt.Errorf("%s - w.cUc.ImportCommands: %w", op, err)
+	}
+
+	appliedAt := time.Now()
+	w.mu.Lock()
+	w.status.Checksum = checksum
+	w.status.AppliedAt = &appliedAt
+	w.status.FailedChecksum, w.status.FailedAt, w.status.Error, w.status.Issues = "", nil, "", nil
+	w.rejected = ""
+	w.mu.Unlock()
+
+	w.l.Info("CommandWatcher - Check - catalog %s reloaded, checksum %s", w.jsonStorage.JsonPath, checksum)
+	return nil
+}
+
+func (w *watcher) Status() entity.CommandCatalogWatchStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.status
+}
+
+func checksumOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (w *watcher) fail(checksum string, err error) {
+	failedAt := time.Now()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.status.FailedChecksum = checksum
+	w.status.FailedAt = &failedAt
+	w.status.Error = err.Error()
+	w.status.Issues = nil
+
+	var report *entity.CommandCatalogReport
+	if errors.As(err, &report) {
+		w.status.Issues = report.Issues
+	}
+}
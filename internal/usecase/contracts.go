@@ -3,7 +3,6 @@ package usecase
 import (
 	"context"
 	"github.com/xuri/excelize/v2"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"mime/multipart"
 	"test_go/internal/entity"
 )
@@ -21,58 +20,142 @@ type (
 		GetUser(context.Context, int64) (*entity.User, error)
 		GetUserByName(context.Context, string) (*entity.User, error)
 		GetUsers(context.Context, entity.FilterUserInput) ([]*entity.User, error)
+		UpdateUser(context.Context, entity.UpdateUserInput) error
 		ChangePassword(context.Context, entity.ChangePasswordInput) error
 		UpdateRating(context.Context, int64, float32) error
 		SetProfilePhoto(context.Context, int64, *multipart.FileHeader) error
 	}
 
 	Author interface {
-		CreateAuthor(context.Context, entity.CreateAuthorInput) (*entity.Author, error)
-		UpdateAuthor(context.Context, entity.UpdateAuthorInput) error
+		CreateAuthor(context.Context, *entity.UserInfoToken, entity.CreateAuthorInput) (*entity.Author, error)
+		UpdateAuthor(context.Context, *entity.UserInfoToken, entity.UpdateAuthorInput) error
 		GetAuthor(context.Context, int64) (*entity.Author, error)
 		GetAuthors(context.Context) ([]*entity.Author, error)
-		DeleteAuthor(context.Context, int64) error
+		GetAuthorStats(context.Context, int64) (*entity.AuthorStats, error)
+		DeleteAuthor(context.Context, *entity.UserInfoToken, entity.DeleteAuthorInput) error
+		GetAuthorHistory(context.Context, int64) ([]*entity.Revision, error)
+		RestoreAuthorRevision(context.Context, *entity.UserInfoToken, int64, int64) (*entity.Author, error)
 	}
 
 	Book interface {
 		CreateBook(context.Context, entity.CreateBookInput) (*entity.Book, error)
-		UpdateBook(context.Context, entity.UpdateBookInput) error
+		UpdateBook(context.Context, *entity.UserInfoToken, entity.UpdateBookInput) error
 		GetBook(context.Context, int64) (*entity.Book, error)
 		GetBooks(context.Context) ([]*entity.Book, error)
-		DeleteBook(context.Context, int64) error
+		DeleteBook(context.Context, *entity.UserInfoToken, int64) error
+		SetCover(context.Context, *entity.UserInfoToken, int64, *multipart.FileHeader) (*entity.Book, error)
+		DeleteCover(context.Context, *entity.UserInfoToken, int64) error
+		GetCover(context.Context, int64, int) (*entity.CoverFile, error)
+		GetBookHistory(context.Context, int64) ([]*entity.Revision, error)
+		RestoreBookRevision(context.Context, *entity.UserInfoToken, int64, int64) (*entity.Book, error)
+	}
+
+	Shelf interface {
+		GetShelves(context.Context, int64) ([]*entity.Shelf, error)
+		CreateShelf(context.Context, entity.CreateShelfInput) (*entity.Shelf, error)
+		DeleteShelf(context.Context, int64, int64) error
+		PutBook(context.Context, entity.PutShelfBookInput) (*entity.ShelfBook, error)
+		RemoveBook(context.Context, int64, int64, int64) error
+		GetReadingSummary(context.Context, int64, int) (*entity.ReadingSummary, error)
+	}
+
+	Review interface {
+		CreateReview(context.Context, entity.CreateReviewInput) (*entity.Review, error)
+		UpdateReview(context.Context, *entity.UserInfoToken, entity.UpdateReviewInput) (*entity.Review, error)
+		DeleteReview(context.Context, *entity.UserInfoToken, int64) error
+		GetReviews(context.Context, entity.FilterReviewInput) (*entity.ReviewList, error)
+		FlagReview(context.Context, entity.FlagReviewInput) error
+		ModerateReview(context.Context, entity.ModerateReviewInput) error
+	}
+
+	Revision interface {
+		Record(context.Context, entity.RecordRevisionInput) error
+		GetHistory(context.Context, entity.RevisionEntityType, string) ([]*entity.Revision, error)
+		GetRevision(context.Context, entity.RevisionEntityType, string, int64) (*entity.Revision, error)
+	}
+
+	Trash interface {
+		GetTrash(context.Context) (*entity.Trash, error)
+		RestoreAuthor(context.Context, *entity.UserInfoToken, int64) error
+		RestoreBook(context.Context, *entity.UserInfoToken, int64) error
+		PurgeExpired(context.Context) error
+	}
+
+	Import interface {
+		ImportLibrary(context.Context, entity.ImportLibraryInput) (*entity.ImportReport, error)
 	}
 
 	Export interface {
 		GenerateExcelFile(context.Context) (*excelize.File, error)
 		SaveToFile(*excelize.File) (string, error)
-		ExportCommandsToCSV(context.Context) ([]byte, string, error)
-		ExportCommandsToPDF(context.Context) ([]byte, string, error)
-		ExportOperationsToCSV(context.Context) ([]byte, string, error)
-		ExportOperationsToPDF(context.Context) ([]byte, string, error)
+		ExportCommandsToCSV(context.Context, string) ([]byte, string, error)
+		ExportCommandsToPDF(context.Context, string) ([]byte, string, error)
+		ExportOperationsToCSV(context.Context, string) ([]byte, string, error)
+		ExportOperationsToPDF(context.Context, string) ([]byte, string, error)
 	}
 
+	// Command - the command catalog of the configured storage backend.
 	Command interface {
 		UpdateCommands(context.Context) error
-		GetCommands(context.Context) (map[string]entity.Command, error)
+		ImportCommands(context.Context, entity.ImportCommandsInput) (*entity.CommandCatalogDiff, error)
+		GetCommands(context.Context, string) (map[string]entity.Command, error)
+		GetCommand(context.Context, string, string) (*entity.Command, error)
+		PatchCommand(context.Context, entity.PatchCommandInput) (*entity.Command, error)
+		DisableCommand(context.Context, *entity.UserInfoToken, string, bool) error
+		GetCatalogVersions(context.Context) ([]*entity.CommandCatalogVersion, error)
+		GetCatalogVersion(context.Context, int64) (*entity.CommandCatalogVersion, error)
+		CompareCatalogVersions(context.Context, int64, int64) (*entity.CommandCatalogDiff, error)
+		RollbackCatalog(context.Context, int64) (*entity.CommandCatalogDiff, error)
+	}
+
+	CommandWatcher interface {
+		Check(context.Context) error
+		Status() entity.CommandCatalogWatchStatus
+	}
+
+	DeckProfile interface {
+		CreateDeckProfile(context.Context, entity.CreateDeckProfileInput) (*entity.DeckProfile, error)
+		GetDeckProfiles(context.Context) ([]*entity.DeckProfile, error)
+		GetDeckProfile(context.Context, int64) (*entity.DeckProfile, error)
+		UpdateDeckProfile(context.Context, entity.UpdateDeckProfileInput) (*entity.DeckProfile, error)
+		SetDefaultDeckProfile(context.Context, int64) error
+		DeleteDeckProfile(context.Context, int64) error
+		GetOperationDeck(context.Context, *int64) (*entity.DeckProfile, error)
 	}
 
+	// Operation - operations of the configured storage backend, ids are opaque strings.
 	Operation interface {
-		CreateOperation(context.Context, entity.CreateOperationInput) (*entity.Operation, error)
-		GetOperations(context.Context) (map[int64]*entity.Operation, error)
-		GetOperation(context.Context, int64) (*entity.Operation, error)
-		UpdateOperation(context.Context, entity.UpdateOperationInput) error
-		DeleteOperation(context.Context, int64) error
+		CreateOperation(context.Context, *entity.UserInfoToken, entity.CreateOperationInput) (*entity.Operation, error)
+		GetOperations(context.Context, string) ([]*entity.Operation, error)
+		GetOperation(context.Context, string, string) (*entity.Operation, error)
+		UpdateOperation(context.Context, *entity.UserInfoToken, entity.UpdateOperationInput) error
+		ValidateOperation(context.Context, entity.ValidateOperationInput) (*entity.OperationValidation, error)
+		DeleteOperation(context.Context, *entity.UserInfoToken, string) error
+		GetOperationHistory(context.Context, string) ([]*entity.Revision, error)
+		RestoreOperationRevision(context.Context, *entity.UserInfoToken, string, int64) (*entity.Operation, error)
 	}
 
-	CommandMongo interface {
-		UpdateCommands(context.Context) error
-		GetCommands(context.Context) (map[string]entity.Command, error)
+	// OperationRun - runs of operations, each instrument executes one at a time in the order of its queue.
+	OperationRun interface {
+		StartRun(context.Context, *entity.UserInfoToken, entity.StartOperationRunInput) (*entity.OperationRun, error)
+		GetRuns(context.Context, entity.OperationRunFilter) ([]*entity.OperationRun, error)
+		GetRun(context.Context, int64) (*entity.OperationRun, error)
+		PauseRun(context.Context, int64) (*entity.OperationRun, error)
+		ResumeRun(context.Context, int64) (*entity.OperationRun, error)
+		AbortRun(context.Context, int64) (*entity.OperationRun, error)
+		Subscribe(context.Context, int64, int64) (<-chan *entity.OperationRunEvent, error)
+		GetQueue(context.Context, int64) (*entity.InstrumentQueue, error)
+		ReorderQueue(context.Context, entity.ReorderQueueInput) (*entity.InstrumentQueue, error)
+		Process(context.Context) error
+	}
+
+	Instrument interface {
+		CreateInstrument(context.Context, entity.CreateInstrumentInput) (*entity.Instrument, error)
+		GetInstruments(context.Context) ([]*entity.Instrument, error)
+		GetInstrument(context.Context, int64) (*entity.Instrument, error)
 	}
 
-	OperationMongo interface {
-		CreateOperation(context.Context, entity.CreateOperationInput) (*entity.OperationMongo, error)
-		UpdateOperation(context.Context, entity.UpdateOperationInputMongo) error
-		GetOperations(context.Context) ([]*entity.OperationMongo, error)
-		DeleteOperation(context.Context, primitive.ObjectID) error
+	StoreMigration interface {
+		Migrate(context.Context, entity.StoreMigrationInput) (*entity.StoreMigrationReport, error)
 	}
 )
@@ -0,0 +1,199 @@
+package deckprofile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Alice00021/test_common/pkg/logger"
+	"github.com/Alice00021/test_common/pkg/transactional"
+	"test_go/internal/entity"
+	"test_go/internal/repo"
+)
+
+// useCase - deck profiles are kept in postgres whichever storage holds the operations.
+type useCase struct {
+	transactional.Transactional
+	repo repo.DeckProfileRepo
+	// refRepo - nil when the operations are kept in postgres, where the foreign key refuses the delete.
+	refRepo repo.DeckProfileRefRepo
+	l       logger.Interface
+}
+
+func New(t transactional.Transactional, repo repo.DeckProfileRepo, refRepo repo.DeckProfileRefRepo, l logger.Interface) *useCase {
+	return &useCase{
+		Transactional: t,
+		repo:          repo,
+		refRepo:       refRepo,
+		l:             l,
+	}
+}
+
+func (uc *useCase) CreateDeckProfile(ctx context.Context, inp entity.CreateDeckProfileInput) (*entity.DeckProfile, error) {
+	op := "DeckProfileUseCase - CreateDeckProfile"
+
+	e := &entity.DeckProfile{Name: inp.Name, Positions: inp.Positions}
+	e.Normalize()
+	if err := e.Validate(); err != nil {
+		return nil, fmt.Errorf("%s - e.Validate: %w", op, err)
+	}
+
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		if err := uc.checkNameIsFree(txCtx, e); err != nil {
+			return err
+		}
+
+		if err := uc.repo.Create(txCtx, e); err != nil {
+			return fmt.Errorf("uc.repo.Create: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	return e, nil
+}
+
+func (uc *useCase) GetDeckProfiles(ctx context.Context) ([]*entity.DeckProfile, error) {
+	profiles, err := uc.repo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("DeckProfileUseCase - GetDeckProfiles - uc.repo.GetAll: %w", err)
+	}
+
+	return profiles, nil
+}
+
+func (uc *useCase) GetDeckProfile(ctx context.Context, id int64) (*entity.DeckProfile, error) {
+	profile, err := uc.repo.GetById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("DeckProfileUseCase - GetDeckProfile - uc.repo.GetById: %w", err)
+	}
+
+	return profile, nil
+}
+
+// UpdateDeckProfile - applies a merge patch. Operations already on the profile are not
+// revalidated, the new layout applies to them from their next update.
+func (uc *useCase) UpdateDeckProfile(ctx context.Context, inp entity.UpdateDeckProfileInput) (*entity.DeckProfile, error) {
+	op := "DeckProfileUseCase - UpdateDeckProfile"
+
+	if err := inp.Validate(); err != nil {
+		return nil, fmt.Errorf("%s - inp.Validate: %w", op, err)
+	}
+
+	var profile *entity.DeckProfile
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		e, err := uc.repo.GetById(txCtx, inp.ID)
+		if err != nil {
+			return fmt.Errorf("uc.repo.GetById: %w", err)
+		}
+
+		inp.Name.Apply(&e.Name)
+		inp.Positions.Apply(&e.Positions)
+		e.Normalize()
+		if err := e.Validate(); err != nil {
+			return fmt.Errorf("e.Validate: %w", err)
+		}
+
+		if err := uc.checkNameIsFree(txCtx, e); err != nil {
+			return err
+		}
+
+		if err := uc.repo.Update(txCtx, e); err != nil {
+			return fmt.Errorf("uc.repo.Update: %w", err)
+		}
+
+		profile = e
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	return profile, nil
+}
+
+// SetDefaultDeckProfile - the profile operations without one are validated against from now on.
+func (uc *useCase) SetDefaultDeckProfile(ctx context.Context, id int64) error {
+	op := "DeckProfileUseCase - SetDefaultDeckProfile"
+
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		if err := uc.repo.SetDefault(txCtx, id); err != nil {
+			return fmt.Errorf("uc.repo.SetDefault: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteDeckProfile - the default profile has to be replaced and a profile in use
+// has to be cleared from its operations and instruments before it can be deleted.
+func (uc *useCase) DeleteDeckProfile(ctx context.Context, id int64) error {
+	op := "DeckProfileUseCase - DeleteDeckProfile"
+
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		e, err := uc.repo.GetById(txCtx, id)
+		if err != nil {
+			return fmt.Errorf("uc.repo.GetById: %w", err)
+		}
+		if e.Default {
+			return entity.ErrDeckProfileIsDefault
+		}
+
+		if uc.refRepo != nil {
+			inUse, err := uc.refRepo.HasDeckProfile(txCtx, id)
+			if err != nil {
+				return fmt.Errorf("uc.refRepo.HasDeckProfile: %w", err)
+			}
+			if inUse {
+				return entity.ErrDeckProfileInUse
+			}
+		}
+
+		if err := uc.repo.DeleteById(txCtx, id); err != nil {
+			return fmt.Errorf("uc.repo.DeleteById: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetOperationDeck - the deck an operation referencing the profile is validated against:
+// the profile itself, else the stored default, else the built-in standard layout.
+func (uc *useCase) GetOperationDeck(ctx context.Context, id *int64) (*entity.DeckProfile, error) {
+	op := "DeckProfileUseCase - GetOperationDeck"
+
+	if id != nil {
+		profile, err := uc.repo.GetById(ctx, *id)
+		if err != nil {
+			return nil, fmt.Errorf("%s - uc.repo.GetById: %w", op, err)
+		}
+		return profile, nil
+	}
+
+	profile, err := uc.repo.GetDefault(ctx)
+	if err != nil {
+		if errors.Is(err, entity.ErrDeckProfileNotFound) {
+			return entity.StandardDeckProfile(), nil
+		}
+		return nil, fmt.Errorf("%s - uc.repo.GetDefault: %w", op, err)
+	}
+
+	return profile, nil
+}
+
+// checkNameIsFree - names are unique regardless of case.
+func (uc *useCase) checkNameIsFree(ctx context.Context, e *entity.DeckProfile) error {
+	existing, err := uc.repo.GetByName(ctx, e.Name)
+	switch {
+	case err == nil && existing.ID != e.ID:
+		return entity.ErrDeckProfileAlreadyExists
+	case err != nil && !errors.Is(err, entity.ErrDeckProfileNotFound):
+		return fmt.Errorf("uc.repo.GetByName: %w", err)
+	}
+	return nil
+}
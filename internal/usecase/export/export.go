@@ -18,7 +18,6 @@ import (
 
 type useCase struct {
 	aUc        usecase.Author
-	bUc        usecase.Book
 	cUc        usecase.Command
 	opUc       usecase.Operation
 	l          logger.Interface
@@ -27,7 +26,6 @@ type useCase struct {
 
 func New(
 	aUc usecase.Author,
-	bUc usecase.Book,
 	cUc usecase.Command,
 	opUc usecase.Operation,
 	l logger.Interface,
@@ -37,7 +35,7 @@ func New(
 		return nil
 	}
 	return &useCase{
-		aUc, bUc, cUc, opUc, l, exportPath,
+		aUc, cUc, opUc, l, exportPath,
 	}
 }
 
@@ -47,15 +45,6 @@ func (uc *useCase) GenerateExcelFile(ctx context.Context) (*excelize.File, error
 		return nil, fmt.Errorf("ExportUseCase - GenerateExcelFile - uc.auс.GetAuthors: %w", err)
 	}
 
-	books, err := uc.bUc.GetBooks(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("ExportUseCase - GenerateExcelFile - uc.buc.GetBooks: %w", err)
-	}
-
-	bookCount := make(map[int64]int)
-	for _, book := range books {
-		bookCount[book.AuthorId]++
-	}
 	f := excelize.NewFile()
 
 	sheetName := "Authors"
@@ -80,13 +69,8 @@ func (uc *useCase) GenerateExcelFile(ctx context.Context) (*excelize.File, error
 			gender = "Мужской"
 		}
 		f.SetCellValue(sheetName, "C"+strconv.Itoa(row), gender)
-		f.SetCellValue(sheetName, "D"+strconv.Itoa(row), bookCount[author.ID])
-
-		status := "Начинающий писатель"
-		if bookCount[author.ID] > 5 {
-			status = "Профессионал"
-		}
-		f.SetCellValue(sheetName, "E"+strconv.Itoa(row), status)
+		f.SetCellValue(sheetName, "D"+strconv.Itoa(row), author.Stats.BookCount)
+		f.SetCellValue(sheetName, "E"+strconv.Itoa(row), author.Stats.Status.Title())
 	}
 	lastRow := len(authors) + 2
 	f.SetCellValue(sheetName, "A"+strconv.Itoa(lastRow), "Всего книг: ")
@@ -105,8 +89,8 @@ func (uc *useCase) SaveToFile(f *excelize.File) (string, error) {
 	return fileName, nil
 }
 
-func (uc *useCase) ExportCommandsToCSV(ctx context.Context) ([]byte, string, error) {
-	commands, err := uc.cUc.GetCommands(ctx)
+func (uc *useCase) ExportCommandsToCSV(ctx context.Context, locale string) ([]byte, string, error) {
+	commands, err := uc.cUc.GetCommands(ctx, locale)
 	if err != nil {
 		return nil, "", fmt.Errorf("ExportUseCase - ExportCommandsToCSV - uc.cUc.GetCommands: %w", err)
 	}
@@ -149,8 +133,8 @@ func (uc *useCase) ExportCommandsToCSV(ctx context.Context) ([]byte, string, err
 	return buf.Bytes(), fileName, nil
 }
 
-func (uc *useCase) ExportCommandsToPDF(ctx context.Context) ([]byte, string, error) {
-	commands, err := uc.cUc.GetCommands(ctx)
+func (uc *useCase) ExportCommandsToPDF(ctx context.Context, locale string) ([]byte, string, error) {
+	commands, err := uc.cUc.GetCommands(ctx, locale)
 	if err != nil {
 		return nil, "", fmt.Errorf("ExportUseCase - ExportCommandsToPDF - uc.cUc.GetCommands: %w", err)
 	}
@@ -201,8 +185,8 @@ func (uc *useCase) ExportCommandsToPDF(ctx context.Context) ([]byte, string, err
 	return buf.Bytes(), fileName, nil
 }
 
-func (uc *useCase) ExportOperationsToCSV(ctx context.Context) ([]byte, string, error) {
-	operations, err := uc.opUc.GetOperations(ctx)
+func (uc *useCase) ExportOperationsToCSV(ctx context.Context, locale string) ([]byte, string, error) {
+	operations, err := uc.opUc.GetOperations(ctx, locale)
 	if err != nil {
 		return nil, "", fmt.Errorf("ExportUseCase - ExportOperationsToCSV - uc.opUc.GetOperations: %w", err)
 	}
@@ -223,7 +207,7 @@ func (uc *useCase) ExportOperationsToCSV(ctx context.Context) ([]byte, string, e
 
 		if len(op.Commands) == 0 {
 			record := []string{
-				strconv.FormatInt(op.ID, 10),
+				op.ID,
 				op.Name,
 				op.Description,
 				strconv.FormatInt(op.AverageTime, 10),
@@ -238,7 +222,7 @@ func (uc *useCase) ExportOperationsToCSV(ctx context.Context) ([]byte, string, e
 			c := oc.Command
 
 			record := []string{
-				strconv.FormatInt(op.ID, 10),
+				op.ID,
 				op.Name,
 				op.Description,
 				strconv.FormatInt(op.AverageTime, 10),
@@ -270,8 +254,8 @@ func (uc *useCase) ExportOperationsToCSV(ctx context.Context) ([]byte, string, e
 	return buf.Bytes(), fileName, nil
 }
 
-func (uc *useCase) ExportOperationsToPDF(ctx context.Context) ([]byte, string, error) {
-	operations, err := uc.opUc.GetOperations(ctx)
+func (uc *useCase) ExportOperationsToPDF(ctx context.Context, locale string) ([]byte, string, error) {
+	operations, err := uc.opUc.GetOperations(ctx, locale)
 	if err != nil {
 		return nil, "", fmt.Errorf("ExportUseCase - ExportOperationsToPDF - uc.opUc.GetOperations: %w", err)
 	}
@@ -289,7 +273,7 @@ func (uc *useCase) ExportOperationsToPDF(ctx context.Context) ([]byte, string, e
 
 	for _, op := range operations {
 		pdf.SetFont("DejaVuBold", "B", 12)
-		pdf.Cell(0, 8, fmt.Sprintf("Операция №%d: %s", op.ID, op.Name))
+		pdf.Cell(0, 8, fmt.Sprintf("Операция №%s: %s", op.ID, op.Name))
 		pdf.Ln(8)
 
 		pdf.SetFont("DejaVuBold", "B", 10)
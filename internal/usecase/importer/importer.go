@@ -0,0 +1,260 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Alice00021/test_common/pkg/logger"
+	"github.com/Alice00021/test_common/pkg/transactional"
+	"maps"
+	"strings"
+	"test_go/internal/entity"
+	"test_go/internal/repo"
+	"test_go/internal/usecase"
+)
+
+// errRollback - returned from a transaction to discard its changes without failing the import.
+var errRollback = errors.New("rollback")
+
+type useCase struct {
+	transactional.Transactional
+	authorRepo repo.AuthorRepo
+	bookRepo   repo.BookRepo
+	revisionUc usecase.Revision
+	l          logger.Interface
+}
+
+func New(t transactional.Transactional,
+	authorRepo repo.AuthorRepo,
+	bookRepo repo.BookRepo,
+	revisionUc usecase.Revision,
+	l logger.Interface,
+) *useCase {
+	return &useCase{
+		Transactional: t,
+		authorRepo:    authorRepo,
+		bookRepo:      bookRepo,
+		revisionUc:    revisionUc,
+		l:             l,
+	}
+}
+
+func (uc *useCase) ImportLibrary(ctx context.Context, inp entity.ImportLibraryInput) (*entity.ImportReport, error) {
+	op := "ImportUseCase - ImportLibrary"
+
+	if inp.Mode == "" {
+		inp.Mode = entity.ImportModeAtomic
+	}
+	if !inp.Mode.IsValid() {
+		return nil, fmt.Errorf("%s: %w", op, entity.ErrInvalidImportMode)
+	}
+
+	records, err := readRecords(inp.File)
+	if err != nil {
+		return nil, fmt.Errorf("%s - readRecords: %w", op, err)
+	}
+
+	rows, err := parseRows(records, inp.Mapping)
+	if err != nil {
+		return nil, fmt.Errorf("%s - parseRows: %w", op, err)
+	}
+
+	report := &entity.ImportReport{
+		Mode:   inp.Mode,
+		DryRun: inp.DryRun,
+		Rows:   make([]*entity.ImportRowResult, 0, len(rows)),
+	}
+
+	if inp.Mode == entity.ImportModeBestEffort && !inp.DryRun {
+		uc.importBestEffort(ctx, inp.User, rows, report)
+		report.Committed = report.Created+report.Updated > 0
+		return report, nil
+	}
+
+	err = uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		uc.importInOneTransaction(txCtx, inp.User, rows, report)
+
+		if inp.DryRun || (inp.Mode == entity.ImportModeAtomic && report.Failed > 0) {
+			return errRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errRollback) {
+		return nil, fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+	report.Committed = err == nil
+
+	return report, nil
+}
+
+// importInOneTransaction - imports all rows in the transaction from ctx.
+// After a database error the transaction is unusable, so the remaining rows are skipped.
+func (uc *useCase) importInOneTransaction(ctx context.Context, user *entity.UserInfoToken, rows []*row, report *entity.ImportReport) {
+	authors := make(map[string]int64)
+
+	for i, r := range rows {
+		res, err := uc.importRow(ctx, user, authors, r)
+		report.Add(res)
+
+		if err != nil {
+			uc.l.Error(err, "ImportUseCase - importRow")
+			for _, rest := range rows[i+1:] {
+				report.Add(&entity.ImportRowResult{
+					Row:     rest.line,
+					Status:  entity.ImportRowStatusSkipped,
+					Message: "import aborted after a database error",
+				})
+			}
+			return
+		}
+	}
+}
+
+// importBestEffort - imports every row in its own transaction.
+func (uc *useCase) importBestEffort(ctx context.Context, user *entity.UserInfoToken, rows []*row, report *entity.ImportReport) {
+	authors := make(map[string]int64)
+
+	for _, r := range rows {
+		var res *entity.ImportRowResult
+		// authors created in a rolled back transaction must not be reused
+		rowAuthors := maps.Clone(authors)
+
+		err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+			var err error
+			res, err = uc.importRow(txCtx, user, rowAuthors, r)
+			if err != nil {
+				return err
+			}
+			if res.Status == entity.ImportRowStatusFailed {
+				return errRollback
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errRollback) {
+			uc.l.Error(err, "ImportUseCase - importRow")
+			res = failed(r.line, "database error")
+		}
+		if err == nil {
+			authors = rowAuthors
+		}
+
+		report.Add(res)
+	}
+}
+
+// importRow - returns a non-nil error only for database failures, invalid rows are reported in the result.
+func (uc *useCase) importRow(ctx context.Context, user *entity.UserInfoToken, authors map[string]int64, r *row) (*entity.ImportRowResult, error) {
+	if r.err != nil {
+		return failed(r.line, r.err.Error()), nil
+	}
+
+	authorID, err := uc.resolveAuthor(ctx, user, authors, r)
+	if err != nil {
+		return failed(r.line, "database error"), err
+	}
+
+	res := &entity.ImportRowResult{Row: r.line, AuthorID: &authorID}
+
+	if r.bookID != nil {
+		book, err := uc.bookRepo.GetById(ctx, *r.bookID)
+		if err != nil {
+			if errors.Is(err, entity.ErrBookNotFound) {
+				return failed(r.line, entity.ErrBookNotFound.Error()), nil
+			}
+			return failed(r.line, "database error"), err
+		}
+
+		if !book.CanBeModifiedBy(user) {
+			return failed(r.line, entity.ErrAccessDenied.Error()), nil
+		}
+
+		res.BookID = &book.ID
+		if book.Title == r.title && book.AuthorId == authorID {
+			res.Status = entity.ImportRowStatusSkipped
+			res.Message = "no changes"
+			return res, nil
+		}
+
+		prev := *book
+		book.Title = r.title
+		book.AuthorId = authorID
+		if err := uc.bookRepo.Update(ctx, book); err != nil {
+			return failed(r.line, "database error"), err
+		}
+		if err := uc.record(ctx, entity.BookRevision(user, book.ID, entity.RevisionActionUpdate, &prev, book)); err != nil {
+			return failed(r.line, "database error"), err
+		}
+
+		res.Status = entity.ImportRowStatusUpdated
+		return res, nil
+	}
+
+	existing, err := uc.bookRepo.GetByTitleAndAuthor(ctx, r.title, authorID)
+	if err == nil {
+		res.BookID = &existing.ID
+		res.Status = entity.ImportRowStatusSkipped
+		res.Message = "book already exists"
+		return res, nil
+	}
+	if !errors.Is(err, entity.ErrBookNotFound) {
+		return failed(r.line, "database error"), err
+	}
+
+	book, err := uc.bookRepo.Create(ctx, entity.NewBook(r.title, authorID, user.ID))
+	if err != nil {
+		return failed(r.line, "database error"), err
+	}
+	if err := uc.record(ctx, entity.BookRevision(user, book.ID, entity.RevisionActionCreate, nil, book)); err != nil {
+		return failed(r.line, "database error"), err
+	}
+
+	res.BookID = &book.ID
+	res.Status = entity.ImportRowStatusCreated
+	return res, nil
+}
+
+// resolveAuthor - finds the author by name or creates a new one.
+func (uc *useCase) resolveAuthor(ctx context.Context, user *entity.UserInfoToken, authors map[string]int64, r *row) (int64, error) {
+	key := strings.ToLower(r.author)
+	if id, ok := authors[key]; ok {
+		return id, nil
+	}
+
+	author, err := uc.authorRepo.GetByName(ctx, r.author)
+	if err != nil {
+		if !errors.Is(err, entity.ErrAuthorNotFound) {
+			return 0, fmt.Errorf("uc.authorRepo.GetByName: %w", err)
+		}
+
+		gender := false
+		if r.gender != nil {
+			gender = *r.gender
+		}
+
+		author, err = uc.authorRepo.Create(ctx, entity.NewAuthor(r.author, gender))
+		if err != nil {
+			return 0, fmt.Errorf("uc.authorRepo.Create: %w", err)
+		}
+		if err := uc.record(ctx, entity.AuthorRevision(user, author.ID, entity.RevisionActionCreate, nil, author)); err != nil {
+			return 0, err
+		}
+	}
+
+	authors[key] = author.ID
+	return author.ID, nil
+}
+
+func (uc *useCase) record(ctx context.Context, inp entity.RecordRevisionInput) error {
+	if err := uc.revisionUc.Record(ctx, inp); err != nil {
+		return fmt.Errorf("uc.revisionUc.Record: %w", err)
+	}
+	return nil
+}
+
+func failed(line int, message string) *entity.ImportRowResult {
+	return &entity.ImportRowResult{
+		Row:     line,
+		Status:  entity.ImportRowStatusFailed,
+		Message: message,
+	}
+}
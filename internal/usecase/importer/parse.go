@@ -0,0 +1,236 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"github.com/xuri/excelize/v2"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"test_go/internal/entity"
+	"unicode/utf8"
+)
+
+// maxFieldLength - size of the name/title columns in the database.
+const maxFieldLength = 100
+
+type row struct {
+	line   int
+	bookID *int64
+	title  string
+	author string
+	gender *bool
+	err    error
+}
+
+// readRecords - the file is rejected before it is parsed when it is over entity.ImportMaxSize,
+// and after that when it has more than entity.ImportMaxRows rows.
+func readRecords(file *multipart.FileHeader) ([][]string, error) {
+	if file.Size > entity.ImportMaxSize {
+		return nil, entity.ErrImportFileTooLarge
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, entity.ErrOpenFile
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, entity.ImportMaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("io.ReadAll: %w", err)
+	}
+	if len(data) > entity.ImportMaxSize {
+		return nil, entity.ErrImportFileTooLarge
+	}
+
+	var records [][]string
+	switch strings.ToLower(filepath.Ext(file.Filename)) {
+	case ".csv":
+		records, err = readCSV(data)
+	case ".xlsx":
+		records, err = readXLSX(data)
+	default:
+		return nil, entity.ErrUnsupportedImportFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(records) > entity.ImportMaxRows+1 {
+		return nil, entity.ErrTooManyImportRows
+	}
+
+	return records, nil
+}
+
+func readCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	// spreadsheets exported with a non-English locale use ';' as a separator
+	header, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("reader.ReadAll: %w", err)
+	}
+
+	return records, nil
+}
+
+// readXLSX - the sheet is read row by row, so that a small file with a huge sheet
+// is rejected without loading all of it.
+func readXLSX(data []byte) ([][]string, error) {
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("excelize.OpenReader: %w", err)
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, entity.ErrEmptyImportFile
+	}
+
+	rows, err := f.Rows(sheets[0])
+	if err != nil {
+		return nil, fmt.Errorf("f.Rows: %w", err)
+	}
+	defer rows.Close()
+
+	var records [][]string
+	for rows.Next() {
+		if len(records) > entity.ImportMaxRows {
+			return nil, entity.ErrTooManyImportRows
+		}
+
+		record, err := rows.Columns()
+		if err != nil {
+			return nil, fmt.Errorf("rows.Columns: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Error(); err != nil {
+		return nil, fmt.Errorf("rows.Error: %w", err)
+	}
+
+	return records, nil
+}
+
+// parseRows - maps records to rows by the header, the first record is the header.
+func parseRows(records [][]string, mapping entity.ImportColumnMapping) ([]*row, error) {
+	if len(records) < 2 {
+		return nil, entity.ErrEmptyImportFile
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	column := func(name string, required bool) (int, error) {
+		i, ok := columns[strings.ToLower(strings.TrimSpace(name))]
+		if !ok || name == "" {
+			if required {
+				return -1, fmt.Errorf("%w: %q", entity.ErrImportColumnNotFound, name)
+			}
+			return -1, nil
+		}
+		return i, nil
+	}
+
+	titleCol, err := column(mapping.Title, true)
+	if err != nil {
+		return nil, err
+	}
+	authorCol, err := column(mapping.Author, true)
+	if err != nil {
+		return nil, err
+	}
+	bookIDCol, _ := column(mapping.BookID, false)
+	genderCol, _ := column(mapping.Gender, false)
+
+	rows := make([]*row, 0, len(records)-1)
+	for i, record := range records[1:] {
+		if isEmptyRecord(record) {
+			continue
+		}
+
+		r := &row{
+			line:   i + 2,
+			title:  cell(record, titleCol),
+			author: cell(record, authorCol),
+		}
+		r.err = r.fill(cell(record, bookIDCol), cell(record, genderCol))
+		rows = append(rows, r)
+	}
+
+	return rows, nil
+}
+
+func (r *row) fill(bookID, gender string) error {
+	switch {
+	case r.title == "":
+		return fmt.Errorf("title is required")
+	case r.author == "":
+		return fmt.Errorf("author is required")
+	case utf8.RuneCountInString(r.title) > maxFieldLength:
+		return fmt.Errorf("title is longer than %d characters", maxFieldLength)
+	case utf8.RuneCountInString(r.author) > maxFieldLength:
+		return fmt.Errorf("author is longer than %d characters", maxFieldLength)
+	}
+
+	if bookID != "" {
+		id, err := strconv.ParseInt(bookID, 10, 64)
+		if err != nil || id <= 0 {
+			return fmt.Errorf("invalid book id %q", bookID)
+		}
+		r.bookID = &id
+	}
+
+	if gender != "" {
+		g, err := parseGender(gender)
+		if err != nil {
+			return err
+		}
+		r.gender = &g
+	}
+
+	return nil
+}
+
+// parseGender - true is male, the same as entity.Author.Gender.
+func parseGender(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "m", "male", "true", "1", "м", "муж", "мужской":
+		return true, nil
+	case "f", "female", "false", "0", "ж", "жен", "женский":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid gender %q", s)
+}
+
+func cell(record []string, i int) string {
+	if i < 0 || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+func isEmptyRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
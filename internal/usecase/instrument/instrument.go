@@ -0,0 +1,77 @@
+package instrument
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Alice00021/test_common/pkg/logger"
+	"github.com/Alice00021/test_common/pkg/transactional"
+	"strings"
+	"test_go/internal/entity"
+	"test_go/internal/repo"
+	"test_go/internal/usecase"
+)
+
+type useCase struct {
+	transactional.Transactional
+	repo   repo.InstrumentRepo
+	deckUc usecase.DeckProfile
+	l      logger.Interface
+}
+
+func New(t transactional.Transactional, repo repo.InstrumentRepo, deckUc usecase.DeckProfile, l logger.Interface) *useCase {
+	return &useCase{
+		Transactional: t,
+		repo:          repo,
+		deckUc:        deckUc,
+		l:             l,
+	}
+}
+
+func (uc *useCase) CreateInstrument(ctx context.Context, inp entity.CreateInstrumentInput) (*entity.Instrument, error) {
+	op := "InstrumentUseCase - CreateInstrument"
+
+	e := &entity.Instrument{Name: strings.TrimSpace(inp.Name), DeckProfileID: inp.DeckProfileID}
+	if e.DeckProfileID != nil {
+		if _, err := uc.deckUc.GetDeckProfile(ctx, *e.DeckProfileID); err != nil {
+			return nil, fmt.Errorf("%s - uc.deckUc.GetDeckProfile: %w", op, err)
+		}
+	}
+
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		_, err := uc.repo.GetByName(txCtx, e.Name)
+		switch {
+		case err == nil:
+			return entity.ErrInstrumentAlreadyExists
+		case !errors.Is(err, entity.ErrInstrumentNotFound):
+			return fmt.Errorf("uc.repo.GetByName: %w", err)
+		}
+
+		if err := uc.repo.Create(txCtx, e); err != nil {
+			return fmt.Errorf("uc.repo.Create: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	return e, nil
+}
+
+func (uc *useCase) GetInstruments(ctx context.Context) ([]*entity.Instrument, error) {
+	instruments, err := uc.repo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("InstrumentUseCase - GetInstruments - uc.repo.GetAll: %w", err)
+	}
+
+	return instruments, nil
+}
+
+func (uc *useCase) GetInstrument(ctx context.Context, id int64) (*entity.Instrument, error) {
+	instrument, err := uc.repo.GetById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("InstrumentUseCase - GetInstrument - uc.repo.GetById: %w", err)
+	}
+
+	return instrument, nil
+}
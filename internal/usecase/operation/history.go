@@ -0,0 +1,82 @@
+package operation
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"test_go/internal/entity"
+)
+
+func (uc *useCase) GetOperationHistory(ctx context.Context, id string) ([]*entity.Revision, error) {
+	op := "OperationUseCase - GetOperationHistory"
+
+	operationId, err := parseOperationID(id)
+	if err != nil {
+		return nil, fmt.Errorf("%s - %w", op, err)
+	}
+
+	items, err := uc.revisionUc.GetHistory(ctx, entity.RevisionEntityOperation, strconv.FormatInt(operationId, 10))
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.revisionUc.GetHistory: %w", op, err)
+	}
+
+	return items, nil
+}
+
+// RestoreOperationRevision - rebuilds the operation from the commands recorded by the revision,
+// so the restored operation passes the same container checks as a regular update.
+func (uc *useCase) RestoreOperationRevision(ctx context.Context, user *entity.UserInfoToken,
+	id string, revisionId int64,
+) (*entity.Operation, error) {
+	op := "OperationUseCase - RestoreOperationRevision"
+
+	operationId, err := parseOperationID(id)
+	if err != nil {
+		return nil, fmt.Errorf("%s - %w", op, err)
+	}
+
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		rev, err := uc.revisionUc.GetRevision(txCtx, entity.RevisionEntityOperation, strconv.FormatInt(operationId, 10), revisionId)
+		if err != nil {
+			return fmt.Errorf("uc.revisionUc.GetRevision: %w", err)
+		}
+
+		var snapshot entity.OperationSnapshot
+		if err := rev.RestoreInto(&snapshot); err != nil {
+			return fmt.Errorf("rev.RestoreInto: %w", err)
+		}
+
+		return uc.update(txCtx, user, snapshot.UpdateInput(id), entity.RevisionActionRestore)
+	}); err != nil {
+		return nil, fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	res, err := uc.opRepo.GetById(ctx, operationId)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.opRepo.GetById: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (uc *useCase) recordRevision(ctx context.Context, user *entity.UserInfoToken, id int64,
+	action entity.RevisionAction, before, after *entity.Operation,
+) error {
+	inp := entity.RecordRevisionInput{
+		EntityType: entity.RevisionEntityOperation,
+		EntityID:   strconv.FormatInt(id, 10),
+		Action:     action,
+		ActorID:    entity.ActorOf(user),
+	}
+	if before != nil {
+		inp.Before = before.Snapshot()
+	}
+	if after != nil {
+		inp.After = after.Snapshot()
+	}
+
+	if err := uc.revisionUc.Record(ctx, inp); err != nil {
+		return fmt.Errorf("uc.revisionUc.Record: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package operation
+
+import (
+	"context"
+	"fmt"
+	"test_go/internal/entity"
+)
+
+func (uc *useCaseMongo) GetOperationHistory(ctx context.Context, id string) ([]*entity.Revision, error) {
+	op := "OperationUseCase - GetOperationHistory"
+
+	objectID, err := parseObjectID(id)
+	if err != nil {
+		return nil, fmt.Errorf("%s - %w", op, err)
+	}
+
+	items, err := uc.revisionUc.GetHistory(ctx, entity.RevisionEntityOperation, objectID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.revisionUc.GetHistory: %w", op, err)
+	}
+
+	return items, nil
+}
+
+// RestoreOperationRevision - rebuilds the operation from the commands recorded by the revision,
+// so the restored operation passes the same container checks as a regular update.
+func (uc *useCaseMongo) RestoreOperationRevision(ctx context.Context, user *entity.UserInfoToken,
+	id string, revisionId int64,
+) (*entity.Operation, error) {
+	op := "OperationUseCase - RestoreOperationRevision"
+
+	objectID, err := parseObjectID(id)
+	if err != nil {
+		return nil, fmt.Errorf("%s - %w", op, err)
+	}
+
+	rev, err := uc.revisionUc.GetRevision(ctx, entity.RevisionEntityOperation, objectID.Hex(), revisionId)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.revisionUc.GetRevision: %w", op, err)
+	}
+
+	var snapshot entity.OperationSnapshot
+	if err := rev.RestoreInto(&snapshot); err != nil {
+		return nil, fmt.Errorf("%s - rev.RestoreInto: %w", op, err)
+	}
+
+	if err := uc.update(ctx, user, snapshot.UpdateInput(id), entity.RevisionActionRestore); err != nil {
+		return nil, fmt.Errorf("%s - %w", op, err)
+	}
+
+	res, err := uc.opRepo.GetById(ctx, objectID)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.opRepo.GetById: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (uc *useCaseMongo) recordRevision(ctx context.Context, user *entity.UserInfoToken, id string,
+	action entity.RevisionAction, before, after *entity.Operation,
+) error {
+	inp := entity.RecordRevisionInput{
+		EntityType: entity.RevisionEntityOperation,
+		EntityID:   id,
+		Action:     action,
+		ActorID:    entity.ActorOf(user),
+	}
+	if before != nil {
+		inp.Before = before.Snapshot()
+	}
+	if after != nil {
+		inp.After = after.Snapshot()
+	}
+
+	if err := uc.revisionUc.Record(ctx, inp); err != nil {
+		return fmt.Errorf("uc.revisionUc.Record: %w", err)
+	}
+	return nil
+}
@@ -2,19 +2,25 @@ package operation
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/Alice00021/test_common/pkg/logger"
 	"github.com/Alice00021/test_common/pkg/transactional"
+	"strconv"
 	"test_go/internal/entity"
 	"test_go/internal/repo"
+	"test_go/internal/usecase"
 )
 
 type useCase struct {
 	transactional.Transactional
-	opRepo  repo.OperationRepo
-	opcRepo repo.OperationCommandsRepo
-	cRepo   repo.CommandRepo
-	l       logger.Interface
+	opRepo      repo.OperationRepo
+	opcRepo     repo.OperationCommandsRepo
+	cRepo       repo.CommandRepo
+	catalogRepo repo.CommandCatalogRepo
+	deckUc      usecase.DeckProfile
+	revisionUc  usecase.Revision
+	l           logger.Interface
 }
 
 func New(
@@ -22,6 +28,9 @@ func New(
 	opRepo repo.OperationRepo,
 	opCmdRepo repo.OperationCommandsRepo,
 	cmdRepo repo.CommandRepo,
+	catalogRepo repo.CommandCatalogRepo,
+	deckUc usecase.DeckProfile,
+	revisionUc usecase.Revision,
 	l logger.Interface,
 ) *useCase {
 	return &useCase{
@@ -29,75 +38,56 @@ func New(
 		opRepo:        opRepo,
 		opcRepo:       opCmdRepo,
 		cRepo:         cmdRepo,
+		catalogRepo:   catalogRepo,
+		deckUc:        deckUc,
+		revisionUc:    revisionUc,
 		l:             l,
 	}
 }
 
-func (uc *useCase) CreateOperation(ctx context.Context, inp entity.CreateOperationInput) (*entity.Operation, error) {
+func (uc *useCase) CreateOperation(ctx context.Context, user *entity.UserInfoToken, inp entity.CreateOperationInput) (*entity.Operation, error) {
 	op := "OperationUseCase - CreateOperation"
 
 	var operation entity.Operation
 	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
 		e := &entity.Operation{
-			Name:        inp.Name,
-			Description: inp.Description,
-			Commands:    []*entity.OperationCommand{},
+			Name:          inp.Name,
+			Description:   inp.Description,
+			Commands:      []*entity.OperationCommand{},
+			DeckProfileID: inp.DeckProfileID,
 		}
 
-		mapCommands, err := uc.cRepo.GetBySystemNames(txCtx)
+		plan, err := uc.buildCommands(txCtx, e.DeckProfileID, inp.Commands)
 		if err != nil {
-			return fmt.Errorf("%s - uc.cRepo.GetBySystemNames: %w", op, err)
+			return fmt.Errorf("%s - uc.buildCommands: %w", op, err)
 		}
+		e.AverageTime = plan.AverageTime
 
-		var operationCommands []*entity.OperationCommand
-		mapContainer := make(map[entity.Address]entity.Container)
-		var totalTime int64
-
-		for _, commandInput := range inp.Commands {
-			command, ok := mapCommands[commandInput.SystemName]
-			if !ok {
-				return entity.ErrCommandNotFound
-			}
-
-			container, ok := mapContainer[commandInput.Address]
-			if !ok {
-				container = entity.Container{
-					Address:     commandInput.Address,
-					ReagentType: command.Reagent,
-					Volume:      command.VolumeContainer,
-				}
-			} else if container.ReagentType != command.Reagent {
-				return entity.ErrCommandDuplicateAddress
-			} else {
-				container.Volume += command.VolumeContainer
-			}
-			if !container.IsValidVolume() {
-				return entity.ErrCommandVolumeExceeded
-			}
-			mapContainer[commandInput.Address] = container
-
-			operationCommand := &entity.OperationCommand{
-				Command: command,
-				Address: commandInput.Address,
-			}
-
-			operationCommands = append(operationCommands, operationCommand)
-			totalTime += command.AverageTime
+		if e.CatalogVersionID, err = activeCatalogVersion(txCtx, uc.catalogRepo); err != nil {
+			return fmt.Errorf("%s - %w", op, err)
 		}
-		e.AverageTime = totalTime
 
 		res, err := uc.opRepo.Create(txCtx, e)
 		if err != nil {
 			return fmt.Errorf("%s - uc.opRepo.Create: %w", op, err)
 		}
 
-		if err := uc.opcRepo.Create(txCtx, res.ID, operationCommands); err != nil {
+		id, err := parseOperationID(res.ID)
+		if err != nil {
+			return fmt.Errorf("%s - %w", op, err)
+		}
+		if err := uc.opcRepo.Create(txCtx, id, plan.Commands); err != nil {
 			return fmt.Errorf("%s - uc.opсRepo.Create: %w", op, err)
 		}
 
 		operation = *res
-		operation.Commands = operationCommands
+		operation.Commands = plan.Commands
 
+		if err := uc.recordRevision(txCtx, user, id, entity.RevisionActionCreate, nil, &operation); err != nil {
+			return err
+		}
+
+		operation.Assignments = plan.Assignments
 		return nil
 	}); err != nil {
 		return nil, fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
@@ -106,123 +96,240 @@ func (uc *useCase) CreateOperation(ctx context.Context, inp entity.CreateOperati
 	return &operation, nil
 }
 
-func (uc *useCase) UpdateOperation(ctx context.Context, inp entity.UpdateOperationInput) error {
+// UpdateOperation - applies a merge patch, the container checks run only when
+// the commands are replaced or the operation moves to another deck.
+func (uc *useCase) UpdateOperation(ctx context.Context, user *entity.UserInfoToken, inp entity.UpdateOperationInput) error {
 	op := "OperationUseCase - UpdateOperation"
 
-	return uc.RunInTransaction(ctx, func(txCtx context.Context) error {
-		mapCommands, err := uc.cRepo.GetBySystemNames(txCtx)
-		if err != nil {
-			return fmt.Errorf("%s - uc.cRepo.GetBySystemNames: %w", op, err)
-		}
-
-		var (
-			operationCommands []*entity.OperationCommand
-			mapContainer      = make(map[entity.Address]entity.Container)
-			totalTime         int64
-			commandsToCreate  []*entity.OperationCommand
-			idsToKeep         []int64
-		)
+	if err := inp.Validate(); err != nil {
+		return fmt.Errorf("%s - inp.Validate: %w", op, err)
+	}
 
-		for _, commandInput := range inp.Commands {
-			command, ok := mapCommands[commandInput.SystemName]
-			if !ok {
-				return entity.ErrCommandNotFound
-			}
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		return uc.update(txCtx, user, inp, entity.RevisionActionUpdate)
+	}); err != nil {
+		return fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
 
-			container, ok := mapContainer[commandInput.Address]
-			if !ok {
-				container = entity.Container{
-					Address:     commandInput.Address,
-					ReagentType: command.Reagent,
-					Volume:      command.VolumeContainer,
-				}
-			} else if container.ReagentType != command.Reagent {
-				return entity.ErrCommandNotFound
-			} else {
-				container.Volume += command.VolumeContainer
-			}
+	return nil
+}
 
-			if !container.IsValidVolume() {
-				return entity.ErrCommandVolumeExceeded
-			}
-			mapContainer[commandInput.Address] = container
+// update - patches the operation and records the change under the given action,
+// it has to run inside a transaction.
+func (uc *useCase) update(ctx context.Context, user *entity.UserInfoToken,
+	inp entity.UpdateOperationInput, action entity.RevisionAction,
+) error {
+	id, err := parseOperationID(inp.ID)
+	if err != nil {
+		return err
+	}
 
-			operationCommand := &entity.OperationCommand{
-				ID:          0,
-				OperationID: inp.ID,
-				Command:     command,
-				Address:     commandInput.Address,
-			}
+	prev, err := uc.opRepo.GetById(ctx, id)
+	if err != nil {
+		return fmt.Errorf("uc.opRepo.GetById: %w", err)
+	}
 
-			operationCommands = append(operationCommands, operationCommand)
-			totalTime += command.AverageTime
-
-			// Если команда уже существует — обновляем
-			if commandInput.ID != nil {
-				operationCommand.ID = *commandInput.ID
-				if err := uc.opcRepo.Update(txCtx, operationCommand); err != nil {
-					return fmt.Errorf("%s - uc.opcRepo.Update: %w", op, err)
-				}
-				idsToKeep = append(idsToKeep, *commandInput.ID)
-			} else {
-				commandsToCreate = append(commandsToCreate, operationCommand)
-			}
+	operation := &entity.Operation{ID: inp.ID, DeckProfileID: prev.DeckProfileID}
+	inp.DeckProfileID.Apply(&operation.DeckProfileID)
+	switch {
+	case inp.Commands.Present:
+		if operation.AverageTime, err = uc.replaceCommands(ctx, id, operation.DeckProfileID, inp.Commands.Get()); err != nil {
+			return fmt.Errorf("uc.replaceCommands: %w", err)
 		}
 
-		if err := uc.opcRepo.DeleteIfNotInOperationCommandIds(txCtx, inp.ID, idsToKeep); err != nil {
-			return fmt.Errorf("%s - uc.opcRepo.DeleteExceptIDs: %w", op, err)
+		if operation.CatalogVersionID, err = activeCatalogVersion(ctx, uc.catalogRepo); err != nil {
+			return err
+		}
+	case inp.DeckProfileID.Present:
+		// The commands stay as they are but have to fit the new deck.
+		if _, err = uc.buildCommands(ctx, operation.DeckProfileID, prev.Snapshot().CommandInputs()); err != nil {
+			return fmt.Errorf("uc.buildCommands: %w", err)
 		}
+	}
+
+	if err := uc.opRepo.Patch(ctx, id, operation, inp); err != nil {
+		return fmt.Errorf("uc.opRepo.Patch: %w", err)
+	}
+
+	next, err := uc.opRepo.GetById(ctx, id)
+	if err != nil {
+		return fmt.Errorf("uc.opRepo.GetById: %w", err)
+	}
+
+	return uc.recordRevision(ctx, user, id, action, prev, next)
+}
+
+// buildCommands - resolves the command inputs against the catalog
+// and places them on the deck of the operation, picking the addresses left empty.
+func (uc *useCase) buildCommands(ctx context.Context, deckProfileID *int64, inputs []*entity.CommandInput) (*entity.OperationPlan, error) {
+	deck, err := uc.deckUc.GetOperationDeck(ctx, deckProfileID)
+	if err != nil {
+		return nil, fmt.Errorf("uc.deckUc.GetOperationDeck: %w", err)
+	}
+
+	mapCommands, err := uc.cRepo.GetBySystemNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("uc.cRepo.GetBySystemNames: %w", err)
+	}
+
+	plan, err := entity.ValidateOperationCommands(deck, mapCommands, inputs)
+	if err != nil {
+		return nil, fmt.Errorf("entity.ValidateOperationCommands: %w", err)
+	}
+
+	return plan, nil
+}
+
+// replaceCommands - syncs the commands of the operation with the inputs,
+// returns the average time of the new command set.
+func (uc *useCase) replaceCommands(ctx context.Context, operationID int64, deckProfileID *int64, inputs []*entity.CommandInput) (int64, error) {
+	plan, err := uc.buildCommands(ctx, deckProfileID, inputs)
+	if err != nil {
+		return 0, fmt.Errorf("uc.buildCommands: %w", err)
+	}
 
-		if len(commandsToCreate) > 0 {
-			if err := uc.opcRepo.Create(txCtx, inp.ID, commandsToCreate); err != nil {
-				return fmt.Errorf("%s - uc.opcRepo.Create: %w", op, err)
+	var (
+		commandsToCreate []*entity.OperationCommand
+		idsToKeep        []int64
+	)
+
+	for i, commandInput := range inputs {
+		operationCommand := plan.Commands[i]
+		operationCommand.OperationID = operationID
+
+		// Если команда уже существует — обновляем
+		if commandInput.ID != nil {
+			operationCommand.ID = *commandInput.ID
+			if err := uc.opcRepo.Update(ctx, operationCommand); err != nil {
+				return 0, fmt.Errorf("uc.opcRepo.Update: %w", err)
 			}
+			idsToKeep = append(idsToKeep, *commandInput.ID)
+		} else {
+			commandsToCreate = append(commandsToCreate, operationCommand)
 		}
+	}
 
-		operation := &entity.Operation{
-			Entity:      entity.Entity{ID: inp.ID},
-			Name:        inp.Name,
-			Description: inp.Description,
-			AverageTime: totalTime,
-		}
+	if err := uc.opcRepo.DeleteIfNotInOperationCommandIds(ctx, operationID, idsToKeep); err != nil {
+		return 0, fmt.Errorf("uc.opcRepo.DeleteExceptIDs: %w", err)
+	}
 
-		if err := uc.opRepo.Update(txCtx, operation); err != nil {
-			return fmt.Errorf("%s - uc.opRepo.Update: %w", op, err)
+	if len(commandsToCreate) > 0 {
+		if err := uc.opcRepo.Create(ctx, operationID, commandsToCreate); err != nil {
+			return 0, fmt.Errorf("uc.opcRepo.Create: %w", err)
 		}
+	}
 
-		return nil
-	})
+	return plan.AverageTime, nil
+}
+
+// ValidateOperation - a dry run of saving an operation with the commands, nothing is stored.
+func (uc *useCase) ValidateOperation(ctx context.Context, inp entity.ValidateOperationInput) (*entity.OperationValidation, error) {
+	op := "OperationUseCase - ValidateOperation"
+
+	deck, err := uc.deckUc.GetOperationDeck(ctx, inp.DeckProfileID)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.deckUc.GetOperationDeck: %w", op, err)
+	}
+
+	catalog, err := uc.cRepo.GetBySystemNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.cRepo.GetBySystemNames: %w", op, err)
+	}
+
+	return entity.CheckOperationCommands(deck, catalog, inp.Commands), nil
 }
 
-func (uc *useCase) GetOperations(ctx context.Context) (map[int64]*entity.Operation, error) {
+func (uc *useCase) GetOperations(ctx context.Context, locale string) ([]*entity.Operation, error) {
+	op := "OperationUseCase - GetOperations"
+
 	operations, err := uc.opRepo.GetAll(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("OperationUseCase - GetOperations - uc.opRepo.GetAll: %w", err)
+		return nil, fmt.Errorf("%s - uc.opRepo.GetAll: %w", op, err)
+	}
+
+	catalog, err := uc.cRepo.GetBySystemNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.cRepo.GetBySystemNames: %w", op, err)
+	}
+
+	for _, operation := range operations {
+		entity.LocalizeOperationCommands(operation.Commands, catalog, locale)
 	}
 
 	return operations, nil
 }
 
-func (uc *useCase) GetOperation(ctx context.Context, id int64) (*entity.Operation, error) {
-	book, err := uc.opRepo.GetById(ctx, id)
+func (uc *useCase) GetOperation(ctx context.Context, id string, locale string) (*entity.Operation, error) {
+	op := "OperationUseCase - GetOperation"
+
+	operationId, err := parseOperationID(id)
+	if err != nil {
+		return nil, fmt.Errorf("%s - %w", op, err)
+	}
+
+	operation, err := uc.opRepo.GetById(ctx, operationId)
 	if err != nil {
-		return nil, fmt.Errorf("OperationUseCase - GetOperation - uc.opRepo.GetById: %w", err)
+		return nil, fmt.Errorf("%s - uc.opRepo.GetById: %w", op, err)
 	}
 
-	return book, nil
+	catalog, err := uc.cRepo.GetBySystemNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.cRepo.GetBySystemNames: %w", op, err)
+	}
+	entity.LocalizeOperationCommands(operation.Commands, catalog, locale)
+
+	return operation, nil
 }
 
-func (uc *useCase) DeleteOperation(ctx context.Context, id int64) error {
+func (uc *useCase) DeleteOperation(ctx context.Context, user *entity.UserInfoToken, id string) error {
 	op := "OperationUseCase - DeleteOperation"
 
-	return uc.RunInTransaction(ctx, func(txCtx context.Context) error {
-		if err := uc.opRepo.DeleteById(txCtx, id); err != nil {
-			return fmt.Errorf("%s - uc.opRepo.DeleteById: %w", op, err)
+	operationId, err := parseOperationID(id)
+	if err != nil {
+		return fmt.Errorf("%s - %w", op, err)
+	}
+
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		prev, err := uc.opRepo.GetById(txCtx, operationId)
+		if err != nil {
+			return fmt.Errorf("uc.opRepo.GetById: %w", err)
 		}
 
-		if err := uc.opcRepo.DeleteByOperationId(txCtx, id); err != nil {
-			return fmt.Errorf("%s - uc.opсRepo.DeleteByOperationId: %w", op, err)
+		if err := uc.opRepo.DeleteById(txCtx, operationId); err != nil {
+			return fmt.Errorf("uc.opRepo.DeleteById: %w", err)
 		}
-		return nil
-	})
+
+		if err := uc.opcRepo.DeleteByOperationId(txCtx, operationId); err != nil {
+			return fmt.Errorf("uc.opсRepo.DeleteByOperationId: %w", err)
+		}
+
+		return uc.recordRevision(txCtx, user, operationId, entity.RevisionActionDelete, prev, nil)
+	}); err != nil {
+		return fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// parseOperationID - postgres ids are decimal numbers, any other id cannot name an operation.
+func parseOperationID(id string) (int64, error) {
+	operationId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, entity.ErrOperationNotFound
+	}
+	return operationId, nil
+}
+
+// activeCatalogVersion - the id of the catalog version the commands are resolved against,
+// nil until the first catalog is imported.
+func activeCatalogVersion(ctx context.Context, catalogRepo repo.CommandCatalogRepo) (*int64, error) {
+	version, err := catalogRepo.GetActive(ctx)
+	if err != nil {
+		if errors.Is(err, entity.ErrCommandCatalogVersionNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("catalogRepo.GetActive: %w", err)
+	}
+
+	return &version.ID, nil
 }
@@ -7,73 +7,55 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"test_go/internal/entity"
 	"test_go/internal/repo"
+	"test_go/internal/usecase"
 )
 
 type useCaseMongo struct {
-	opRepo repo.OperationMongoRepo
-	cRepo  repo.CommandMongoRepo
-	l      logger.Interface
+	opRepo      repo.OperationMongoRepo
+	cRepo       repo.CommandMongoRepo
+	catalogRepo repo.CommandCatalogRepo
+	deckUc      usecase.DeckProfile
+	revisionUc  usecase.Revision
+	l           logger.Interface
 }
 
 func NewMongo(
 	opRepo repo.OperationMongoRepo,
 	cRepo repo.CommandMongoRepo,
+	catalogRepo repo.CommandCatalogRepo,
+	deckUc usecase.DeckProfile,
+	revisionUc usecase.Revision,
 	l logger.Interface,
 ) *useCaseMongo {
 	return &useCaseMongo{
-		opRepo: opRepo,
-		cRepo:  cRepo,
-		l:      l,
+		opRepo:      opRepo,
+		cRepo:       cRepo,
+		catalogRepo: catalogRepo,
+		deckUc:      deckUc,
+		revisionUc:  revisionUc,
+		l:           l,
 	}
 }
-func (uc *useCaseMongo) CreateOperation(ctx context.Context, inp entity.CreateOperationInput) (*entity.OperationMongo, error) {
+func (uc *useCaseMongo) CreateOperation(ctx context.Context, user *entity.UserInfoToken, inp entity.CreateOperationInput) (*entity.Operation, error) {
 	op := "OperationUseCase - CreateOperation"
 
-	mapCommands, err := uc.cRepo.GetBySystemName(ctx)
+	plan, err := uc.buildCommands(ctx, inp.DeckProfileID, inp.Commands)
 	if err != nil {
-		return nil, fmt.Errorf("%s - uc.cRepo.GetBySystemNames: %w", op, err)
+		return nil, fmt.Errorf("%s - uc.buildCommands: %w", op, err)
 	}
 
-	mapContainer := make(map[entity.Address]entity.Container)
-	var operationCommands []*entity.OperationCommand
-	var totalTime int64
-
-	for _, commandInput := range inp.Commands {
-		command, ok := mapCommands[commandInput.SystemName]
-		if !ok {
-			return nil, entity.ErrCommandNotFound
-		}
-
-		container, ok := mapContainer[commandInput.Address]
-		if !ok {
-			container = entity.Container{
-				Address:     commandInput.Address,
-				ReagentType: command.Reagent,
-				Volume:      command.VolumeContainer,
-			}
-		} else if container.ReagentType != command.Reagent {
-			return nil, entity.ErrCommandDuplicateAddress
-		} else {
-			container.Volume += command.VolumeContainer
-		}
-
-		if !container.IsValidVolume() {
-			return nil, entity.ErrCommandVolumeExceeded
-		}
-		mapContainer[commandInput.Address] = container
-
-		operationCommands = append(operationCommands, &entity.OperationCommand{
-			Command: command,
-			Address: commandInput.Address,
-		})
-		totalTime += command.AverageTime
+	catalogVersionID, err := activeCatalogVersion(ctx, uc.catalogRepo)
+	if err != nil {
+		return nil, fmt.Errorf("%s - %w", op, err)
 	}
 
-	opDoc := &entity.OperationMongo{
-		Name:        inp.Name,
-		Description: inp.Description,
-		AverageTime: totalTime,
-		Commands:    operationCommands,
+	opDoc := &entity.Operation{
+		Name:             inp.Name,
+		Description:      inp.Description,
+		AverageTime:      plan.AverageTime,
+		Commands:         plan.Commands,
+		CatalogVersionID: catalogVersionID,
+		DeckProfileID:    inp.DeckProfileID,
 	}
 
 	res, err := uc.opRepo.Create(ctx, opDoc)
@@ -81,81 +63,181 @@ func (uc *useCaseMongo) CreateOperation(ctx context.Context, inp entity.CreateOp
 		return nil, fmt.Errorf("%s - uc.opRepo.Create: %w", op, err)
 	}
 
+	if err := uc.recordRevision(ctx, user, res.ID, entity.RevisionActionCreate, nil, res); err != nil {
+		return nil, fmt.Errorf("%s - %w", op, err)
+	}
+
+	res.Assignments = plan.Assignments
 	return res, nil
 }
 
-func (uc *useCaseMongo) UpdateOperation(ctx context.Context, inp entity.UpdateOperationInputMongo) error {
+// UpdateOperation - applies a merge patch, the container checks run only when
+// the commands are replaced or the operation moves to another deck.
+func (uc *useCaseMongo) UpdateOperation(ctx context.Context, user *entity.UserInfoToken, inp entity.UpdateOperationInput) error {
 	op := "OperationUseCase - UpdateOperation"
 
-	mapCommands, err := uc.cRepo.GetBySystemName(ctx)
+	if err := inp.Validate(); err != nil {
+		return fmt.Errorf("%s - inp.Validate: %w", op, err)
+	}
+
+	if err := uc.update(ctx, user, inp, entity.RevisionActionUpdate); err != nil {
+		return fmt.Errorf("%s - %w", op, err)
+	}
+
+	return nil
+}
+
+// update - patches the operation and records the change under the given action.
+func (uc *useCaseMongo) update(ctx context.Context, user *entity.UserInfoToken,
+	inp entity.UpdateOperationInput, action entity.RevisionAction,
+) error {
+	id, err := parseObjectID(inp.ID)
 	if err != nil {
-		return fmt.Errorf("%s - uc.cRepo.GetBySystemName: %w", op, err)
+		return err
 	}
 
-	mapContainer := make(map[entity.Address]entity.Container)
-	var operationCommands []*entity.OperationCommand
-	var totalTime int64
+	prev, err := uc.opRepo.GetById(ctx, id)
+	if err != nil {
+		return fmt.Errorf("uc.opRepo.GetById: %w", err)
+	}
 
-	for _, commandInput := range inp.Commands {
-		command, ok := mapCommands[commandInput.SystemName]
-		if !ok {
-			return entity.ErrCommandNotFound
+	operation := *prev
+	inp.Name.Apply(&operation.Name)
+	inp.Description.Apply(&operation.Description)
+	inp.DeckProfileID.Apply(&operation.DeckProfileID)
+	switch {
+	case inp.Commands.Present:
+		plan, err := uc.buildCommands(ctx, operation.DeckProfileID, inp.Commands.Get())
+		if err != nil {
+			return fmt.Errorf("uc.buildCommands: %w", err)
 		}
+		operation.Commands, operation.AverageTime = plan.Commands, plan.AverageTime
 
-		container, ok := mapContainer[commandInput.Address]
-		if !ok {
-			container = entity.Container{
-				Address:     commandInput.Address,
-				ReagentType: command.Reagent,
-				Volume:      command.VolumeContainer,
-			}
-		} else if container.ReagentType != command.Reagent {
-			return entity.ErrCommandDuplicateAddress
-		} else {
-			container.Volume += command.VolumeContainer
+		if operation.CatalogVersionID, err = activeCatalogVersion(ctx, uc.catalogRepo); err != nil {
+			return err
 		}
-
-		if !container.IsValidVolume() {
-			return entity.ErrCommandVolumeExceeded
+	case inp.DeckProfileID.Present:
+		// The commands stay as they are but have to fit the new deck.
+		if _, err = uc.buildCommands(ctx, operation.DeckProfileID, prev.Snapshot().CommandInputs()); err != nil {
+			return fmt.Errorf("uc.buildCommands: %w", err)
 		}
-		mapContainer[commandInput.Address] = container
+	}
 
-		operationCommands = append(operationCommands, &entity.OperationCommand{
-			Command: command,
-			Address: commandInput.Address,
-		})
-		totalTime += command.AverageTime
+	if err := uc.opRepo.Patch(ctx, &operation, inp); err != nil {
+		return fmt.Errorf("uc.opRepo.Patch: %w", err)
 	}
 
-	operation := &entity.OperationMongo{
-		Id:          inp.ID,
-		Name:        inp.Name,
-		Description: inp.Description,
-		AverageTime: totalTime,
-		Commands:    operationCommands,
+	return uc.recordRevision(ctx, user, prev.ID, action, prev, &operation)
+}
+
+// ValidateOperation - a dry run of saving an operation with the commands, nothing is stored.
+func (uc *useCaseMongo) ValidateOperation(ctx context.Context, inp entity.ValidateOperationInput) (*entity.OperationValidation, error) {
+	op := "OperationUseCase - ValidateOperation"
+
+	deck, err := uc.deckUc.GetOperationDeck(ctx, inp.DeckProfileID)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.deckUc.GetOperationDeck: %w", op, err)
 	}
 
-	if err := uc.opRepo.Update(ctx, operation); err != nil {
-		return fmt.Errorf("%s - uc.opRepo.Update: %w", op, err)
+	catalog, err := uc.cRepo.GetBySystemNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.cRepo.GetBySystemNames: %w", op, err)
 	}
 
-	return nil
+	return entity.CheckOperationCommands(deck, catalog, inp.Commands), nil
 }
 
-func (uc *useCaseMongo) GetOperations(ctx context.Context) ([]*entity.OperationMongo, error) {
+func (uc *useCaseMongo) GetOperations(ctx context.Context, locale string) ([]*entity.Operation, error) {
+	op := "OperationUseCase - GetOperations"
+
 	operations, err := uc.opRepo.GetAll(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("OperationUseCase - GetOperations - uc.opRepo.GetAll: %w", err)
+		return nil, fmt.Errorf("%s - uc.opRepo.GetAll: %w", op, err)
+	}
+
+	catalog, err := uc.cRepo.GetBySystemNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.cRepo.GetBySystemNames: %w", op, err)
+	}
+
+	for _, operation := range operations {
+		entity.LocalizeOperationCommands(operation.Commands, catalog, locale)
 	}
 
 	return operations, nil
 }
 
-func (uc *useCaseMongo) DeleteOperation(ctx context.Context, id primitive.ObjectID) error {
+func (uc *useCaseMongo) GetOperation(ctx context.Context, id string, locale string) (*entity.Operation, error) {
+	op := "OperationUseCase - GetOperation"
+
+	objectID, err := parseObjectID(id)
+	if err != nil {
+		return nil, fmt.Errorf("%s - %w", op, err)
+	}
+
+	operation, err := uc.opRepo.GetById(ctx, objectID)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.opRepo.GetById: %w", op, err)
+	}
+
+	catalog, err := uc.cRepo.GetBySystemNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.cRepo.GetBySystemNames: %w", op, err)
+	}
+	entity.LocalizeOperationCommands(operation.Commands, catalog, locale)
+
+	return operation, nil
+}
+
+func (uc *useCaseMongo) DeleteOperation(ctx context.Context, user *entity.UserInfoToken, id string) error {
 	op := "OperationUseCase - DeleteOperation"
 
-	if err := uc.opRepo.DeleteById(ctx, id); err != nil {
+	objectID, err := parseObjectID(id)
+	if err != nil {
+		return fmt.Errorf("%s - %w", op, err)
+	}
+
+	prev, err := uc.opRepo.GetById(ctx, objectID)
+	if err != nil {
+		return fmt.Errorf("%s - uc.opRepo.GetById: %w", op, err)
+	}
+
+	if err := uc.opRepo.DeleteById(ctx, objectID); err != nil {
 		return fmt.Errorf("%s - uc.opRepo.DeleteById: %w", op, err)
 	}
+
+	if err := uc.recordRevision(ctx, user, prev.ID, entity.RevisionActionDelete, prev, nil); err != nil {
+		return fmt.Errorf("%s - %w", op, err)
+	}
 	return nil
 }
+
+// buildCommands - resolves the command inputs against the catalog of this store
+// and places them on the deck of the operation, picking the addresses left empty.
+func (uc *useCaseMongo) buildCommands(ctx context.Context, deckProfileID *int64, inputs []*entity.CommandInput) (*entity.OperationPlan, error) {
+	deck, err := uc.deckUc.GetOperationDeck(ctx, deckProfileID)
+	if err != nil {
+		return nil, fmt.Errorf("uc.deckUc.GetOperationDeck: %w", err)
+	}
+
+	mapCommands, err := uc.cRepo.GetBySystemNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("uc.cRepo.GetBySystemNames: %w", err)
+	}
+
+	plan, err := entity.ValidateOperationCommands(deck, mapCommands, inputs)
+	if err != nil {
+		return nil, fmt.Errorf("entity.ValidateOperationCommands: %w", err)
+	}
+
+	return plan, nil
+}
+
+// parseObjectID - mongo ids are ObjectID hex strings, any other id cannot name an operation.
+func parseObjectID(id string) (primitive.ObjectID, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, entity.ErrOperationNotFound
+	}
+	return objectID, nil
+}
@@ -0,0 +1,134 @@
+package operationrun
+
+import (
+	"slices"
+	"sync"
+	"test_go/internal/entity"
+	"time"
+)
+
+const (
+	// historySize - how many events of a run are kept for the clients that resume the stream.
+	historySize = 256
+	// historyRetention - how long the events of a finished run are kept.
+	historyRetention = 10 * time.Minute
+)
+
+// eventLog - the recent events of the runs. Publishing never blocks on the subscribers:
+// they are only woken up and read the events at their own pace, a subscriber that fell
+// behind the kept events is sent a snapshot of the run instead.
+type eventLog struct {
+	mu sync.Mutex
+	// lastID - starts from the clock, so the IDs keep growing after a restart
+	// and an ID of the previous process is never taken for a newer one.
+	lastID int64
+	runs   map[int64]*runEvents
+}
+
+type runEvents struct {
+	events []*entity.OperationRunEvent
+	// baseID - the events up to it are not kept.
+	baseID     int64
+	finishedAt time.Time
+	watchers   map[chan struct{}]struct{}
+}
+
+func newEventLog() *eventLog {
+	return &eventLog{
+		lastID: time.Now().UnixMicro(),
+		runs:   make(map[int64]*runEvents),
+	}
+}
+
+// get - the events of the run, created on first use. The caller holds mu.
+func (l *eventLog) get(runID int64) *runEvents {
+	r, ok := l.runs[runID]
+	if !ok {
+		r = &runEvents{baseID: l.lastID, watchers: make(map[chan struct{}]struct{})}
+		l.runs[runID] = r
+	}
+	return r
+}
+
+func (l *eventLog) publish(events ...*entity.OperationRunEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	for _, e := range events {
+		l.lastID++
+		e.ID = l.lastID
+
+		r := l.get(e.RunID)
+		if len(r.events) == historySize {
+			r.baseID = r.events[0].ID
+			r.events = r.events[1:]
+		}
+		r.events = append(r.events, e)
+		if e.State.IsFinal() && e.Type == entity.OperationRunEventStateChanged {
+			r.finishedAt = now
+		}
+
+		for w := range r.watchers {
+			select {
+			case w <- struct{}{}:
+			default:
+			}
+		}
+	}
+
+	l.prune(now)
+}
+
+// prune - drops the events of the runs finished long ago that nobody watches. The caller holds mu.
+func (l *eventLog) prune(now time.Time) {
+	for id, r := range l.runs {
+		if len(r.watchers) == 0 && !r.finishedAt.IsZero() && now.Sub(r.finishedAt) > historyRetention {
+			delete(l.runs, id)
+		}
+	}
+}
+
+// watch - the returned channel gets a signal after the events of the run are published.
+func (l *eventLog) watch(runID int64) (<-chan struct{}, func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	w := make(chan struct{}, 1)
+	l.get(runID).watchers[w] = struct{}{}
+
+	return w, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if r, ok := l.runs[runID]; ok {
+			delete(r.watchers, w)
+			if len(r.watchers) == 0 && len(r.events) == 0 {
+				delete(l.runs, runID)
+			}
+		}
+	}
+}
+
+// since - the events of the run after lastID, ok is false when some of them are no longer kept.
+// latestID is the ID of the last event of the run, a snapshot taken now is at least as new.
+func (l *eventLog) since(runID, lastID int64) (events []*entity.OperationRunEvent, latestID int64, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	r := l.get(runID)
+	latestID = r.baseID
+	if len(r.events) > 0 {
+		latestID = r.events[len(r.events)-1].ID
+	}
+	if lastID < r.baseID {
+		return nil, latestID, false
+	}
+
+	for i, e := range r.events {
+		if e.ID > lastID {
+			return slices.Clone(r.events[i:]), latestID, true
+		}
+	}
+	return nil, latestID, true
+}
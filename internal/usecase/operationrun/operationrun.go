@@ -0,0 +1,741 @@
+package operationrun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Alice00021/test_common/pkg/logger"
+	"github.com/Alice00021/test_common/pkg/transactional"
+	"slices"
+	"sync"
+	"test_go/internal/entity"
+	"test_go/internal/repo"
+	"test_go/internal/usecase"
+	"time"
+)
+
+// retryInterval - how long a worker waits after it failed to read the queue.
+const retryInterval = 5 * time.Second
+
+// useCase - each instrument has a worker of Process that executes its runs one at a time
+// in the order of its queue, every change of a run is saved before the next step starts.
+type useCase struct {
+	transactional.Transactional
+	repo           repo.OperationRunRepo
+	instrumentRepo repo.InstrumentRepo
+	operationUc    usecase.Operation
+	commandUc      usecase.Command
+	deckUc         usecase.DeckProfile
+	executor       repo.OperationExecutor
+	// timeUnit - what a unit of AverageTime lasts, for the ETA of the runs.
+	timeUnit time.Duration
+	l        logger.Interface
+
+	events *eventLog
+
+	// wake - tells Process to start the workers of new instruments.
+	wake      chan struct{}
+	workersMu sync.Mutex
+	// workers - tells the worker of the instrument that a run was queued.
+	workers map[int64]chan struct{}
+
+	// mu - guards active and locks, the repo is never called while it is held.
+	mu     sync.Mutex
+	active map[int64]*activeRun
+	// locks - serializes the state changes of a run between its worker and the callers.
+	locks map[int64]*runLock
+}
+
+// runLock - the lock of a run, dropped once nobody refers to it.
+type runLock struct {
+	sync.Mutex
+	refs int
+}
+
+// activeRun - a run a worker is executing.
+type activeRun struct {
+	run    *entity.OperationRun
+	cancel context.CancelFunc
+	// resumed - closed when the paused run is resumed.
+	resumed chan struct{}
+}
+
+func New(
+	t transactional.Transactional,
+	repo repo.OperationRunRepo,
+	instrumentRepo repo.InstrumentRepo,
+	operationUc usecase.Operation,
+	commandUc usecase.Command,
+	deckUc usecase.DeckProfile,
+	executor repo.OperationExecutor,
+	timeUnit time.Duration,
+	l logger.Interface,
+) *useCase {
+	return &useCase{
+		Transactional:  t,
+		repo:           repo,
+		instrumentRepo: instrumentRepo,
+		operationUc:    operationUc,
+		commandUc:      commandUc,
+		deckUc:         deckUc,
+		executor:       executor,
+		timeUnit:       timeUnit,
+		l:              l,
+		events:         newEventLog(),
+		wake:           make(chan struct{}, 1),
+		workers:        make(map[int64]chan struct{}),
+		active:         make(map[int64]*activeRun),
+		locks:          make(map[int64]*runLock),
+	}
+}
+
+// StartRun - queues a run of the operation on the instrument, after the queued runs of the same
+// or a higher priority. The operation has to be planned for the deck mounted on the instrument
+// and may be queued on it only once.
+func (uc *useCase) StartRun(ctx context.Context, user *entity.UserInfoToken, inp entity.StartOperationRunInput) (*entity.OperationRun, error) {
+	op := "OperationRunUseCase - StartRun"
+
+	if err := inp.Validate(); err != nil {
+		return nil, fmt.Errorf("%s - inp.Validate: %w", op, err)
+	}
+
+	operation, err := uc.operationUc.GetOperation(ctx, inp.OperationID, entity.DefaultLocale)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.operationUc.GetOperation: %w", op, err)
+	}
+
+	catalog, err := uc.commandUc.GetCommands(ctx, entity.DefaultLocale)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.commandUc.GetCommands: %w", op, err)
+	}
+
+	run, err := entity.NewOperationRun(operation, catalog, entity.ActorOf(user))
+	if err != nil {
+		return nil, fmt.Errorf("%s - entity.NewOperationRun: %w", op, err)
+	}
+	run.InstrumentID, run.Priority = inp.InstrumentID, inp.Priority
+
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		instrument, err := uc.instrumentRepo.GetByIdForUpdate(txCtx, inp.InstrumentID)
+		if err != nil {
+			return fmt.Errorf("uc.instrumentRepo.GetByIdForUpdate: %w", err)
+		}
+		if err := uc.checkDeck(txCtx, operation, instrument); err != nil {
+			return err
+		}
+
+		queue, err := uc.repo.GetQueued(txCtx, instrument.ID)
+		if err != nil {
+			return fmt.Errorf("uc.repo.GetQueued: %w", err)
+		}
+		if i := slices.IndexFunc(queue, func(r *entity.OperationRun) bool { return r.OperationID == operation.ID }); i >= 0 {
+			return fmt.Errorf("%w: operation %q is already queued on instrument %q as run %d",
+				entity.ErrQueueConflict, operation.Name, instrument.Name, queue[i].ID)
+		}
+
+		moved := entity.Enqueue(queue, run)
+		if err := uc.repo.Create(txCtx, run); err != nil {
+			return fmt.Errorf("uc.repo.Create: %w", err)
+		}
+		return uc.saveQueuePositions(txCtx, moved)
+	}); err != nil {
+		return nil, fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+	uc.emit(run, stateChanged(run))
+	uc.notify(run.InstrumentID)
+
+	return run, nil
+}
+
+// checkDeck - the operation was planned for a deck, it cannot run on an instrument with another one.
+func (uc *useCase) checkDeck(ctx context.Context, operation *entity.Operation, instrument *entity.Instrument) error {
+	if operation.DeckProfileID == nil && instrument.DeckProfileID == nil {
+		return nil
+	}
+
+	operationDeck, err := uc.deckUc.GetOperationDeck(ctx, operation.DeckProfileID)
+	if err != nil {
+		return fmt.Errorf("uc.deckUc.GetOperationDeck: %w", err)
+	}
+	instrumentDeck, err := uc.deckUc.GetOperationDeck(ctx, instrument.DeckProfileID)
+	if err != nil {
+		return fmt.Errorf("uc.deckUc.GetOperationDeck: %w", err)
+	}
+
+	if operationDeck.ID != instrumentDeck.ID {
+		return fmt.Errorf("%w: operation %q is planned for deck %q, instrument %q has deck %q",
+			entity.ErrQueueConflict, operation.Name, operationDeck.Name, instrument.Name, instrumentDeck.Name)
+	}
+	return nil
+}
+
+func (uc *useCase) saveQueuePositions(ctx context.Context, runs []*entity.OperationRun) error {
+	for _, run := range runs {
+		if err := uc.repo.UpdateQueuePosition(ctx, run.ID, run.QueuePosition); err != nil {
+			return fmt.Errorf("uc.repo.UpdateQueuePosition: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetQueue - the runs of the instrument with their estimated start and finish, as of now.
+func (uc *useCase) GetQueue(ctx context.Context, instrumentID int64) (*entity.InstrumentQueue, error) {
+	queue, err := uc.getQueue(ctx, instrumentID)
+	if err != nil {
+		return nil, fmt.Errorf("OperationRunUseCase - GetQueue - %w", err)
+	}
+
+	return queue, nil
+}
+
+func (uc *useCase) getQueue(ctx context.Context, instrumentID int64) (*entity.InstrumentQueue, error) {
+	instrument, err := uc.instrumentRepo.GetById(ctx, instrumentID)
+	if err != nil {
+		return nil, fmt.Errorf("uc.instrumentRepo.GetById: %w", err)
+	}
+
+	var started []*entity.OperationRun
+	for _, state := range []entity.OperationRunState{entity.OperationRunRunning, entity.OperationRunPaused} {
+		runs, err := uc.repo.GetAll(ctx, entity.OperationRunFilter{InstrumentID: instrumentID, State: state})
+		if err != nil {
+			return nil, fmt.Errorf("uc.repo.GetAll: %w", err)
+		}
+		started = append(started, runs...)
+	}
+
+	queued, err := uc.repo.GetQueued(ctx, instrumentID)
+	if err != nil {
+		return nil, fmt.Errorf("uc.repo.GetQueued: %w", err)
+	}
+
+	return entity.NewInstrumentQueue(instrument, started, queued, time.Now(), uc.timeUnit), nil
+}
+
+// ReorderQueue - puts the queued runs of the instrument in the given order, which has to list
+// all of them. A run queued or started since the order was made is a conflict.
+func (uc *useCase) ReorderQueue(ctx context.Context, inp entity.ReorderQueueInput) (*entity.InstrumentQueue, error) {
+	op := "OperationRunUseCase - ReorderQueue"
+
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		if _, err := uc.instrumentRepo.GetByIdForUpdate(txCtx, inp.InstrumentID); err != nil {
+			return fmt.Errorf("uc.instrumentRepo.GetByIdForUpdate: %w", err)
+		}
+
+		queue, err := uc.repo.GetQueued(txCtx, inp.InstrumentID)
+		if err != nil {
+			return fmt.Errorf("uc.repo.GetQueued: %w", err)
+		}
+
+		moved, err := entity.ReorderQueue(queue, inp.RunIDs)
+		if err != nil {
+			return err
+		}
+		return uc.saveQueuePositions(txCtx, moved)
+	}); err != nil {
+		return nil, fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	queue, err := uc.getQueue(ctx, inp.InstrumentID)
+	if err != nil {
+		return nil, fmt.Errorf("%s - %w", op, err)
+	}
+
+	return queue, nil
+}
+
+func (uc *useCase) GetRuns(ctx context.Context, filter entity.OperationRunFilter) ([]*entity.OperationRun, error) {
+	op := "OperationRunUseCase - GetRuns"
+
+	if filter.State != "" && !filter.State.IsValid() {
+		return nil, fmt.Errorf("%s: %w: %q", op, entity.ErrInvalidRunState, filter.State)
+	}
+
+	runs, err := uc.repo.GetAll(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.repo.GetAll: %w", op, err)
+	}
+
+	return runs, nil
+}
+
+func (uc *useCase) GetRun(ctx context.Context, id int64) (*entity.OperationRun, error) {
+	run, err := uc.repo.GetById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("OperationRunUseCase - GetRun - uc.repo.GetById: %w", err)
+	}
+
+	return run, nil
+}
+
+// PauseRun - the step being executed is finished, the next one waits for ResumeRun.
+func (uc *useCase) PauseRun(ctx context.Context, id int64) (*entity.OperationRun, error) {
+	run, err := uc.changeState(ctx, id, entity.OperationRunRunning, entity.OperationRunPaused, func(a *activeRun) {
+		a.resumed = make(chan struct{})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("OperationRunUseCase - PauseRun - %w", err)
+	}
+
+	return run, nil
+}
+
+// ResumeRun - lets the worker of a paused run go on with the next step.
+func (uc *useCase) ResumeRun(ctx context.Context, id int64) (*entity.OperationRun, error) {
+	run, err := uc.changeState(ctx, id, entity.OperationRunPaused, entity.OperationRunRunning, func(a *activeRun) {
+		close(a.resumed)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("OperationRunUseCase - ResumeRun - %w", err)
+	}
+
+	return run, nil
+}
+
+// AbortRun - stops the step being executed, a queued run is dropped from the queue.
+func (uc *useCase) AbortRun(ctx context.Context, id int64) (*entity.OperationRun, error) {
+	run, err := uc.changeState(ctx, id, "", entity.OperationRunAborted, func(a *activeRun) {
+		a.cancel()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("OperationRunUseCase - AbortRun - %w", err)
+	}
+
+	return run, nil
+}
+
+// changeState - moves the run to the state and saves it, onActive is called
+// when the run is the one being executed. A from state limits the change to a run
+// a worker of this process executes in that state, since only the worker can pause and resume it.
+func (uc *useCase) changeState(ctx context.Context, id int64, from, to entity.OperationRunState, onActive func(*activeRun)) (*entity.OperationRun, error) {
+	lock, release := uc.lockRun(id)
+	defer release()
+	lock.Lock()
+	defer lock.Unlock()
+
+	uc.mu.Lock()
+	active, ok := uc.active[id]
+	uc.mu.Unlock()
+
+	var (
+		run *entity.OperationRun
+		err error
+	)
+	if ok {
+		run = active.run
+	} else if run, err = uc.repo.GetById(ctx, id); err != nil {
+		return nil, fmt.Errorf("uc.repo.GetById: %w", err)
+	}
+	switch {
+	case from == "":
+	case !ok:
+		return nil, fmt.Errorf("%w: run %d is not executed by a worker", entity.ErrInvalidRunTransition, id)
+	case run.State != from:
+		return nil, fmt.Errorf("%w: %s to %s", entity.ErrInvalidRunTransition, run.State, to)
+	}
+
+	now := time.Now()
+	if err := run.Transition(to, now); err != nil {
+		return nil, err
+	}
+	// The step being executed is aborted with the run, the executor is stopped by onActive.
+	var step *entity.OperationRunStep
+	if to == entity.OperationRunAborted && run.HasNextStep() && run.Steps[run.CurrentStep].State == entity.OperationRunStepRunning {
+		step = run.Steps[run.CurrentStep]
+		run.FinishStep(entity.OperationRunStepAborted, nil, now)
+	}
+	if ok {
+		onActive(active)
+	}
+
+	if err := uc.repo.Update(ctx, run); err != nil {
+		return nil, fmt.Errorf("uc.repo.Update: %w", err)
+	}
+	uc.emit(run, stepFinished(step), stateChanged(run))
+
+	res := *run
+	return &res, nil
+}
+
+// lockRun - the lock of the run, release drops the reference to it once the lock is no longer used.
+func (uc *useCase) lockRun(id int64) (*runLock, func()) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	lock, ok := uc.locks[id]
+	if !ok {
+		lock = &runLock{}
+		uc.locks[id] = lock
+	}
+	lock.refs++
+
+	return lock, func() {
+		uc.mu.Lock()
+		defer uc.mu.Unlock()
+
+		if lock.refs--; lock.refs == 0 {
+			delete(uc.locks, id)
+		}
+	}
+}
+
+// Process - starts a worker for each instrument and waits for them until ctx is cancelled.
+// Runs left running by a previous process are failed first, since their steps cannot be resumed.
+func (uc *useCase) Process(ctx context.Context) error {
+	op := "OperationRunUseCase - Process"
+
+	if err := uc.failInterrupted(ctx, "interrupted by a restart"); err != nil {
+		return fmt.Errorf("%s - %w", op, err)
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		var retry <-chan time.Time
+		if err := uc.startWorkers(ctx, &wg); err != nil {
+			uc.l.Error(fmt.Errorf("%s - %w", op, err))
+			retry = time.After(retryInterval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-uc.wake:
+		case <-retry:
+		}
+	}
+}
+
+// startWorkers - starts the workers of the instruments that have none yet.
+func (uc *useCase) startWorkers(ctx context.Context, wg *sync.WaitGroup) error {
+	instruments, err := uc.instrumentRepo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("uc.instrumentRepo.GetAll: %w", err)
+	}
+
+	uc.workersMu.Lock()
+	defer uc.workersMu.Unlock()
+
+	for _, instrument := range instruments {
+		if _, ok := uc.workers[instrument.ID]; ok {
+			continue
+		}
+
+		wake := make(chan struct{}, 1)
+		uc.workers[instrument.ID] = wake
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			uc.work(ctx, instrument.ID, wake)
+		}()
+	}
+	return nil
+}
+
+// notify - wakes the worker of the instrument, or Process when the instrument has none yet.
+func (uc *useCase) notify(instrumentID int64) {
+	uc.workersMu.Lock()
+	wake, ok := uc.workers[instrumentID]
+	uc.workersMu.Unlock()
+	if !ok {
+		wake = uc.wake
+	}
+
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+// work - executes the queue of the instrument in order until ctx is cancelled.
+func (uc *useCase) work(ctx context.Context, instrumentID int64, wake <-chan struct{}) {
+	op := "OperationRunUseCase - work"
+
+	for {
+		run, err := uc.repo.GetNextQueued(ctx, instrumentID)
+		switch {
+		case err == nil:
+			uc.execute(ctx, run.ID)
+			continue
+		case errors.Is(err, entity.ErrOperationRunNotFound):
+			select {
+			case <-ctx.Done():
+				return
+			case <-wake:
+			}
+		default:
+			uc.l.Error(fmt.Errorf("%s - uc.repo.GetNextQueued: %w", op, err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryInterval):
+			}
+		}
+	}
+}
+
+func (uc *useCase) failInterrupted(ctx context.Context, reason string) error {
+	for _, state := range []entity.OperationRunState{entity.OperationRunRunning, entity.OperationRunPaused} {
+		runs, err := uc.repo.GetAll(ctx, entity.OperationRunFilter{State: state})
+		if err != nil {
+			return fmt.Errorf("uc.repo.GetAll: %w", err)
+		}
+
+		for _, run := range runs {
+			step := uc.fail(run, reason)
+			if err := uc.repo.Update(ctx, run); err != nil {
+				return fmt.Errorf("uc.repo.Update: %w", err)
+			}
+			uc.emit(run, stepFinished(step), stateChanged(run))
+		}
+	}
+	return nil
+}
+
+// fail - closes the run as failed, the step being executed fails with it and is returned.
+func (uc *useCase) fail(run *entity.OperationRun, reason string) *entity.OperationRunStep {
+	var (
+		now  = time.Now()
+		step *entity.OperationRunStep
+	)
+	if run.HasNextStep() && run.Steps[run.CurrentStep].State == entity.OperationRunStepRunning {
+		step = run.Steps[run.CurrentStep]
+		run.FinishStep(entity.OperationRunStepFailed, errors.New(reason), now)
+	}
+	run.Error = reason
+	_ = run.Transition(entity.OperationRunFailed, now)
+	return step
+}
+
+// execute - runs the steps of the run in order. Its changes are saved even when ctx
+// is cancelled, so that a shutdown leaves the run failed instead of running.
+func (uc *useCase) execute(ctx context.Context, id int64) {
+	op := "OperationRunUseCase - execute"
+
+	saveCtx := context.WithoutCancel(ctx)
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	save := func(run *entity.OperationRun) {
+		if err := uc.repo.Update(saveCtx, run); err != nil {
+			uc.l.Error(fmt.Errorf("%s - uc.repo.Update: %w", op, err))
+		}
+	}
+
+	lock, release := uc.lockRun(id)
+	defer release()
+
+	lock.Lock()
+	run, err := uc.repo.GetById(saveCtx, id)
+	if err != nil {
+		lock.Unlock()
+		uc.l.Error(fmt.Errorf("%s - uc.repo.GetById: %w", op, err))
+		return
+	}
+	// The run may have been aborted after it was taken from the queue.
+	if err := run.Transition(entity.OperationRunRunning, time.Now()); err != nil {
+		lock.Unlock()
+		return
+	}
+	save(run)
+	uc.emit(run, stateChanged(run))
+	a := &activeRun{run: run, cancel: cancel}
+	uc.mu.Lock()
+	uc.active[run.ID] = a
+	uc.mu.Unlock()
+	lock.Unlock()
+
+	uc.l.Info("%s - run %d of operation %s started", op, run.ID, run.OperationID)
+
+	for uc.waitWhilePaused(runCtx, lock, a) == nil {
+		lock.Lock()
+		if run.State != entity.OperationRunRunning || !run.HasNextStep() {
+			lock.Unlock()
+			break
+		}
+		step := run.StartStep(time.Now())
+		save(run)
+		uc.emit(run, stepStarted(step))
+		lock.Unlock()
+
+		err := uc.executor.Execute(runCtx, step)
+
+		lock.Lock()
+		if run.State == entity.OperationRunAborted {
+			// AbortRun has closed the step already.
+			lock.Unlock()
+			break
+		}
+		prevState := run.State
+		switch {
+		case err != nil && ctx.Err() != nil:
+			uc.fail(run, "interrupted by a shutdown")
+		case err != nil:
+			run.FinishStep(entity.OperationRunStepFailed, err, time.Now())
+			run.Error = fmt.Sprintf("step %d %s: %v", step.Index, step.SystemName, err)
+			_ = run.Transition(entity.OperationRunFailed, time.Now())
+		default:
+			run.FinishStep(entity.OperationRunStepCompleted, nil, time.Now())
+		}
+		save(run)
+		if run.State != prevState {
+			uc.emit(run, stepFinished(step), stateChanged(run))
+		} else {
+			uc.emit(run, stepFinished(step))
+		}
+		lock.Unlock()
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	switch {
+	case run.State == entity.OperationRunRunning && !run.HasNextStep():
+		_ = run.Transition(entity.OperationRunCompleted, time.Now())
+		save(run)
+		uc.emit(run, stateChanged(run))
+	case !run.State.IsFinal():
+		step := uc.fail(run, "interrupted by a shutdown")
+		save(run)
+		uc.emit(run, stepFinished(step), stateChanged(run))
+	}
+	uc.mu.Lock()
+	delete(uc.active, run.ID)
+	uc.mu.Unlock()
+
+	uc.l.Info("%s - run %d of operation %s %s", op, run.ID, run.OperationID, run.State)
+}
+
+// waitWhilePaused - blocks until the run is resumed, the error of ctx when it is aborted meanwhile.
+func (uc *useCase) waitWhilePaused(ctx context.Context, lock *runLock, a *activeRun) error {
+	for {
+		lock.Lock()
+		state, resumed := a.run.State, a.resumed
+		lock.Unlock()
+
+		if state != entity.OperationRunPaused {
+			return ctx.Err()
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-resumed:
+		}
+	}
+}
+
+// Subscribe - the events of the run after lastEventID. The stream starts with a snapshot of the run
+// when lastEventID is 0 or the events after it are no longer kept, and the channel is closed
+// once the run is over or ctx is done.
+func (uc *useCase) Subscribe(ctx context.Context, id int64, lastEventID int64) (<-chan *entity.OperationRunEvent, error) {
+	if _, err := uc.repo.GetById(ctx, id); err != nil {
+		return nil, fmt.Errorf("OperationRunUseCase - Subscribe - uc.repo.GetById: %w", err)
+	}
+
+	out := make(chan *entity.OperationRunEvent)
+	go uc.stream(ctx, id, lastEventID, out)
+
+	return out, nil
+}
+
+// stream - sends the events only as fast as out is read, the events published meanwhile
+// wait in the log, so a slow subscriber never holds up the run.
+func (uc *useCase) stream(ctx context.Context, id int64, lastID int64, out chan<- *entity.OperationRunEvent) {
+	defer close(out)
+
+	wake, unwatch := uc.events.watch(id)
+	defer unwatch()
+
+	for {
+		events, latestID, ok := uc.events.since(id, lastID)
+		if !ok {
+			run, err := uc.repo.GetById(ctx, id)
+			if err != nil {
+				if ctx.Err() == nil {
+					uc.l.Error(fmt.Errorf("OperationRunUseCase - stream - uc.repo.GetById: %w", err))
+				}
+				return
+			}
+
+			now := time.Now()
+			events = []*entity.OperationRunEvent{{
+				ID:       latestID,
+				RunID:    id,
+				Type:     entity.OperationRunEventSnapshot,
+				At:       now,
+				State:    run.State,
+				Error:    run.Error,
+				Progress: run.Progress(now, uc.timeUnit),
+				Run:      run,
+			}}
+		}
+
+		over := false
+		for _, e := range events {
+			select {
+			case <-ctx.Done():
+				return
+			case out <- e:
+			}
+			lastID = e.ID
+			over = over || isFinal(e)
+		}
+		// The progress published with the final state is in the same batch.
+		if over {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-wake:
+		}
+	}
+}
+
+// emit - publishes the changes of the run followed by its progress.
+func (uc *useCase) emit(run *entity.OperationRun, events ...*entity.OperationRunEvent) {
+	now := time.Now()
+	events = append(events, &entity.OperationRunEvent{
+		Type:     entity.OperationRunEventProgress,
+		Progress: run.Progress(now, uc.timeUnit),
+	})
+
+	published := make([]*entity.OperationRunEvent, 0, len(events))
+	for _, e := range events {
+		if e == nil {
+			continue
+		}
+		e.RunID = run.ID
+		e.At = now
+		published = append(published, e)
+	}
+	uc.events.publish(published...)
+}
+
+func stateChanged(run *entity.OperationRun) *entity.OperationRunEvent {
+	return &entity.OperationRunEvent{Type: entity.OperationRunEventStateChanged, State: run.State, Error: run.Error}
+}
+
+func stepStarted(step *entity.OperationRunStep) *entity.OperationRunEvent {
+	s := *step
+	return &entity.OperationRunEvent{Type: entity.OperationRunEventStepStarted, Step: &s}
+}
+
+// stepFinished - nil for a nil step, emit skips it.
+func stepFinished(step *entity.OperationRunStep) *entity.OperationRunEvent {
+	if step == nil {
+		return nil
+	}
+	s := *step
+	return &entity.OperationRunEvent{Type: entity.OperationRunEventStepFinished, Step: &s}
+}
+
+func isFinal(e *entity.OperationRunEvent) bool {
+	switch e.Type {
+	case entity.OperationRunEventStateChanged, entity.OperationRunEventSnapshot:
+		return e.State.IsFinal()
+	}
+	return false
+}
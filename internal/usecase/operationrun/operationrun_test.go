@@ -0,0 +1,389 @@
+package operationrun_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"test_go/internal/entity"
+	"test_go/internal/repo/executor"
+	"test_go/internal/usecase"
+	"test_go/internal/usecase/operationrun"
+)
+
+const (
+	instrumentID = 1
+	waitTimeout  = 5 * time.Second
+)
+
+// stepClock - a clock whose timers fire only when the test finishes the step.
+type stepClock struct {
+	started chan chan time.Time
+}
+
+func newStepClock() *stepClock {
+	return &stepClock{started: make(chan chan time.Time, 16)}
+}
+
+func (c *stepClock) After(time.Duration) <-chan time.Time {
+	finish := make(chan time.Time, 1)
+	c.started <- finish
+	return finish
+}
+
+// waitStep - blocks until the executor starts a step, the returned channel finishes it.
+func (c *stepClock) waitStep(t *testing.T) chan<- time.Time {
+	t.Helper()
+
+	select {
+	case finish := <-c.started:
+		return finish
+	case <-time.After(waitTimeout):
+		t.Fatal("no step was started")
+		return nil
+	}
+}
+
+func (c *stepClock) expectNoStep(t *testing.T) {
+	t.Helper()
+
+	select {
+	case <-c.started:
+		t.Fatal("a step was started")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// runRepo - keeps copies of the runs, so the worker and the test never share one.
+type runRepo struct {
+	mu     sync.Mutex
+	runs   map[int64]*entity.OperationRun
+	nextID int64
+}
+
+func newRunRepo() *runRepo {
+	return &runRepo{runs: make(map[int64]*entity.OperationRun)}
+}
+
+func clone(run *entity.OperationRun) *entity.OperationRun {
+	res := *run
+	res.Steps = make([]*entity.OperationRunStep, 0, len(run.Steps))
+	for _, step := range run.Steps {
+		s := *step
+		res.Steps = append(res.Steps, &s)
+	}
+	return &res
+}
+
+func (r *runRepo) Create(_ context.Context, run *entity.OperationRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	run.ID = r.nextID
+	r.runs[run.ID] = clone(run)
+	return nil
+}
+
+func (r *runRepo) GetById(_ context.Context, id int64) (*entity.OperationRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	run, ok := r.runs[id]
+	if !ok {
+		return nil, entity.ErrOperationRunNotFound
+	}
+	return clone(run), nil
+}
+
+func (r *runRepo) GetNextQueued(ctx context.Context, instrumentID int64) (*entity.OperationRun, error) {
+	queue, _ := r.GetQueued(ctx, instrumentID)
+	if len(queue) == 0 {
+		return nil, entity.ErrOperationRunNotFound
+	}
+	return queue[0], nil
+}
+
+func (r *runRepo) GetQueued(_ context.Context, instrumentID int64) ([]*entity.OperationRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	queue := make([]*entity.OperationRun, 0)
+	for _, run := range r.runs {
+		if run.InstrumentID == instrumentID && run.State == entity.OperationRunQueued {
+			queue = append(queue, clone(run))
+		}
+	}
+	slices.SortFunc(queue, func(a, b *entity.OperationRun) int {
+		return a.QueuePosition - b.QueuePosition
+	})
+	return queue, nil
+}
+
+func (r *runRepo) GetAll(_ context.Context, filter entity.OperationRunFilter) ([]*entity.OperationRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	runs := make([]*entity.OperationRun, 0)
+	for _, run := range r.runs {
+		if filter.State != "" && run.State != filter.State ||
+			filter.InstrumentID != 0 && run.InstrumentID != filter.InstrumentID {
+			continue
+		}
+		runs = append(runs, clone(run))
+	}
+	return runs, nil
+}
+
+func (r *runRepo) UpdateQueuePosition(_ context.Context, id int64, position int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	run, ok := r.runs[id]
+	if !ok {
+		return entity.ErrOperationRunNotFound
+	}
+	run.QueuePosition = position
+	return nil
+}
+
+func (r *runRepo) Update(_ context.Context, run *entity.OperationRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.runs[run.ID]; !ok {
+		return entity.ErrOperationRunNotFound
+	}
+	r.runs[run.ID] = clone(run)
+	return nil
+}
+
+// instrumentRepo - the single instrument the workers are started for.
+type instrumentRepo struct{}
+
+func (instrumentRepo) Create(context.Context, *entity.Instrument) error { return nil }
+
+func (instrumentRepo) GetById(context.Context, int64) (*entity.Instrument, error) {
+	return &entity.Instrument{ID: instrumentID}, nil
+}
+
+func (instrumentRepo) GetByIdForUpdate(context.Context, int64) (*entity.Instrument, error) {
+	return &entity.Instrument{ID: instrumentID}, nil
+}
+
+func (instrumentRepo) GetByName(context.Context, string) (*entity.Instrument, error) {
+	return &entity.Instrument{ID: instrumentID}, nil
+}
+
+func (instrumentRepo) GetAll(context.Context) ([]*entity.Instrument, error) {
+	return []*entity.Instrument{{ID: instrumentID}}, nil
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(interface{}, ...interface{}) {}
+func (nopLogger) Info(string, ...interface{})       {}
+func (nopLogger) Warn(string, ...interface{})       {}
+func (nopLogger) Error(interface{}, ...interface{}) {}
+func (nopLogger) Fatal(interface{}, ...interface{}) {}
+
+type engine struct {
+	uc    usecase.OperationRun
+	repo  *runRepo
+	clock *stepClock
+}
+
+func newEngine() *engine {
+	repo, clock := newRunRepo(), newStepClock()
+	uc := operationrun.New(nil, repo, instrumentRepo{}, nil, nil, nil,
+		executor.NewSimulator(clock, time.Second), time.Second, nopLogger{})
+
+	return &engine{uc: uc, repo: repo, clock: clock}
+}
+
+// queue - stores a queued run with a step for each system name.
+func (e *engine) queue(t *testing.T, systemNames ...string) int64 {
+	t.Helper()
+
+	run := &entity.OperationRun{InstrumentID: instrumentID, State: entity.OperationRunQueued}
+	for i, systemName := range systemNames {
+		run.Steps = append(run.Steps, &entity.OperationRunStep{
+			Index: i, SystemName: systemName, AverageTime: 1, State: entity.OperationRunStepPending,
+		})
+	}
+	if err := e.repo.Create(context.Background(), run); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return run.ID
+}
+
+// start - runs Process until the returned stop is called, stop waits for the workers.
+func (e *engine) start(t *testing.T) (stop func()) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- e.uc.Process(ctx) }()
+
+	var once sync.Once
+	stop = func() {
+		once.Do(func() {
+			cancel()
+			select {
+			case err := <-done:
+				if err != nil {
+					t.Errorf("Process: %v", err)
+				}
+			case <-time.After(waitTimeout):
+				t.Error("Process did not return after ctx was cancelled")
+			}
+		})
+	}
+	t.Cleanup(stop)
+	return stop
+}
+
+// waitState - the stored run once it reaches the state.
+func (e *engine) waitState(t *testing.T, id int64, state entity.OperationRunState) *entity.OperationRun {
+	t.Helper()
+
+	return e.waitRun(t, id, func(run *entity.OperationRun) bool { return run.State == state })
+}
+
+// waitPaused - the stored run once it is paused with the step being executed finished.
+func (e *engine) waitPaused(t *testing.T, id int64) *entity.OperationRun {
+	t.Helper()
+
+	return e.waitRun(t, id, func(run *entity.OperationRun) bool {
+		return run.State == entity.OperationRunPaused && run.CurrentStep > 0
+	})
+}
+
+func (e *engine) waitRun(t *testing.T, id int64, done func(*entity.OperationRun) bool) *entity.OperationRun {
+	t.Helper()
+
+	deadline := time.Now().Add(waitTimeout)
+	for {
+		run, err := e.repo.GetById(context.Background(), id)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if done(run) {
+			return run
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("run is %s at step %d", run.State, run.CurrentStep)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestPauseAndResumeRun(t *testing.T) {
+	e := newEngine()
+	id := e.queue(t, "fill", "rinse")
+	e.start(t)
+
+	finish := e.clock.waitStep(t)
+	if _, err := e.uc.PauseRun(context.Background(), id); err != nil {
+		t.Fatalf("PauseRun: %v", err)
+	}
+	// the step being executed is finished, the next one waits for ResumeRun
+	finish <- time.Now()
+	run := e.waitPaused(t, id)
+	e.clock.expectNoStep(t)
+
+	if run.CurrentStep != 1 || run.Steps[0].State != entity.OperationRunStepCompleted {
+		t.Fatalf("paused at step %d with the first step %s", run.CurrentStep, run.Steps[0].State)
+	}
+	if _, err := e.uc.PauseRun(context.Background(), id); !errors.Is(err, entity.ErrInvalidRunTransition) {
+		t.Fatalf("PauseRun of a paused run: got %v, want %v", err, entity.ErrInvalidRunTransition)
+	}
+
+	if _, err := e.uc.ResumeRun(context.Background(), id); err != nil {
+		t.Fatalf("ResumeRun: %v", err)
+	}
+	e.clock.waitStep(t) <- time.Now()
+
+	run = e.waitState(t, id, entity.OperationRunCompleted)
+	if run.Steps[1].State != entity.OperationRunStepCompleted {
+		t.Fatalf("last step is %s, want %s", run.Steps[1].State, entity.OperationRunStepCompleted)
+	}
+}
+
+func TestAbortRunningRun(t *testing.T) {
+	e := newEngine()
+	id := e.queue(t, "fill", "rinse")
+	e.start(t)
+
+	e.clock.waitStep(t)
+	if _, err := e.uc.AbortRun(context.Background(), id); err != nil {
+		t.Fatalf("AbortRun: %v", err)
+	}
+	// the executor is stopped without the step being finished
+	e.clock.expectNoStep(t)
+
+	run := e.waitState(t, id, entity.OperationRunAborted)
+	if run.Steps[0].State != entity.OperationRunStepAborted || run.Steps[1].State != entity.OperationRunStepPending {
+		t.Fatalf("steps are %s and %s after the abort", run.Steps[0].State, run.Steps[1].State)
+	}
+	if _, err := e.uc.ResumeRun(context.Background(), id); !errors.Is(err, entity.ErrInvalidRunTransition) {
+		t.Fatalf("ResumeRun of an aborted run: got %v, want %v", err, entity.ErrInvalidRunTransition)
+	}
+}
+
+func TestAbortPausedRun(t *testing.T) {
+	e := newEngine()
+	id := e.queue(t, "fill", "rinse")
+	e.start(t)
+
+	finish := e.clock.waitStep(t)
+	if _, err := e.uc.PauseRun(context.Background(), id); err != nil {
+		t.Fatalf("PauseRun: %v", err)
+	}
+	finish <- time.Now()
+	e.waitPaused(t, id)
+
+	if _, err := e.uc.AbortRun(context.Background(), id); err != nil {
+		t.Fatalf("AbortRun: %v", err)
+	}
+	e.clock.expectNoStep(t)
+
+	run := e.waitState(t, id, entity.OperationRunAborted)
+	if run.Steps[0].State != entity.OperationRunStepCompleted || run.Steps[1].State != entity.OperationRunStepPending {
+		t.Fatalf("steps are %s and %s after the abort", run.Steps[0].State, run.Steps[1].State)
+	}
+}
+
+func TestAbortQueuedRun(t *testing.T) {
+	e := newEngine()
+	id := e.queue(t, "fill")
+
+	if _, err := e.uc.ResumeRun(context.Background(), id); !errors.Is(err, entity.ErrInvalidRunTransition) {
+		t.Fatalf("ResumeRun of a queued run: got %v, want %v", err, entity.ErrInvalidRunTransition)
+	}
+	if _, err := e.uc.AbortRun(context.Background(), id); err != nil {
+		t.Fatalf("AbortRun: %v", err)
+	}
+
+	// the worker does not take the aborted run from the queue
+	e.start(t)
+	e.clock.expectNoStep(t)
+	e.waitState(t, id, entity.OperationRunAborted)
+}
+
+func TestShutdownFailsRun(t *testing.T) {
+	e := newEngine()
+	id := e.queue(t, "fill", "rinse")
+	stop := e.start(t)
+
+	e.clock.waitStep(t)
+	stop()
+
+	run := e.waitState(t, id, entity.OperationRunFailed)
+	if run.Error != "interrupted by a shutdown" || run.Steps[0].State != entity.OperationRunStepFailed {
+		t.Fatalf("failed with %q and the step %s", run.Error, run.Steps[0].State)
+	}
+}
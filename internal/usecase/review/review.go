@@ -0,0 +1,244 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Alice00021/test_common/pkg/logger"
+	"github.com/Alice00021/test_common/pkg/transactional"
+	"strings"
+	"test_go/internal/entity"
+	"test_go/internal/repo"
+)
+
+type useCase struct {
+	transactional.Transactional
+	repo     repo.ReviewRepo
+	bookRepo repo.BookRepo
+	l        logger.Interface
+}
+
+func New(t transactional.Transactional,
+	repo repo.ReviewRepo,
+	bookRepo repo.BookRepo,
+	l logger.Interface,
+) *useCase {
+	return &useCase{
+		Transactional: t,
+		repo:          repo,
+		bookRepo:      bookRepo,
+		l:             l,
+	}
+}
+
+func (uc *useCase) CreateReview(ctx context.Context, inp entity.CreateReviewInput) (*entity.Review, error) {
+	op := "ReviewUseCase - CreateReview"
+
+	if !entity.IsValidRating(inp.Rating) {
+		return nil, entity.ErrInvalidRating
+	}
+
+	var review entity.Review
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		if _, err := uc.bookRepo.GetById(txCtx, inp.BookID); err != nil {
+			return fmt.Errorf("uc.bookRepo.GetById: %w", err)
+		}
+
+		_, err := uc.repo.GetByBookAndUser(txCtx, inp.BookID, inp.UserID)
+		if err == nil {
+			return entity.ErrReviewAlreadyExists
+		}
+
+		if !errors.Is(err, entity.ErrReviewNotFound) {
+			return fmt.Errorf("uc.repo.GetByBookAndUser: %w", err)
+		}
+
+		res, err := uc.repo.Create(txCtx, entity.NewReview(
+			inp.BookID, inp.UserID, inp.Rating, strings.TrimSpace(inp.Text),
+		))
+		if err != nil {
+			return fmt.Errorf("uc.repo.Create: %w", err)
+		}
+
+		if err := uc.applyRating(txCtx, nil, res); err != nil {
+			return err
+		}
+
+		review = *res
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	return &review, nil
+}
+
+func (uc *useCase) UpdateReview(ctx context.Context, user *entity.UserInfoToken, inp entity.UpdateReviewInput) (*entity.Review, error) {
+	op := "ReviewUseCase - UpdateReview"
+
+	if inp.Rating != nil && !entity.IsValidRating(*inp.Rating) {
+		return nil, entity.ErrInvalidRating
+	}
+
+	var review entity.Review
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		prev, err := uc.getModifiable(txCtx, user, inp.ID)
+		if err != nil {
+			return err
+		}
+
+		review = *prev
+		if inp.Rating != nil {
+			review.Rating = *inp.Rating
+		}
+		if inp.Text != nil {
+			review.Text = strings.TrimSpace(*inp.Text)
+		}
+
+		if err := uc.repo.Update(txCtx, &review); err != nil {
+			return fmt.Errorf("uc.repo.Update: %w", err)
+		}
+
+		return uc.applyRating(txCtx, prev, &review)
+	}); err != nil {
+		return nil, fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	return &review, nil
+}
+
+func (uc *useCase) DeleteReview(ctx context.Context, user *entity.UserInfoToken, id int64) error {
+	op := "ReviewUseCase - DeleteReview"
+
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		prev, err := uc.getModifiable(txCtx, user, id)
+		if err != nil {
+			return err
+		}
+
+		if err := uc.repo.DeleteById(txCtx, id); err != nil {
+			return fmt.Errorf("uc.repo.DeleteById: %w", err)
+		}
+
+		return uc.applyRating(txCtx, prev, nil)
+	}); err != nil {
+		return fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	return nil
+}
+
+func (uc *useCase) GetReviews(ctx context.Context, filter entity.FilterReviewInput) (*entity.ReviewList, error) {
+	filter.Normalize()
+
+	items, total, err := uc.repo.GetAll(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("ReviewUseCase - GetReviews - uc.repo.GetAll: %w", err)
+	}
+
+	return &entity.ReviewList{
+		Items: items,
+		Total: total,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+	}, nil
+}
+
+// FlagReview - reports the review as abusive and puts a published review in the moderation queue.
+func (uc *useCase) FlagReview(ctx context.Context, inp entity.FlagReviewInput) error {
+	op := "ReviewUseCase - FlagReview"
+
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		review, err := uc.repo.GetByIdForUpdate(txCtx, inp.ReviewID)
+		if err != nil {
+			return fmt.Errorf("uc.repo.GetByIdForUpdate: %w", err)
+		}
+
+		if review.UserID == inp.UserID {
+			return entity.ErrAccessDenied
+		}
+
+		inp.Reason = strings.TrimSpace(inp.Reason)
+		if err := uc.repo.AddFlag(txCtx, inp); err != nil {
+			return fmt.Errorf("uc.repo.AddFlag: %w", err)
+		}
+
+		if review.Status != entity.ReviewStatusPublished {
+			return nil
+		}
+
+		review.Status = entity.ReviewStatusFlagged
+		if err := uc.repo.Update(txCtx, review); err != nil {
+			return fmt.Errorf("uc.repo.Update: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// ModerateReview - an admin either publishes the review again or hides it from the book rating.
+func (uc *useCase) ModerateReview(ctx context.Context, inp entity.ModerateReviewInput) error {
+	op := "ReviewUseCase - ModerateReview"
+
+	if !inp.Status.IsModerationResult() {
+		return entity.ErrInvalidReviewStatus
+	}
+
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		prev, err := uc.repo.GetByIdForUpdate(txCtx, inp.ID)
+		if err != nil {
+			return fmt.Errorf("uc.repo.GetByIdForUpdate: %w", err)
+		}
+
+		review := *prev
+		review.Status = inp.Status
+
+		if err := uc.repo.Update(txCtx, &review); err != nil {
+			return fmt.Errorf("uc.repo.Update: %w", err)
+		}
+
+		return uc.applyRating(txCtx, prev, &review)
+	}); err != nil {
+		return fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// getModifiable - the review is locked, the rating delta is computed from its current state.
+func (uc *useCase) getModifiable(ctx context.Context, user *entity.UserInfoToken, id int64) (*entity.Review, error) {
+	review, err := uc.repo.GetByIdForUpdate(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("uc.repo.GetByIdForUpdate: %w", err)
+	}
+
+	if !review.CanBeModifiedBy(user) {
+		return nil, entity.ErrAccessDenied
+	}
+
+	return review, nil
+}
+
+// applyRating - moves the book rating from the old state of the review to the new one, nil means absent.
+func (uc *useCase) applyRating(ctx context.Context, prev, next *entity.Review) error {
+	sumDelta, countDelta := entity.ReviewRatingDelta(prev, next)
+	if sumDelta == 0 && countDelta == 0 {
+		return nil
+	}
+
+	var bookID int64
+	if next != nil {
+		bookID = next.BookID
+	} else {
+		bookID = prev.BookID
+	}
+
+	if err := uc.bookRepo.ApplyRating(ctx, bookID, sumDelta, countDelta); err != nil {
+		return fmt.Errorf("uc.bookRepo.ApplyRating: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package revision
+
+import (
+	"context"
+	"fmt"
+	"github.com/Alice00021/test_common/pkg/logger"
+	"test_go/internal/entity"
+	"test_go/internal/repo"
+)
+
+type useCase struct {
+	repo repo.RevisionRepo
+	l    logger.Interface
+}
+
+func New(repo repo.RevisionRepo, l logger.Interface) *useCase {
+	return &useCase{
+		repo: repo,
+		l:    l,
+	}
+}
+
+// Record - saves the revision of a mutation, updates that changed nothing are skipped.
+// It is expected to run inside the transaction of the mutation.
+func (uc *useCase) Record(ctx context.Context, inp entity.RecordRevisionInput) error {
+	op := "RevisionUseCase - Record"
+
+	e, err := entity.NewRevision(inp.EntityType, inp.EntityID, inp.Action, inp.ActorID, inp.Before, inp.After)
+	if err != nil {
+		return fmt.Errorf("%s - entity.NewRevision: %w", op, err)
+	}
+
+	if !e.HasChanges() {
+		return nil
+	}
+
+	if err = uc.repo.Create(ctx, e); err != nil {
+		return fmt.Errorf("%s - uc.repo.Create: %w", op, err)
+	}
+
+	return nil
+}
+
+func (uc *useCase) GetHistory(ctx context.Context, entityType entity.RevisionEntityType, entityID string) ([]*entity.Revision, error) {
+	items, err := uc.repo.GetByEntity(ctx, entityType, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("RevisionUseCase - GetHistory - uc.repo.GetByEntity: %w", err)
+	}
+
+	return items, nil
+}
+
+// GetRevision - the revision if it belongs to the given record.
+func (uc *useCase) GetRevision(ctx context.Context, entityType entity.RevisionEntityType, entityID string, id int64) (*entity.Revision, error) {
+	op := "RevisionUseCase - GetRevision"
+
+	e, err := uc.repo.GetById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.repo.GetById: %w", op, err)
+	}
+
+	if e.EntityType != entityType || e.EntityID != entityID {
+		return nil, fmt.Errorf("%s: %w", op, entity.ErrRevisionNotFound)
+	}
+
+	return e, nil
+}
@@ -0,0 +1,294 @@
+package shelf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Alice00021/test_common/pkg/logger"
+	"github.com/Alice00021/test_common/pkg/transactional"
+	"strings"
+	"test_go/internal/entity"
+	"test_go/internal/repo"
+	"time"
+)
+
+type useCase struct {
+	transactional.Transactional
+	repo     repo.ShelfRepo
+	bookRepo repo.BookRepo
+	l        logger.Interface
+}
+
+func New(t transactional.Transactional,
+	repo repo.ShelfRepo,
+	bookRepo repo.BookRepo,
+	l logger.Interface,
+) *useCase {
+	return &useCase{
+		Transactional: t,
+		repo:          repo,
+		bookRepo:      bookRepo,
+		l:             l,
+	}
+}
+
+func (uc *useCase) GetShelves(ctx context.Context, userID int64) ([]*entity.Shelf, error) {
+	op := "ShelfUseCase - GetShelves"
+
+	if err := uc.repo.EnsureDefaults(ctx, userID); err != nil {
+		return nil, fmt.Errorf("%s - uc.repo.EnsureDefaults: %w", op, err)
+	}
+
+	shelves, err := uc.repo.GetByUserId(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.repo.GetByUserId: %w", op, err)
+	}
+
+	ids := make([]int64, 0, len(shelves))
+	byId := make(map[int64]*entity.Shelf, len(shelves))
+	for _, s := range shelves {
+		s.Books = make([]*entity.ShelfBook, 0)
+		ids = append(ids, s.ID)
+		byId[s.ID] = s
+	}
+
+	books, err := uc.repo.GetBooks(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.repo.GetBooks: %w", op, err)
+	}
+
+	for _, b := range books {
+		if s, ok := byId[b.ShelfID]; ok {
+			s.Books = append(s.Books, b)
+		}
+	}
+
+	return shelves, nil
+}
+
+// CreateShelf - the default shelves are created first, so that a custom shelf
+// cannot take the name of one of them.
+func (uc *useCase) CreateShelf(ctx context.Context, inp entity.CreateShelfInput) (*entity.Shelf, error) {
+	op := "ShelfUseCase - CreateShelf"
+
+	var shelf entity.Shelf
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		name := strings.TrimSpace(inp.Name)
+
+		if err := uc.repo.EnsureDefaults(txCtx, inp.UserID); err != nil {
+			return fmt.Errorf("uc.repo.EnsureDefaults: %w", err)
+		}
+
+		_, err := uc.repo.GetByName(txCtx, inp.UserID, name)
+		if err == nil {
+			return entity.ErrShelfNameUsed
+		}
+
+		if !errors.Is(err, entity.ErrShelfNotFound) {
+			return fmt.Errorf("uc.repo.GetByName: %w", err)
+		}
+
+		res, err := uc.repo.Create(txCtx, &entity.Shelf{
+			UserID: inp.UserID,
+			Name:   name,
+			Kind:   entity.ShelfKindCustom,
+		})
+		if err != nil {
+			return fmt.Errorf("uc.repo.Create: %w", err)
+		}
+
+		shelf = *res
+		shelf.Books = make([]*entity.ShelfBook, 0)
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	return &shelf, nil
+}
+
+func (uc *useCase) DeleteShelf(ctx context.Context, userID, id int64) error {
+	op := "ShelfUseCase - DeleteShelf"
+
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		shelf, err := uc.getOwnShelf(txCtx, userID, id)
+		if err != nil {
+			return err
+		}
+
+		if shelf.Kind.IsStatus() {
+			return entity.ErrDefaultShelfDelete
+		}
+
+		if err := uc.repo.DeleteById(txCtx, id); err != nil {
+			return fmt.Errorf("uc.repo.DeleteById: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// PutBook - adds the book to the shelf or updates its progress there.
+// A book is kept on a single reading status shelf, moving it carries over the start date.
+func (uc *useCase) PutBook(ctx context.Context, inp entity.PutShelfBookInput) (*entity.ShelfBook, error) {
+	op := "ShelfUseCase - PutBook"
+
+	if inp.Progress != nil && (*inp.Progress < 0 || *inp.Progress > 100) {
+		return nil, entity.ErrInvalidShelfProgress
+	}
+
+	var res *entity.ShelfBook
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		shelf, err := uc.getOwnShelf(txCtx, inp.UserID, inp.ShelfID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := uc.bookRepo.GetById(txCtx, inp.BookID); err != nil {
+			return fmt.Errorf("uc.bookRepo.GetById: %w", err)
+		}
+
+		e, err := uc.repo.GetBook(txCtx, shelf.ID, inp.BookID)
+		if err != nil && !errors.Is(err, entity.ErrShelfBookNotFound) {
+			return fmt.Errorf("uc.repo.GetBook: %w", err)
+		}
+
+		if e == nil {
+			e = &entity.ShelfBook{ShelfID: shelf.ID, BookID: inp.BookID}
+
+			if shelf.Kind.IsStatus() {
+				if err := uc.leaveStatusShelf(txCtx, inp.UserID, e); err != nil {
+					return err
+				}
+			}
+		}
+
+		applyProgress(shelf.Kind, e, inp)
+
+		if e.ID == 0 {
+			res, err = uc.repo.AddBook(txCtx, e)
+			if err != nil {
+				return fmt.Errorf("uc.repo.AddBook: %w", err)
+			}
+			return nil
+		}
+
+		if err := uc.repo.UpdateBook(txCtx, e); err != nil {
+			return fmt.Errorf("uc.repo.UpdateBook: %w", err)
+		}
+		res = e
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (uc *useCase) RemoveBook(ctx context.Context, userID, shelfID, bookID int64) error {
+	op := "ShelfUseCase - RemoveBook"
+
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		if _, err := uc.getOwnShelf(txCtx, userID, shelfID); err != nil {
+			return err
+		}
+
+		if err := uc.repo.RemoveBook(txCtx, shelfID, bookID); err != nil {
+			return fmt.Errorf("uc.repo.RemoveBook: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	return nil
+}
+
+func (uc *useCase) GetReadingSummary(ctx context.Context, userID int64, year int) (*entity.ReadingSummary, error) {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	books, err := uc.repo.GetFinished(ctx, userID, from, from.AddDate(1, 0, 0))
+	if err != nil {
+		return nil, fmt.Errorf("ShelfUseCase - GetReadingSummary - uc.repo.GetFinished: %w", err)
+	}
+
+	summary := &entity.ReadingSummary{
+		Year:          year,
+		FinishedCount: len(books),
+		Books:         books,
+	}
+	for _, b := range books {
+		summary.ByMonth[b.FinishedAt.UTC().Month()-1]++
+	}
+
+	return summary, nil
+}
+
+func (uc *useCase) getOwnShelf(ctx context.Context, userID, id int64) (*entity.Shelf, error) {
+	shelf, err := uc.repo.GetById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("uc.repo.GetById: %w", err)
+	}
+
+	if !shelf.IsOwnedBy(userID) {
+		return nil, entity.ErrShelfNotFound
+	}
+
+	return shelf, nil
+}
+
+// leaveStatusShelf - removes the book from its current reading status shelf, keeping the reading dates.
+func (uc *useCase) leaveStatusShelf(ctx context.Context, userID int64, e *entity.ShelfBook) error {
+	prev, err := uc.repo.GetStatusBook(ctx, userID, e.BookID)
+	if errors.Is(err, entity.ErrShelfBookNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("uc.repo.GetStatusBook: %w", err)
+	}
+
+	if err := uc.repo.RemoveBook(ctx, prev.ShelfID, prev.BookID); err != nil {
+		return fmt.Errorf("uc.repo.RemoveBook: %w", err)
+	}
+
+	e.Progress = prev.Progress
+	e.StartedAt = prev.StartedAt
+	return nil
+}
+
+func applyProgress(kind entity.ShelfKind, e *entity.ShelfBook, inp entity.PutShelfBookInput) {
+	now := time.Now()
+
+	if inp.Progress != nil {
+		e.Progress = *inp.Progress
+	}
+
+	switch kind {
+	case entity.ShelfKindWantToRead:
+		e.Progress = 0
+		e.StartedAt = nil
+		e.FinishedAt = nil
+	case entity.ShelfKindReading:
+		if e.StartedAt == nil {
+			e.StartedAt = &now
+		}
+		e.FinishedAt = nil
+	case entity.ShelfKindFinished:
+		e.Progress = 100
+		switch {
+		case inp.FinishedAt != nil:
+			e.FinishedAt = inp.FinishedAt
+		case e.FinishedAt == nil:
+			e.FinishedAt = &now
+		}
+	default:
+		if inp.FinishedAt != nil {
+			e.FinishedAt = inp.FinishedAt
+		}
+	}
+}
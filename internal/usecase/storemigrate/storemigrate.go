@@ -0,0 +1,204 @@
+package storemigrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Alice00021/test_common/pkg/logger"
+	"github.com/Alice00021/test_common/pkg/transactional"
+	"test_go/internal/entity"
+	"test_go/internal/repo"
+)
+
+// progressEvery - how many records are migrated between two progress lines.
+const progressEvery = 100
+
+type useCase struct {
+	transactional.Transactional
+	source repo.StoreMigrationRepo
+	target repo.StoreMigrationRepo
+	l      logger.Interface
+}
+
+func New(
+	t transactional.Transactional,
+	source repo.StoreMigrationRepo,
+	target repo.StoreMigrationRepo,
+	l logger.Interface,
+) *useCase {
+	return &useCase{
+		Transactional: t,
+		source:        source,
+		target:        target,
+		l:             l,
+	}
+}
+
+// Migrate - copies the commands and then the operations from the source to the target,
+// records that are already there unchanged are skipped, so the migration can be run again.
+// After a real run the migrated records are read back from the target and compared with the source.
+func (uc *useCase) Migrate(ctx context.Context, inp entity.StoreMigrationInput) (*entity.StoreMigrationReport, error) {
+	op := "StoreMigrateUseCase - Migrate"
+
+	report := &entity.StoreMigrationReport{From: inp.From, To: inp.To, DryRun: inp.DryRun}
+
+	total, err := uc.source.Counts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.source.Counts: %w", op, err)
+	}
+	uc.l.Info("%s - %s: %d commands, %d operations, %d operation commands",
+		op, inp.From, total.Commands, total.Operations, total.OperationCommands)
+
+	var (
+		source      entity.StoreDigest
+		systemNames = make(map[string]struct{}, total.Commands)
+		origins     = make(map[string]struct{}, total.Operations)
+		migrated    int64
+	)
+
+	if err := uc.source.StreamCommands(ctx, func(c *entity.Command) error {
+		source.AddCommand(c)
+		systemNames[c.SystemName] = struct{}{}
+
+		if err := uc.migrateCommand(ctx, c, inp.DryRun, &report.Commands); err != nil {
+			return fmt.Errorf("command %s: %w", c.SystemName, err)
+		}
+
+		migrated++
+		uc.progress(op, "commands", migrated, total.Commands)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("%s - uc.source.StreamCommands: %w", op, err)
+	}
+
+	migrated = 0
+	if err := uc.source.StreamOperations(ctx, func(e *entity.Operation) error {
+		source.AddOperation(e)
+		origins[e.Origin()] = struct{}{}
+
+		if err := uc.migrateOperation(ctx, e, inp.DryRun, &report.Operations); err != nil {
+			return fmt.Errorf("operation %s: %w", e.Origin(), err)
+		}
+
+		migrated++
+		uc.progress(op, "operations", migrated, total.Operations)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("%s - uc.source.StreamOperations: %w", op, err)
+	}
+
+	report.Source = source.Summary()
+	if inp.DryRun {
+		return report, nil
+	}
+
+	target, err := uc.targetSummary(ctx, systemNames, origins)
+	if err != nil {
+		return nil, fmt.Errorf("%s - %w", op, err)
+	}
+	report.Target = target
+	report.Verified = *target == report.Source
+	if !report.Verified {
+		return report, fmt.Errorf("%s: %w", op, entity.ErrStoreMigrationMismatch)
+	}
+
+	uc.l.Info("%s - %s: verified %d commands, %d operations, %d operation commands",
+		op, inp.To, target.Commands, target.Operations, target.OperationCommands)
+	return report, nil
+}
+
+func (uc *useCase) migrateCommand(ctx context.Context, c *entity.Command, dryRun bool, stats *entity.StoreMigrationStats) error {
+	existing, err := uc.target.GetCommand(ctx, c.SystemName)
+	switch {
+	case errors.Is(err, entity.ErrCommandNotFound):
+		stats.Created++
+	case err != nil:
+		return fmt.Errorf("uc.target.GetCommand: %w", err)
+	case existing.MigrationChecksum() == c.MigrationChecksum():
+		stats.Unchanged++
+		return nil
+	default:
+		stats.Updated++
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	return uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		if err := uc.target.SaveCommand(txCtx, c); err != nil {
+			return fmt.Errorf("uc.target.SaveCommand: %w", err)
+		}
+		return nil
+	})
+}
+
+func (uc *useCase) migrateOperation(ctx context.Context, e *entity.Operation, dryRun bool, stats *entity.StoreMigrationStats) error {
+	existing, err := uc.target.GetOperationByOrigin(ctx, e.Origin())
+	switch {
+	case errors.Is(err, entity.ErrOperationNotFound):
+		stats.Created++
+		if dryRun {
+			return nil
+		}
+
+		return uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+			if err := uc.target.CreateOperation(txCtx, e); err != nil {
+				return fmt.Errorf("uc.target.CreateOperation: %w", err)
+			}
+			return nil
+		})
+	case err != nil:
+		return fmt.Errorf("uc.target.GetOperationByOrigin: %w", err)
+	case existing.MigrationChecksum() == e.MigrationChecksum():
+		stats.Unchanged++
+		return nil
+	}
+
+	stats.Updated++
+	if dryRun {
+		return nil
+	}
+
+	update := *e
+	update.ID, update.SourceID = existing.ID, existing.SourceID
+	return uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		if err := uc.target.UpdateOperation(txCtx, &update); err != nil {
+			return fmt.Errorf("uc.target.UpdateOperation: %w", err)
+		}
+		return nil
+	})
+}
+
+// targetSummary - the digest of the target records that came from the source,
+// records the target had on its own are left out.
+func (uc *useCase) targetSummary(ctx context.Context, systemNames, origins map[string]struct{}) (*entity.StoreSummary, error) {
+	var target entity.StoreDigest
+
+	if err := uc.target.StreamCommands(ctx, func(c *entity.Command) error {
+		if _, ok := systemNames[c.SystemName]; ok {
+			target.AddCommand(c)
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("uc.target.StreamCommands: %w", err)
+	}
+
+	if err := uc.target.StreamOperations(ctx, func(e *entity.Operation) error {
+		if _, ok := origins[e.Origin()]; ok {
+			target.AddOperation(e)
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("uc.target.StreamOperations: %w", err)
+	}
+
+	summary := target.Summary()
+	return &summary, nil
+}
+
+func (uc *useCase) progress(op, kind string, done, total int64) {
+	if done%progressEvery == 0 || done == total {
+		uc.l.Info("%s - %s: %d/%d", op, kind, done, total)
+	}
+}
@@ -0,0 +1,189 @@
+package trash
+
+import (
+	"context"
+	"fmt"
+	"github.com/Alice00021/test_common/pkg/logger"
+	"github.com/Alice00021/test_common/pkg/transactional"
+	"test_go/internal/entity"
+	"test_go/internal/repo"
+	"test_go/internal/usecase"
+	"time"
+)
+
+type useCase struct {
+	transactional.Transactional
+	authorRepo   repo.AuthorRepo
+	bookRepo     repo.BookRepo
+	shelfRepo    repo.ShelfRepo
+	coverStorage repo.CoverStorage
+	revisionUc   usecase.Revision
+	retention    time.Duration
+	l            logger.Interface
+}
+
+func New(t transactional.Transactional,
+	authorRepo repo.AuthorRepo,
+	bookRepo repo.BookRepo,
+	shelfRepo repo.ShelfRepo,
+	coverStorage repo.CoverStorage,
+	revisionUc usecase.Revision,
+	retention time.Duration,
+	l logger.Interface,
+) *useCase {
+	return &useCase{
+		Transactional: t,
+		authorRepo:    authorRepo,
+		bookRepo:      bookRepo,
+		shelfRepo:     shelfRepo,
+		coverStorage:  coverStorage,
+		revisionUc:    revisionUc,
+		retention:     retention,
+		l:             l,
+	}
+}
+
+func (uc *useCase) GetTrash(ctx context.Context) (*entity.Trash, error) {
+	op := "TrashUseCase - GetTrash"
+
+	authors, err := uc.authorRepo.GetDeleted(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.authorRepo.GetDeleted: %w", op, err)
+	}
+
+	books, err := uc.bookRepo.GetDeleted(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s - uc.bookRepo.GetDeleted: %w", op, err)
+	}
+
+	return &entity.Trash{
+		Authors: authors,
+		Books:   books,
+	}, nil
+}
+
+// RestoreAuthor - restores the author together with the books deleted by a cascade delete and their shelf entries.
+func (uc *useCase) RestoreAuthor(ctx context.Context, user *entity.UserInfoToken, id int64) error {
+	op := "TrashUseCase - RestoreAuthor"
+
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		author, err := uc.authorRepo.GetDeletedById(txCtx, id)
+		if err != nil {
+			return fmt.Errorf("uc.authorRepo.GetDeletedById: %w", err)
+		}
+
+		if err := uc.authorRepo.Restore(txCtx, id); err != nil {
+			return fmt.Errorf("uc.authorRepo.Restore: %w", err)
+		}
+
+		books, err := uc.bookRepo.RestoreByAuthorId(txCtx, id, *author.DeletedAt)
+		if err != nil {
+			return fmt.Errorf("uc.bookRepo.RestoreByAuthorId: %w", err)
+		}
+
+		if err := uc.shelfRepo.RestoreByAuthorId(txCtx, id, *author.DeletedAt); err != nil {
+			return fmt.Errorf("uc.shelfRepo.RestoreByAuthorId: %w", err)
+		}
+
+		if err := uc.record(txCtx, entity.AuthorRevision(user, id, entity.RevisionActionRestore, nil, author)); err != nil {
+			return err
+		}
+		for _, bookID := range books {
+			if err := uc.recordBookRestore(txCtx, user, bookID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// RestoreBook - restores the book; its author has to be restored first.
+func (uc *useCase) RestoreBook(ctx context.Context, user *entity.UserInfoToken, id int64) error {
+	op := "TrashUseCase - RestoreBook"
+
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		book, err := uc.bookRepo.GetDeletedById(txCtx, id)
+		if err != nil {
+			return fmt.Errorf("uc.bookRepo.GetDeletedById: %w", err)
+		}
+
+		if _, err := uc.authorRepo.GetById(txCtx, book.AuthorId); err != nil {
+			return fmt.Errorf("uc.authorRepo.GetById: %w", err)
+		}
+
+		if err := uc.bookRepo.Restore(txCtx, id); err != nil {
+			return fmt.Errorf("uc.bookRepo.Restore: %w", err)
+		}
+
+		if err := uc.shelfRepo.RestoreByBookId(txCtx, id, *book.DeletedAt); err != nil {
+			return fmt.Errorf("uc.shelfRepo.RestoreByBookId: %w", err)
+		}
+
+		return uc.record(txCtx, entity.BookRevision(user, id, entity.RevisionActionRestore, nil, book))
+	}); err != nil {
+		return fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// PurgeExpired - permanently removes records kept in trash longer than the retention period.
+func (uc *useCase) PurgeExpired(ctx context.Context) error {
+	op := "TrashUseCase - PurgeExpired"
+
+	before := time.Now().Add(-uc.retention)
+
+	var (
+		books   []int64
+		authors int64
+	)
+	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		books, err = uc.bookRepo.PurgeDeletedBefore(txCtx, before)
+		if err != nil {
+			return fmt.Errorf("uc.bookRepo.PurgeDeletedBefore: %w", err)
+		}
+
+		authors, err = uc.authorRepo.PurgeDeletedBefore(txCtx, before)
+		if err != nil {
+			return fmt.Errorf("uc.authorRepo.PurgeDeletedBefore: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
+	}
+
+	// covers are files, they are removed only once the books are purged for sure
+	for _, id := range books {
+		if err := uc.coverStorage.Delete(ctx, id); err != nil {
+			uc.l.Error(err, op)
+		}
+	}
+
+	if len(books) > 0 || authors > 0 {
+		uc.l.Info("%s - purged %d books and %d authors", op, len(books), authors)
+	}
+
+	return nil
+}
+
+// recordBookRestore - the revision of a book restored together with its author.
+func (uc *useCase) recordBookRestore(ctx context.Context, user *entity.UserInfoToken, id int64) error {
+	book, err := uc.bookRepo.GetById(ctx, id)
+	if err != nil {
+		return fmt.Errorf("uc.bookRepo.GetById: %w", err)
+	}
+
+	return uc.record(ctx, entity.BookRevision(user, id, entity.RevisionActionRestore, nil, book))
+}
+
+func (uc *useCase) record(ctx context.Context, inp entity.RecordRevisionInput) error {
+	if err := uc.revisionUc.Record(ctx, inp); err != nil {
+		return fmt.Errorf("uc.revisionUc.Record: %w", err)
+	}
+	return nil
+}
@@ -71,21 +71,34 @@ func (uc *useCase) GetUsers(ctx context.Context, filter entity.FilterUserInput)
 	return users, nil
 }
 
+// UpdateUser - applies a merge patch to the user profile.
 func (uc *useCase) UpdateUser(ctx context.Context, inp entity.UpdateUserInput) error {
+	op := "UserUseCase - UpdateUser"
+
+	if err := inp.Validate(); err != nil {
+		return fmt.Errorf("%s - inp.Validate: %w", op, err)
+	}
+	if inp.Locale.Null {
+		inp.Locale = entity.NewPatchField(entity.DefaultLocale)
+	}
+
 	if err := uc.RunInTransaction(ctx, func(txCtx context.Context) error {
-		e := &entity.User{
-			Entity:   entity.Entity{ID: inp.ID},
-			Name:     inp.Name,
-			Surname:  inp.Surname,
-			Username: inp.Username,
-			Rating:   inp.Rating,
-		}
-		if err := uc.repo.Update(txCtx, e); err != nil {
-			return fmt.Errorf("uc.repo.Update: %w", err)
+		if inp.Username.Present {
+			user, err := uc.repo.GetByUserName(txCtx, inp.Username.Value)
+			if err != nil && !errors.Is(err, entity.ErrUserNotFound) {
+				return fmt.Errorf("uc.repo.GetByUserName: %w", err)
+			}
+			if user != nil && user.ID != inp.ID {
+				return entity.ErrUserNameUsed
+			}
+		}
+
+		if err := uc.repo.Patch(txCtx, inp); err != nil {
+			return fmt.Errorf("uc.repo.Patch: %w", err)
 		}
 		return nil
 	}); err != nil {
-		return fmt.Errorf("%s - uc.RunInTransaction: %w", err)
+		return fmt.Errorf("%s - uc.RunInTransaction: %w", op, err)
 	}
 
 	return nil
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table books
+    add column IF NOT EXISTS created_by INTEGER REFERENCES users (id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table books
+    drop column IF EXISTS created_by;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS shelves
+(
+    id           SERIAL PRIMARY KEY,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at   TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    user_id      INTEGER NOT NULL REFERENCES users (id),
+    name         VARCHAR(100) NOT NULL,
+    kind         VARCHAR(100) NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS shelves_user_id_kind_uindex
+    ON shelves (user_id, kind) WHERE kind <> 'CUSTOM' AND deleted_at IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS shelves_user_id_name_uindex
+    ON shelves (user_id, name) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS shelves;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS shelf_books
+(
+    id           SERIAL PRIMARY KEY,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at   TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    shelf_id     INTEGER NOT NULL REFERENCES shelves (id) ON DELETE CASCADE,
+    book_id      INTEGER NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    progress     INTEGER NOT NULL DEFAULT 0,
+    started_at   TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    finished_at  TIMESTAMP WITH TIME ZONE DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS shelf_books_shelf_id_book_id_uindex
+    ON shelf_books (shelf_id, book_id) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS shelf_books;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table books
+    add column IF NOT EXISTS rating_avg NUMERIC(3, 2) NOT NULL DEFAULT 0,
+    add column IF NOT EXISTS rating_sum INTEGER NOT NULL DEFAULT 0,
+    add column IF NOT EXISTS rating_count INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table books
+    drop column IF EXISTS rating_avg,
+    drop column IF EXISTS rating_sum,
+    drop column IF EXISTS rating_count;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS reviews
+(
+    id           SERIAL PRIMARY KEY,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at   TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    book_id      INTEGER NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    user_id      INTEGER NOT NULL REFERENCES users (id),
+    rating       SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    text         TEXT NOT NULL DEFAULT '',
+    status       VARCHAR(100) NOT NULL DEFAULT 'PUBLISHED',
+    flag_count   INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS reviews_book_id_user_id_uindex
+    ON reviews (book_id, user_id) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS reviews_status_index ON reviews (status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS reviews;
+-- +goose StatementEnd
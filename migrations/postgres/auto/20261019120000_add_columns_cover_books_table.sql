@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table books
+    add column IF NOT EXISTS cover_hash VARCHAR(64) DEFAULT NULL,
+    add column IF NOT EXISTS cover_type VARCHAR(100) DEFAULT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table books
+    drop column IF EXISTS cover_hash,
+    drop column IF EXISTS cover_type;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS revisions
+(
+    id           SERIAL PRIMARY KEY,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    entity_type  VARCHAR(100) NOT NULL,
+    entity_id    VARCHAR(100) NOT NULL,
+    action       VARCHAR(100) NOT NULL,
+    actor_id     INTEGER REFERENCES users (id),
+    before       JSONB DEFAULT NULL,
+    after        JSONB DEFAULT NULL,
+    diff         JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX IF NOT EXISTS revisions_entity_type_entity_id_index
+    ON revisions (entity_type, entity_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS revisions;
+-- +goose StatementEnd
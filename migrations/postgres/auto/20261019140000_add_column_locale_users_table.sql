@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table users
+    add column IF NOT EXISTS locale VARCHAR(35) NOT NULL DEFAULT 'en';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table users
+    drop column IF EXISTS locale;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS command_translations
+(
+    id           SERIAL PRIMARY KEY,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    command_id   INTEGER NOT NULL REFERENCES commands (id) ON DELETE CASCADE,
+    locale       VARCHAR(35) NOT NULL,
+    name         VARCHAR(100) NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS command_translations_command_id_locale_uindex
+    ON command_translations (command_id, locale);
+
+INSERT INTO command_translations (command_id, locale, name)
+SELECT id, 'en', name
+FROM commands
+WHERE name IS NOT NULL
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS command_translations;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS command_catalog_versions
+(
+    id           SERIAL PRIMARY KEY,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    checksum     VARCHAR(64) NOT NULL,
+    author_id    INTEGER REFERENCES users (id),
+    is_active    BOOLEAN NOT NULL DEFAULT FALSE,
+    commands     JSONB NOT NULL DEFAULT '[]'
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS command_catalog_versions_is_active_uindex
+    ON command_catalog_versions (is_active)
+    WHERE is_active;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS command_catalog_versions;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table operations
+    add column IF NOT EXISTS catalog_version_id INTEGER REFERENCES command_catalog_versions (id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table operations
+    drop column IF EXISTS catalog_version_id;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table commands
+    add column IF NOT EXISTS is_disabled BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table commands
+    drop column IF EXISTS is_disabled;
+-- +goose StatementEnd
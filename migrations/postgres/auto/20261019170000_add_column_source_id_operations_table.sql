@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table operations
+    add column IF NOT EXISTS source_id VARCHAR(24);
+
+CREATE UNIQUE INDEX IF NOT EXISTS operations_source_id_uindex
+    ON operations (source_id)
+    WHERE source_id IS NOT NULL AND deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS operations_source_id_uindex;
+
+alter table operations
+    drop column IF EXISTS source_id;
+-- +goose StatementEnd
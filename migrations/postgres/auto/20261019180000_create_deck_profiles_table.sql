@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS deck_profiles
+(
+    id           SERIAL PRIMARY KEY,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    name         VARCHAR(255) NOT NULL UNIQUE,
+    is_default   BOOLEAN NOT NULL DEFAULT FALSE,
+    positions    JSONB NOT NULL DEFAULT '[]'
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS deck_profiles_is_default_uindex
+    ON deck_profiles (is_default)
+    WHERE is_default;
+
+INSERT INTO deck_profiles (name, is_default, positions)
+VALUES ('standard', TRUE, '[
+  {"address": "RA", "capacity": 5000, "group": "large"},
+  {"address": "RB", "capacity": 5000, "group": "large"},
+  {"address": "RC", "capacity": 5000, "group": "large"},
+  {"address": "RD", "capacity": 5000, "group": "large"},
+  {"address": "SA", "capacity": 200, "group": "small"},
+  {"address": "SB", "capacity": 200, "group": "small"},
+  {"address": "SC", "capacity": 200, "group": "small"},
+  {"address": "SD", "capacity": 200, "group": "small"},
+  {"address": "SE", "capacity": 200, "group": "small"},
+  {"address": "SF", "capacity": 200, "group": "small"},
+  {"address": "SG", "capacity": 200, "group": "small"},
+  {"address": "SH", "capacity": 200, "group": "small"}
+]')
+ON CONFLICT (name) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS deck_profiles;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table operations
+    add column IF NOT EXISTS deck_profile_id INTEGER REFERENCES deck_profiles (id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table operations
+    drop column IF EXISTS deck_profile_id;
+-- +goose StatementEnd
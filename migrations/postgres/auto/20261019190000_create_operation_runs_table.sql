@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS operation_runs
+(
+    id             SERIAL PRIMARY KEY,
+    created_at     TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at     TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    operation_id   VARCHAR(24) NOT NULL,
+    operation_name VARCHAR(255) NOT NULL,
+    state          VARCHAR(16) NOT NULL,
+    current_step   INTEGER NOT NULL DEFAULT 0,
+    started_by     INTEGER REFERENCES users (id),
+    started_at     TIMESTAMP WITH TIME ZONE,
+    finished_at    TIMESTAMP WITH TIME ZONE,
+    error          TEXT NOT NULL DEFAULT '',
+    steps          JSONB NOT NULL DEFAULT '[]'
+);
+
+CREATE INDEX IF NOT EXISTS operation_runs_operation_id_index
+    ON operation_runs (operation_id);
+
+CREATE INDEX IF NOT EXISTS operation_runs_state_index
+    ON operation_runs (state);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS operation_runs;
+-- +goose StatementEnd
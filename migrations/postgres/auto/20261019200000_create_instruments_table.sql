@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS instruments
+(
+    id              SERIAL PRIMARY KEY,
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    name            VARCHAR(255) NOT NULL UNIQUE,
+    deck_profile_id INTEGER REFERENCES deck_profiles (id) ON DELETE SET NULL
+);
+
+INSERT INTO instruments (name)
+VALUES ('instrument-1')
+ON CONFLICT (name) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS instruments;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table operation_runs
+    add column IF NOT EXISTS instrument_id  INTEGER REFERENCES instruments (id),
+    add column IF NOT EXISTS priority       INTEGER NOT NULL DEFAULT 0,
+    add column IF NOT EXISTS queue_position INTEGER NOT NULL DEFAULT 0,
+    add column IF NOT EXISTS average_time   BIGINT NOT NULL DEFAULT 0;
+
+-- runs started before instruments existed belong to the first one
+update operation_runs
+set instrument_id = (SELECT id FROM instruments ORDER BY id LIMIT 1)
+where instrument_id IS NULL;
+
+alter table operation_runs
+    alter column instrument_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS operation_runs_instrument_id_state_index
+    ON operation_runs (instrument_id, state);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS operation_runs_instrument_id_state_index;
+
+alter table operation_runs
+    drop column IF EXISTS instrument_id,
+    drop column IF EXISTS priority,
+    drop column IF EXISTS queue_position,
+    drop column IF EXISTS average_time;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a profile in use is refused instead of moving its operations and instruments to the default deck
+alter table operations
+    drop constraint IF EXISTS operations_deck_profile_id_fkey,
+    add constraint operations_deck_profile_id_fkey
+        FOREIGN KEY (deck_profile_id) REFERENCES deck_profiles (id) ON DELETE RESTRICT;
+
+alter table instruments
+    drop constraint IF EXISTS instruments_deck_profile_id_fkey,
+    add constraint instruments_deck_profile_id_fkey
+        FOREIGN KEY (deck_profile_id) REFERENCES deck_profiles (id) ON DELETE RESTRICT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table operations
+    drop constraint IF EXISTS operations_deck_profile_id_fkey,
+    add constraint operations_deck_profile_id_fkey
+        FOREIGN KEY (deck_profile_id) REFERENCES deck_profiles (id) ON DELETE SET NULL;
+
+alter table instruments
+    drop constraint IF EXISTS instruments_deck_profile_id_fkey,
+    add constraint instruments_deck_profile_id_fkey
+        FOREIGN KEY (deck_profile_id) REFERENCES deck_profiles (id) ON DELETE SET NULL;
+-- +goose StatementEnd